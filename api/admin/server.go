@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"vk-test-task/pkg/health"
+	"vk-test-task/pkg/logger"
+)
+
+// Server is the admin listener. It is kept apart from the API so that
+// operational endpoints are not exposed together with it.
+type Server struct {
+	host    string
+	server  *http.Server
+	checker *health.Checker
+}
+
+// New serves metricsHandler on /metrics, see metrics.Handler, the liveness on /healthz,
+// the readiness by the checks of checker on /readyz and the log level on /log/level.
+func New(host string, checker *health.Checker, metricsHandler http.Handler) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metricsHandler)
+	mux.Handle("/healthz", checker.LiveHandler())
+	mux.Handle("/readyz", checker.ReadyHandler())
+	mux.Handle("/log/level", logger.LevelHandler())
+
+	return &Server{
+		host:    host,
+		checker: checker,
+		server: &http.Server{
+			Addr:    host,
+			Handler: mux,
+		},
+	}
+}
+
+// Drain reports the service as not ready for the rest of its life.
+func (s *Server) Drain() {
+	s.checker.Drain()
+}
+
+// Run serves the admin endpoints until Shutdown is called.
+func (s *Server) Run() error {
+	if err := s.server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// Handler returns the router of the admin endpoints, e.g. to serve it from httptest.
+func (s *Server) Handler() http.Handler {
+	return s.server.Handler
+}
+
+func (s *Server) GetAddr() string {
+	return s.host
+}
@@ -1,17 +1,112 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"vk-test-task/api/admin"
+	"vk-test-task/api/grpc"
 	"vk-test-task/api/rest/handlers"
+	"vk-test-task/pkg/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Container struct {
-	Resolver *handlers.Resolver
+	Resolver    *handlers.Resolver
+	GRPCServer  *grpc.Server
+	AdminServer *admin.Server
+	DB          *pgxpool.Pool
 }
 
 func NewContainer(
 	resolver *handlers.Resolver,
+	grpcServer *grpc.Server,
+	adminServer *admin.Server,
+	db *pgxpool.Pool,
 ) Container {
 	return Container{
-		Resolver: resolver,
+		Resolver:    resolver,
+		GRPCServer:  grpcServer,
+		AdminServer: adminServer,
+		DB:          db,
 	}
 }
+
+type server interface {
+	Run() error
+	Shutdown(context.Context) error
+	GetAddr() string
+}
+
+// Run serves the REST, gRPC and admin servers until ctx is done or one of them fails
+// to start, then shuts them down:
+//   - the service is reported as not ready on /readyz;
+//   - the REST and gRPC servers stop accepting connections and the requests in flight
+//     are given drainTimeout to complete;
+//   - the admin server is stopped and the database pool is closed.
+//
+// The error of a server that failed to start is returned.
+func (c Container) Run(ctx context.Context, drainTimeout time.Duration) error {
+	apis := map[string]server{"rest": c.Resolver, "grpc": c.GRPCServer}
+
+	errs := make(chan error, len(apis)+1)
+	serve := func(name string, s server) {
+		logger.Log.Info(name+" server started", "address", s.GetAddr())
+		if err := s.Run(); err != nil {
+			errs <- err
+			return
+		}
+		logger.Log.Info(name + " server stopped")
+	}
+
+	go serve("admin", c.AdminServer)
+	for name, s := range apis {
+		go serve(name, s)
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		logger.Log.Info("shutting down", "timeout", drainTimeout)
+	case runErr = <-errs:
+		logger.Log.Error("server failed, shutting down", "error", runErr.Error())
+	}
+
+	c.AdminServer.Drain()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	shutdownErrs := make(chan error, len(apis))
+	for name, s := range apis {
+		go func(name string, s server) {
+			if err := s.Shutdown(shutdownCtx); err != nil {
+				shutdownErrs <- fmt.Errorf("%s server shutdown: %w", name, err)
+				return
+			}
+			shutdownErrs <- nil
+		}(name, s)
+	}
+
+	var shutdownErr error
+	for range apis {
+		shutdownErr = errors.Join(shutdownErr, <-shutdownErrs)
+	}
+
+	if err := c.AdminServer.Shutdown(shutdownCtx); err != nil {
+		shutdownErr = errors.Join(shutdownErr, fmt.Errorf("admin server shutdown: %w", err))
+	}
+
+	if c.DB != nil {
+		c.DB.Close()
+	}
+
+	if runErr != nil {
+		return runErr
+	}
+
+	return shutdownErr
+}
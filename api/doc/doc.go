@@ -0,0 +1,26 @@
+// Package doc embeds the OpenAPI document of the REST API and the Swagger UI page
+// that renders it.
+package doc
+
+import (
+	_ "embed"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// OpenAPI is the OpenAPI 3 document, served at /api/v1/openapi.json.
+//
+//go:embed swagger.json
+var OpenAPI []byte
+
+// SwaggerUI is the page served at /api/v1/docs. It loads a pinned version of
+// Swagger UI from a CDN (see the swagger-ui-sri make target) and points it at
+// openapi.json next to it.
+//
+//go:embed swagger-ui.html
+var SwaggerUI []byte
+
+// Load parses the OpenAPI document.
+func Load() (*openapi3.T, error) {
+	return openapi3.NewLoader().LoadFromData(OpenAPI)
+}
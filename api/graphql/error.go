@@ -0,0 +1,101 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/web"
+	"vk-test-task/pkg/webutil"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/jackc/pgx/v5"
+)
+
+// Error carries the same msg codes as REST responses in the error extensions.
+type Error struct {
+	MsgCode string
+	Errors  []web.ValidationError
+}
+
+func (e *Error) Error() string {
+	return e.MsgCode
+}
+
+func (e *Error) Extensions() map[string]any {
+	extensions := map[string]any{"msg_code": e.MsgCode}
+	if len(e.Errors) != 0 {
+		extensions["errors"] = e.Errors
+	}
+
+	return extensions
+}
+
+func requireRole(ctx context.Context, allowedRoles ...string) error {
+	role, ok := webutil.RoleFromContext(ctx)
+	if !ok {
+		return &Error{MsgCode: core.UnauthorizedCode}
+	}
+
+	for _, allowedRole := range allowedRoles {
+		if role == allowedRole {
+			return nil
+		}
+	}
+
+	return &Error{MsgCode: core.ForbiddenErrorCode}
+}
+
+func validate(model any) error {
+	verrors, err := webutil.StructErrors(model)
+	if err != nil {
+		logger.Log.Debug("error validation", "error", err.Error())
+		return &Error{MsgCode: core.InternalErrorCode}
+	}
+	if len(verrors) != 0 {
+		return &Error{MsgCode: core.ValidationCode, Errors: verrors}
+	}
+
+	return nil
+}
+
+func parseID(id graphql.ID) (int, error) {
+	num, err := strconv.Atoi(string(id))
+	if err != nil || num <= 0 {
+		return 0, &Error{MsgCode: core.InvalidIDCode}
+	}
+
+	return num, nil
+}
+
+func parseIDs(ids []graphql.ID) ([]int, error) {
+	nums := make([]int, len(ids))
+	for i, id := range ids {
+		num, err := parseID(id)
+		if err != nil {
+			return nil, err
+		}
+		nums[i] = num
+	}
+
+	return nums, nil
+}
+
+// serviceError maps service errors to msg codes, notFoundCode is used for pgx.ErrNoRows.
+func serviceError(err error, notFoundCode string) error {
+	switch {
+	case errors.Is(err, core.ErrStarIDNotExists):
+		return &Error{MsgCode: core.StarNotFoundCode}
+	case errors.Is(err, pgx.ErrNoRows):
+		return &Error{MsgCode: notFoundCode}
+	case errors.Is(err, context.Canceled):
+		return &Error{MsgCode: core.RequestCanceledCode}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Error{MsgCode: core.QueryTimeoutCode}
+	default:
+		logger.Log.Error("graphql", "error", err.Error())
+		return &Error{MsgCode: core.InternalErrorCode}
+	}
+}
@@ -0,0 +1,72 @@
+package graphql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/web"
+	"vk-test-task/pkg/webutil"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// maxDepth stops queries like movie.stars.movies.stars... from fanning out without limit.
+const maxDepth = 8
+
+//go:embed schema.graphql
+var schemaString string
+
+type (
+	Handler struct {
+		schema *graphql.Schema
+	}
+
+	request struct {
+		Query         string         `json:"query"`
+		OperationName string         `json:"operationName"`
+		Variables     map[string]any `json:"variables"`
+	}
+)
+
+// New returns the GraphQL endpoint. It expects the user role in the request
+// context, so it has to be mounted behind the JWT middleware.
+func New(filmotekaService filmoteka.Service) *Handler {
+	return &Handler{
+		schema: graphql.MustParseSchema(schemaString, &rootResolver{service: filmotekaService}, graphql.MaxDepth(maxDepth)),
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		webutil.SendJSONResponse(w, http.StatusMethodNotAllowed, web.ErrorResponse(core.UnsupportedMethodCode, nil, nil))
+		return
+	}
+
+	var body request
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		webutil.SendBodyError(w, err)
+		return
+	}
+	if body.Query == "" {
+		webutil.SendJSONResponse(w, http.StatusBadRequest, web.ErrorResponse(core.InvalidBodyCode, nil, nil))
+		return
+	}
+
+	resp := h.schema.Exec(req.Context(), body.Query, body.OperationName, body.Variables)
+
+	// Errors are reported in the response body, the status is always 200 as GraphQL clients expect
+	data, err := json.Marshal(resp)
+	if err != nil {
+		logger.Log.ErrorContext(req.Context(), "marshal graphql response", "error", err.Error())
+		webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data) //nolint
+}
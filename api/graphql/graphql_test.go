@@ -0,0 +1,167 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/internal/store/star"
+)
+
+// fakeService serves a fixed catalogue and counts the batch lookups.
+type fakeService struct {
+	filmoteka.Service
+	movies      []movie.Entity
+	stars       []star.Entity
+	credits     map[int][]int // movie id -> star ids
+	starsCalls  int
+	moviesCalls int
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{
+		movies: []movie.Entity{
+			{ID: 1, Title: "Pulp Fiction", Rating: 9},
+			{ID: 2, Title: "Jackie Brown", Rating: 8},
+		},
+		stars: []star.Entity{
+			{ID: 1, Name: "Samuel L. Jackson", Sex: "male"},
+			{ID: 2, Name: "Uma Thurman", Sex: "female"},
+		},
+		credits: map[int][]int{1: {1, 2}, 2: {1}},
+	}
+}
+
+func (s *fakeService) GetMovies(_ context.Context, _ filmoteka.GetMoviesModel) ([]movie.Entity, int, error) {
+	return s.movies, len(s.movies), nil
+}
+
+func (s *fakeService) GetStarsByMovieIDs(_ context.Context, ids []int) (map[int][]star.Entity, error) {
+	s.starsCalls++
+
+	result := map[int][]star.Entity{}
+	for _, id := range ids {
+		for _, starID := range s.credits[id] {
+			result[id] = append(result[id], s.stars[starID-1])
+		}
+	}
+
+	return result, nil
+}
+
+func (s *fakeService) GetMoviesByStarIDs(_ context.Context, ids []int) (map[int][]movie.Entity, error) {
+	s.moviesCalls++
+
+	result := map[int][]movie.Entity{}
+	for _, id := range ids {
+		for _, m := range s.movies {
+			for _, starID := range s.credits[m.ID] {
+				if starID == id {
+					result[id] = append(result[id], m)
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func execute(t *testing.T, service filmoteka.Service, role, query string) response {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", bytes.NewReader(body))
+	req = req.WithContext(context.WithValue(req.Context(), "user_role", role)) //nolint
+	rec := httptest.NewRecorder()
+
+	New(service).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var resp response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	return resp
+}
+
+func TestNestedQueryBatchesLookups(t *testing.T) {
+	service := newFakeService()
+
+	resp := execute(t, service, core.UserRole, `{
+		movies {
+			items { title stars { name movies { title stars { name } } } }
+			pagination { totalCount }
+		}
+	}`)
+	if len(resp.Errors) != 0 {
+		t.Fatalf("unexpected errors: %+v", resp.Errors)
+	}
+
+	// One lookup per nesting level, not per movie or star
+	if service.starsCalls != 2 || service.moviesCalls != 1 {
+		t.Errorf("expected 2 stars and 1 movies lookups, got %d and %d", service.starsCalls, service.moviesCalls)
+	}
+
+	var data struct {
+		Movies struct {
+			Items []struct {
+				Title string
+				Stars []struct {
+					Name   string
+					Movies []struct{ Title string }
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	items := data.Movies.Items
+	if len(items) != 2 || len(items[0].Stars) != 2 || len(items[1].Stars) != 1 {
+		t.Fatalf("unexpected movies %+v", items)
+	}
+	if got := len(items[0].Stars[0].Movies); got != 2 {
+		t.Errorf("expected 2 movies of %s, got %d", items[0].Stars[0].Name, got)
+	}
+}
+
+func TestMutationRequiresAdmin(t *testing.T) {
+	resp := execute(t, newFakeService(), core.UserRole, `mutation { deleteMovie(id: "1") }`)
+
+	if len(resp.Errors) != 1 {
+		t.Fatalf("expected 1 error, got %+v", resp.Errors)
+	}
+	if code := resp.Errors[0].Extensions["msg_code"]; code != core.ForbiddenErrorCode {
+		t.Errorf("expected msg_code %s, got %v", core.ForbiddenErrorCode, code)
+	}
+}
+
+func TestQueryValidation(t *testing.T) {
+	resp := execute(t, newFakeService(), core.AdminRole, `{ movies(sort: "budget,desc") { items { title } } }`)
+
+	if len(resp.Errors) != 1 {
+		t.Fatalf("expected 1 error, got %+v", resp.Errors)
+	}
+	if code := resp.Errors[0].Extensions["msg_code"]; code != core.ValidationCode {
+		t.Errorf("expected msg_code %s, got %v", core.ValidationCode, code)
+	}
+}
@@ -0,0 +1,339 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/internal/store/star"
+	"vk-test-task/pkg/web"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type (
+	rootResolver struct {
+		service filmoteka.Service
+	}
+
+	idArgs struct {
+		ID graphql.ID
+	}
+
+	moviesArgs struct {
+		Q     *string
+		Sort  *string
+		Page  *int32
+		Limit *int32
+	}
+
+	starsArgs struct {
+		Q     *string
+		Page  *int32
+		Limit *int32
+	}
+
+	createMovieArgs struct {
+		Input struct {
+			Title       string
+			Description string
+			ReleaseDate graphql.Time
+			Rating      int32
+			StarsID     []graphql.ID
+		}
+	}
+
+	updateMovieArgs struct {
+		ID    graphql.ID
+		Input struct {
+			Title       *string
+			Description *string
+			ReleaseDate *graphql.Time
+			Rating      *int32
+			StarsID     *[]graphql.ID
+		}
+	}
+
+	createStarArgs struct {
+		Input struct {
+			Name      string
+			Sex       string
+			BirthDate graphql.Time
+		}
+	}
+
+	updateStarArgs struct {
+		ID    graphql.ID
+		Input struct {
+			Name      *string
+			Sex       *string
+			BirthDate *graphql.Time
+		}
+	}
+)
+
+func (r *rootResolver) Movies(ctx context.Context, args moviesArgs) (*moviesPageResolver, error) {
+	if err := requireRole(ctx, core.AdminRole, core.UserRole); err != nil {
+		return nil, err
+	}
+
+	var model filmoteka.GetMoviesModel
+	model.PaginationQuery = paginationArgs(args.Page, args.Limit)
+	if args.Q != nil {
+		model.SearchTerm = *args.Q
+	}
+	if args.Sort != nil {
+		model.Sort = *args.Sort
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	data, total, err := r.service.GetMovies(ctx, model)
+	if err != nil {
+		return nil, serviceError(err, core.MovieNotFoundCode)
+	}
+
+	return &moviesPageResolver{
+		items:      newMovieResolvers(r.service, data),
+		pagination: model.PaginationBody(total),
+	}, nil
+}
+
+func (r *rootResolver) Movie(ctx context.Context, args idArgs) (*movieResolver, error) {
+	if err := requireRole(ctx, core.AdminRole, core.UserRole); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := r.service.GetMovieByID(ctx, id)
+	if err != nil {
+		return nil, serviceError(err, core.MovieNotFoundCode)
+	}
+
+	return newMovieResolvers(r.service, []movie.Entity{data})[0], nil
+}
+
+func (r *rootResolver) Stars(ctx context.Context, args starsArgs) (*starsPageResolver, error) {
+	if err := requireRole(ctx, core.AdminRole, core.UserRole); err != nil {
+		return nil, err
+	}
+
+	var model filmoteka.GetStarsModel
+	model.PaginationQuery = paginationArgs(args.Page, args.Limit)
+	if args.Q != nil {
+		model.SearchTerm = *args.Q
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	data, total, err := r.service.GetStars(ctx, model)
+	if err != nil {
+		return nil, serviceError(err, core.StarNotFoundCode)
+	}
+
+	return &starsPageResolver{
+		items:      newStarResolvers(r.service, data),
+		pagination: model.PaginationBody(total),
+	}, nil
+}
+
+func (r *rootResolver) Star(ctx context.Context, args idArgs) (*starResolver, error) {
+	if err := requireRole(ctx, core.AdminRole, core.UserRole); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, movies, err := r.service.GetStarByID(ctx, id)
+	if err != nil {
+		return nil, serviceError(err, core.StarNotFoundCode)
+	}
+
+	resolver := newStarResolvers(r.service, []star.Entity{data})[0]
+	resolver.group.prime(id, movies)
+
+	return resolver, nil
+}
+
+func (r *rootResolver) CreateMovie(ctx context.Context, args createMovieArgs) (*movieResolver, error) {
+	if err := requireRole(ctx, core.AdminRole); err != nil {
+		return nil, err
+	}
+
+	starsID, err := parseIDs(args.Input.StarsID)
+	if err != nil {
+		return nil, err
+	}
+
+	model := filmoteka.CreateMovieModel{
+		Title:       args.Input.Title,
+		Description: args.Input.Description,
+		ReleaseDate: args.Input.ReleaseDate.Format(time.RFC3339),
+		Rating:      int(args.Input.Rating),
+		StarsID:     starsID,
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	data, err := r.service.CreateMovie(ctx, model)
+	if err != nil {
+		return nil, serviceError(err, core.MovieNotFoundCode)
+	}
+
+	return newMovieResolvers(r.service, []movie.Entity{data})[0], nil
+}
+
+func (r *rootResolver) UpdateMovie(ctx context.Context, args updateMovieArgs) (*movieResolver, error) {
+	if err := requireRole(ctx, core.AdminRole); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	model := filmoteka.UpdateMovieModel{
+		Title:       args.Input.Title,
+		Description: args.Input.Description,
+		ReleaseDate: formatTime(args.Input.ReleaseDate),
+	}
+	if args.Input.Rating != nil {
+		rating := int(*args.Input.Rating)
+		model.Rating = &rating
+	}
+	if args.Input.StarsID != nil {
+		if model.StarsID, err = parseIDs(*args.Input.StarsID); err != nil {
+			return nil, err
+		}
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	data, err := r.service.UpdateMovie(ctx, id, model)
+	if err != nil {
+		return nil, serviceError(err, core.MovieNotFoundCode)
+	}
+
+	return newMovieResolvers(r.service, []movie.Entity{data})[0], nil
+}
+
+func (r *rootResolver) DeleteMovie(ctx context.Context, args idArgs) (bool, error) {
+	if err := requireRole(ctx, core.AdminRole); err != nil {
+		return false, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.service.DeleteMovie(ctx, id); err != nil {
+		return false, serviceError(err, core.MovieNotFoundCode)
+	}
+
+	return true, nil
+}
+
+func (r *rootResolver) CreateStar(ctx context.Context, args createStarArgs) (*starResolver, error) {
+	if err := requireRole(ctx, core.AdminRole); err != nil {
+		return nil, err
+	}
+
+	model := filmoteka.CreateStarModel{
+		Name:      args.Input.Name,
+		Sex:       args.Input.Sex,
+		BirthDate: args.Input.BirthDate.Format(time.RFC3339),
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	data, err := r.service.CreateStar(ctx, model)
+	if err != nil {
+		return nil, serviceError(err, core.StarNotFoundCode)
+	}
+
+	return newStarResolvers(r.service, []star.Entity{data})[0], nil
+}
+
+func (r *rootResolver) UpdateStar(ctx context.Context, args updateStarArgs) (*starResolver, error) {
+	if err := requireRole(ctx, core.AdminRole); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	model := filmoteka.UpdateStarModel{
+		Name:      args.Input.Name,
+		Sex:       args.Input.Sex,
+		BirthDate: formatTime(args.Input.BirthDate),
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	data, movies, err := r.service.UpdateStar(ctx, id, model)
+	if err != nil {
+		return nil, serviceError(err, core.StarNotFoundCode)
+	}
+
+	resolver := newStarResolvers(r.service, []star.Entity{data})[0]
+	resolver.group.prime(id, movies)
+
+	return resolver, nil
+}
+
+func (r *rootResolver) DeleteStar(ctx context.Context, args idArgs) (bool, error) {
+	if err := requireRole(ctx, core.AdminRole); err != nil {
+		return false, err
+	}
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.service.DeleteStar(ctx, id); err != nil {
+		return false, serviceError(err, core.StarNotFoundCode)
+	}
+
+	return true, nil
+}
+
+func paginationArgs(page, limit *int32) web.PaginationQuery {
+	var pq web.PaginationQuery
+	if page != nil {
+		pq.Page = int(*page)
+	}
+	if limit != nil {
+		pq.Limit = int(*limit)
+	}
+
+	return pq
+}
+
+func formatTime(t *graphql.Time) *string {
+	if t == nil {
+		return nil
+	}
+
+	formatted := t.Format(time.RFC3339)
+	return &formatted
+}
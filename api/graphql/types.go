@@ -0,0 +1,273 @@
+package graphql
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/internal/store/star"
+	"vk-test-task/pkg/web"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type (
+	movieResolver struct {
+		entity movie.Entity
+		group  *movieGroup
+	}
+
+	starResolver struct {
+		entity star.Entity
+		group  *starGroup
+	}
+
+	// movieGroup holds movies resolved by one field, e.g. a page of movies or the movies
+	// of every star on a page. The first movie asking for its stars loads the stars of
+	// the whole group with one query, like a DataLoader batch. The loaded stars form the
+	// next group, so a query of any depth costs one query per level instead of one per item.
+	movieGroup struct {
+		service filmoteka.Service
+		ids     []int
+		once    sync.Once
+		stars   map[int][]*starResolver
+		err     error
+	}
+
+	starGroup struct {
+		service filmoteka.Service
+		ids     []int
+		once    sync.Once
+		movies  map[int][]*movieResolver
+		err     error
+	}
+
+	paginationResolver struct {
+		body web.PaginationBody
+	}
+
+	moviesPageResolver struct {
+		items      []*movieResolver
+		pagination web.PaginationBody
+	}
+
+	starsPageResolver struct {
+		items      []*starResolver
+		pagination web.PaginationBody
+	}
+)
+
+func newMovieResolvers(service filmoteka.Service, entities []movie.Entity) []*movieResolver {
+	group := &movieGroup{service: service}
+
+	resolvers := make([]*movieResolver, len(entities))
+	for i, entity := range entities {
+		group.ids = append(group.ids, entity.ID)
+		resolvers[i] = &movieResolver{entity: entity, group: group}
+	}
+
+	return resolvers
+}
+
+func newStarResolvers(service filmoteka.Service, entities []star.Entity) []*starResolver {
+	group := &starGroup{service: service}
+
+	resolvers := make([]*starResolver, len(entities))
+	for i, entity := range entities {
+		group.ids = append(group.ids, entity.ID)
+		resolvers[i] = &starResolver{entity: entity, group: group}
+	}
+
+	return resolvers
+}
+
+func (g *movieGroup) starsOf(ctx context.Context, movieID int) ([]*starResolver, error) {
+	g.once.Do(func() {
+		data, err := g.service.GetStarsByMovieIDs(ctx, g.ids)
+		if err != nil {
+			g.err = serviceError(err, core.StarNotFoundCode)
+			return
+		}
+
+		g.stars = make(map[int][]*starResolver, len(data))
+		next := &starGroup{service: g.service}
+		resolvers := map[int]*starResolver{}
+		for id, entities := range data {
+			for _, entity := range entities {
+				resolver, ok := resolvers[entity.ID]
+				if !ok {
+					resolver = &starResolver{entity: entity, group: next}
+					resolvers[entity.ID] = resolver
+					next.ids = append(next.ids, entity.ID)
+				}
+				g.stars[id] = append(g.stars[id], resolver)
+			}
+		}
+	})
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	if stars, ok := g.stars[movieID]; ok {
+		return stars, nil
+	}
+	return []*starResolver{}, nil
+}
+
+// prime fills the group with movies that are already loaded for a star.
+func (g *starGroup) prime(starID int, movies []movie.Entity) {
+	g.once.Do(func() {
+		g.movies = map[int][]*movieResolver{
+			starID: newMovieResolvers(g.service, movies),
+		}
+	})
+}
+
+func (g *starGroup) moviesOf(ctx context.Context, starID int) ([]*movieResolver, error) {
+	g.once.Do(func() {
+		data, err := g.service.GetMoviesByStarIDs(ctx, g.ids)
+		if err != nil {
+			g.err = serviceError(err, core.MovieNotFoundCode)
+			return
+		}
+
+		g.movies = make(map[int][]*movieResolver, len(data))
+		next := &movieGroup{service: g.service}
+		resolvers := map[int]*movieResolver{}
+		for id, entities := range data {
+			for _, entity := range entities {
+				resolver, ok := resolvers[entity.ID]
+				if !ok {
+					resolver = &movieResolver{entity: entity, group: next}
+					resolvers[entity.ID] = resolver
+					next.ids = append(next.ids, entity.ID)
+				}
+				g.movies[id] = append(g.movies[id], resolver)
+			}
+		}
+	})
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	if movies, ok := g.movies[starID]; ok {
+		return movies, nil
+	}
+	return []*movieResolver{}, nil
+}
+
+func (r *movieResolver) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(r.entity.ID))
+}
+
+func (r *movieResolver) Title() string {
+	return r.entity.Title
+}
+
+func (r *movieResolver) Description() string {
+	return r.entity.Description
+}
+
+func (r *movieResolver) ReleaseDate() graphql.Time {
+	return graphql.Time{Time: r.entity.ReleaseDate}
+}
+
+func (r *movieResolver) Rating() int32 {
+	return int32(r.entity.Rating)
+}
+
+func (r *movieResolver) Locale() *string {
+	if r.entity.Locale == "" {
+		return nil
+	}
+	return &r.entity.Locale
+}
+
+func (r *movieResolver) MatchedAlias() *string {
+	if r.entity.MatchedAlias == "" {
+		return nil
+	}
+	return &r.entity.MatchedAlias
+}
+
+func (r *movieResolver) Stars(ctx context.Context) ([]*starResolver, error) {
+	return r.group.starsOf(ctx, r.entity.ID)
+}
+
+func (r *movieResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.entity.CreatedAt}
+}
+
+func (r *movieResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.entity.UpdatedAt}
+}
+
+func (r *starResolver) ID() graphql.ID {
+	return graphql.ID(strconv.Itoa(r.entity.ID))
+}
+
+func (r *starResolver) Name() string {
+	return r.entity.Name
+}
+
+func (r *starResolver) Sex() string {
+	return r.entity.Sex
+}
+
+func (r *starResolver) BirthDate() graphql.Time {
+	return graphql.Time{Time: r.entity.BirthDate}
+}
+
+func (r *starResolver) MatchedAlias() *string {
+	if r.entity.MatchedAlias == "" {
+		return nil
+	}
+	return &r.entity.MatchedAlias
+}
+
+func (r *starResolver) Movies(ctx context.Context) ([]*movieResolver, error) {
+	return r.group.moviesOf(ctx, r.entity.ID)
+}
+
+func (r *starResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.entity.CreatedAt}
+}
+
+func (r *starResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.entity.UpdatedAt}
+}
+
+func (r *paginationResolver) TotalCount() int32 {
+	return int32(r.body.TotalCount)
+}
+
+func (r *paginationResolver) PageCount() int32 {
+	return int32(r.body.PageCount)
+}
+
+func (r *paginationResolver) CurrentPage() int32 {
+	return int32(r.body.CurrentPage)
+}
+
+func (r *paginationResolver) PerPage() int32 {
+	return int32(r.body.PerPage)
+}
+
+func (r *moviesPageResolver) Items() []*movieResolver {
+	return r.items
+}
+
+func (r *moviesPageResolver) Pagination() *paginationResolver {
+	return &paginationResolver{body: r.pagination}
+}
+
+func (r *starsPageResolver) Items() []*starResolver {
+	return r.items
+}
+
+func (r *starsPageResolver) Pagination() *paginationResolver {
+	return &paginationResolver{body: r.pagination}
+}
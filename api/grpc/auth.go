@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"vk-test-task/api/grpc/pb"
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/auth"
+	"vk-test-task/pkg/hash"
+	"vk-test-task/pkg/metrics"
+	"vk-test-task/pkg/ratelimit"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type authServer struct {
+	pb.UnimplementedAuthServiceServer
+	service auth.Service
+	lockout *ratelimit.Lockout
+}
+
+func (s *authServer) SignUp(ctx context.Context, req *pb.SignUpRequest) (*pb.User, error) {
+	model := auth.SignUpModel{
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+		Role:     req.GetRole(),
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	data, err := s.service.SignUp(ctx, model)
+	if err != nil {
+		return nil, serviceError(err, core.InternalErrorCode)
+	}
+
+	return &pb.User{
+		Id:       int64(data.ID),
+		Username: data.Username,
+		Role:     data.Role,
+	}, nil
+}
+
+func (s *authServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	model := auth.LoginModel{
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	if left, locked := s.lockout.Locked(model.Username); locked {
+		metrics.Login(metrics.LoginLocked)
+		return nil, lockedError(left)
+	}
+
+	passHash, role, err := s.service.GetPassHashAndRoleByUsername(ctx, model.Username)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, serviceError(err, core.WrongCredentialsCode)
+	}
+	if err != nil || passHash != hash.CalculateHash(model.Password) {
+		metrics.Login(metrics.LoginFailure)
+		s.lockout.Fail(model.Username)
+		return nil, status.Error(codes.Unauthenticated, core.WrongCredentialsCode)
+	}
+	s.lockout.Succeed(model.Username)
+
+	token, err := s.service.CreateToken(ctx, model.Username, role)
+	if err != nil {
+		return nil, serviceError(err, core.InternalErrorCode)
+	}
+
+	metrics.Login(metrics.LoginSuccess)
+	return &pb.LoginResponse{AccessToken: token.AccessToken}, nil
+}
@@ -0,0 +1,121 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/webutil"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// validate checks a filmoteka model with the REST rules. Failed rules are sent
+// as a BadRequest detail, one field violation per rule.
+func validate(model any) error {
+	verrors, err := webutil.StructErrors(model)
+	if err != nil {
+		logger.Log.Debug("error validation", "error", err.Error())
+		return status.Error(codes.Internal, core.InternalErrorCode)
+	}
+	if len(verrors) == 0 {
+		return nil
+	}
+
+	details := &errdetails.BadRequest{}
+	for _, verr := range verrors {
+		description := verr.Tag
+		if verr.Param != "" {
+			description += "=" + verr.Param
+		}
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       verr.Field,
+			Description: description,
+		})
+	}
+
+	st, err := status.New(codes.InvalidArgument, core.ValidationCode).WithDetails(details)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, core.ValidationCode)
+	}
+
+	return st.Err()
+}
+
+func parseID(id int64) (int, error) {
+	if id <= 0 {
+		return 0, status.Error(codes.InvalidArgument, core.InvalidIDCode)
+	}
+
+	return int(id), nil
+}
+
+func parseIDs(ids []int64) ([]int, error) {
+	nums := make([]int, len(ids))
+	for i, id := range ids {
+		num, err := parseID(id)
+		if err != nil {
+			return nil, err
+		}
+		nums[i] = num
+	}
+
+	return nums, nil
+}
+
+// formatTimestamp formats a timestamp as the REST API expects dates,
+// a missing timestamp becomes an empty string.
+func formatTimestamp(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+
+	return ts.AsTime().Format(time.RFC3339)
+}
+
+func formatOptionalTimestamp(ts *timestamppb.Timestamp) *string {
+	if ts == nil {
+		return nil
+	}
+
+	formatted := formatTimestamp(ts)
+	return &formatted
+}
+
+// lockedError tells when the account locked out after failed logins can log in again.
+func lockedError(left time.Duration) error {
+	st, err := status.New(codes.ResourceExhausted, core.AccountLockedCode).WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(left),
+	})
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, core.AccountLockedCode)
+	}
+
+	return st.Err()
+}
+
+// serviceError maps service errors to status codes, notFoundCode is used for pgx.ErrNoRows.
+func serviceError(err error, notFoundCode string) error {
+	switch {
+	case errors.Is(err, core.ErrStarIDNotExists):
+		return status.Error(codes.NotFound, core.StarNotFoundCode)
+	case errors.Is(err, core.ErrUsernameExists):
+		return status.Error(codes.AlreadyExists, core.UsernameIsTaken)
+	case errors.Is(err, pgx.ErrNoRows):
+		return status.Error(codes.NotFound, notFoundCode)
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, core.RequestCanceledCode)
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, core.QueryTimeoutCode)
+	default:
+		logger.Log.Error("grpc", "error", err.Error())
+		return status.Error(codes.Internal, core.InternalErrorCode)
+	}
+}
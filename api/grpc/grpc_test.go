@@ -0,0 +1,243 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"vk-test-task/api/grpc/pb"
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/auth"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/pkg/hash"
+	"vk-test-task/pkg/jwt"
+	"vk-test-task/pkg/locale"
+	"vk-test-task/pkg/ratelimit"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type (
+	// fakeAuth accepts tokens named after roles.
+	fakeAuth struct {
+		auth.Service
+	}
+
+	fakeFilmoteka struct {
+		filmoteka.Service
+		deleted []int
+		// locales are the ones ListMovies is called with
+		locales locale.Locales
+	}
+)
+
+var tokens = map[string]string{
+	"admin-token": core.AdminRole,
+	"user-token":  core.UserRole,
+}
+
+func (fakeAuth) VerifyToken(token string) (*jwt.UserData, error) {
+	role, ok := tokens[token]
+	if !ok {
+		return nil, jwt.ErrInvalidToken
+	}
+
+	return &jwt.UserData{Username: role, Role: role}, nil
+}
+
+func (fakeAuth) GetPassHashAndRoleByUsername(_ context.Context, username string) (string, string, error) {
+	if username != "admin" {
+		return "", "", pgx.ErrNoRows
+	}
+
+	return hash.CalculateHash("secret"), core.AdminRole, nil
+}
+
+func (fakeAuth) CreateToken(_ context.Context, _, role string) (jwt.Token, error) {
+	return jwt.Token{AccessToken: role + "-token"}, nil
+}
+
+func (s *fakeFilmoteka) GetMovies(ctx context.Context, _ filmoteka.GetMoviesModel) ([]movie.Entity, int, error) {
+	s.locales = locale.FromContext(ctx)
+	return []movie.Entity{{ID: 1, Title: "Pulp Fiction", Rating: 9}}, 1, nil
+}
+
+func (s *fakeFilmoteka) GetMovieByID(_ context.Context, id int) (movie.Entity, error) {
+	return movie.Entity{}, pgx.ErrNoRows
+}
+
+func (s *fakeFilmoteka) DeleteMovie(_ context.Context, id int) error {
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+func newTestClient(t *testing.T, service filmoteka.Service, lockout *ratelimit.Lockout) (pb.AuthServiceClient, pb.MovieServiceClient) {
+	t.Helper()
+
+	locales, err := locale.NewNegotiator([]string{"ru", "en"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := New("bufconn", service, fakeAuth{}, lockout, locales)
+	go server.Serve(lis) //nolint
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewAuthServiceClient(conn), pb.NewMovieServiceClient(conn)
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func assertStatus(t *testing.T, err error, code codes.Code, msgCode string) *status.Status {
+	t.Helper()
+
+	st, _ := status.FromError(err)
+	if st.Code() != code || st.Message() != msgCode {
+		t.Fatalf("expected %s %s, got %s %s", code, msgCode, st.Code(), st.Message())
+	}
+
+	return st
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	service := &fakeFilmoteka{}
+	_, movies := newTestClient(t, service, nil)
+
+	_, err := movies.ListMovies(context.Background(), &pb.ListMoviesRequest{})
+	assertStatus(t, err, codes.Unauthenticated, core.AuthHeaderRequiredCode)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Token user-token")
+	_, err = movies.ListMovies(ctx, &pb.ListMoviesRequest{})
+	assertStatus(t, err, codes.Unauthenticated, core.InvalidHeaderCode)
+
+	_, err = movies.ListMovies(withToken("expired"), &pb.ListMoviesRequest{})
+	assertStatus(t, err, codes.Unauthenticated, core.UnauthorizedCode)
+
+	resp, err := movies.ListMovies(withToken("user-token"), &pb.ListMoviesRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(resp.GetMovies()) != 1 || resp.GetPagination().GetTotalCount() != 1 {
+		t.Errorf("unexpected response %v", resp)
+	}
+
+	_, err = movies.DeleteMovie(withToken("user-token"), &pb.DeleteMovieRequest{Id: 1})
+	assertStatus(t, err, codes.PermissionDenied, core.ForbiddenErrorCode)
+	if len(service.deleted) != 0 {
+		t.Errorf("expected no deleted movies, got %v", service.deleted)
+	}
+
+	if _, err := movies.DeleteMovie(withToken("admin-token"), &pb.DeleteMovieRequest{Id: 1}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+}
+
+func TestLocaleInterceptor(t *testing.T) {
+	service := &fakeFilmoteka{}
+	_, movies := newTestClient(t, service, nil)
+
+	ctx := metadata.AppendToOutgoingContext(withToken("user-token"), "accept-language", "en-US,ru;q=0.5")
+	if _, err := movies.ListMovies(ctx, &pb.ListMoviesRequest{}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !slices.Equal(service.locales, locale.Locales{"en", "ru"}) {
+		t.Errorf("wrong locales. Expected [en ru] but got %v", service.locales)
+	}
+
+	if _, err := movies.ListMovies(withToken("user-token"), &pb.ListMoviesRequest{}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !slices.Equal(service.locales, locale.Locales{"ru"}) {
+		t.Errorf("wrong locales. Expected [ru] but got %v", service.locales)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	authClient, _ := newTestClient(t, &fakeFilmoteka{}, nil)
+
+	resp, err := authClient.Login(context.Background(), &pb.LoginRequest{Username: "admin", Password: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if resp.GetAccessToken() != "admin-token" {
+		t.Errorf("expected admin-token, got %s", resp.GetAccessToken())
+	}
+
+	_, err = authClient.Login(context.Background(), &pb.LoginRequest{Username: "admin", Password: "wrong"})
+	assertStatus(t, err, codes.Unauthenticated, core.WrongCredentialsCode)
+
+	_, err = authClient.Login(context.Background(), &pb.LoginRequest{Username: "nobody", Password: "secret"})
+	assertStatus(t, err, codes.Unauthenticated, core.WrongCredentialsCode)
+}
+
+func TestLoginLockout(t *testing.T) {
+	authClient, _ := newTestClient(t, &fakeFilmoteka{}, ratelimit.NewLockout(2, time.Minute, time.Hour))
+
+	for i := 0; i < 2; i++ {
+		_, err := authClient.Login(context.Background(), &pb.LoginRequest{Username: "admin", Password: "wrong"})
+		assertStatus(t, err, codes.Unauthenticated, core.WrongCredentialsCode)
+	}
+
+	_, err := authClient.Login(context.Background(), &pb.LoginRequest{Username: "admin", Password: "secret"})
+	st := assertStatus(t, err, codes.ResourceExhausted, core.AccountLockedCode)
+
+	var retry *errdetails.RetryInfo
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retry = info
+		}
+	}
+	if retry == nil || retry.GetRetryDelay().AsDuration() <= 0 {
+		t.Errorf("expected a retry delay, got %v", st.Details())
+	}
+}
+
+func TestErrors(t *testing.T) {
+	_, movies := newTestClient(t, &fakeFilmoteka{}, nil)
+	ctx := withToken("admin-token")
+
+	_, err := movies.GetMovie(ctx, &pb.GetMovieRequest{Id: 42})
+	assertStatus(t, err, codes.NotFound, core.MovieNotFoundCode)
+
+	_, err = movies.GetMovie(ctx, &pb.GetMovieRequest{})
+	assertStatus(t, err, codes.InvalidArgument, core.InvalidIDCode)
+
+	_, err = movies.CreateMovie(ctx, &pb.CreateMovieRequest{Description: "No title", Rating: 11})
+	st := assertStatus(t, err, codes.InvalidArgument, core.ValidationCode)
+
+	violations := map[string]string{}
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				violations[violation.GetField()] = violation.GetDescription()
+			}
+		}
+	}
+	if violations["Title"] != "required" || violations["Rating"] != "max=10" {
+		t.Errorf("unexpected field violations %v", violations)
+	}
+}
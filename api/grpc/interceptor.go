@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"vk-test-task/api/grpc/pb"
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/auth"
+	"vk-test-task/pkg/jwt"
+	"vk-test-task/pkg/locale"
+	"vk-test-task/pkg/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var publicMethods = map[string]struct{}{
+	pb.AuthService_SignUp_FullMethodName: {},
+	pb.AuthService_Login_FullMethodName:  {},
+}
+
+// methodRoles are the roles allowed to call a method, the same as in the REST handlers.
+// Methods missing here and in publicMethods are denied.
+var methodRoles = map[string][]string{
+	pb.MovieService_ListMovies_FullMethodName:  {core.AdminRole, core.UserRole},
+	pb.MovieService_GetMovie_FullMethodName:    {core.AdminRole, core.UserRole},
+	pb.MovieService_CreateMovie_FullMethodName: {core.AdminRole},
+	pb.MovieService_UpdateMovie_FullMethodName: {core.AdminRole},
+	pb.MovieService_DeleteMovie_FullMethodName: {core.AdminRole},
+	pb.StarService_ListStars_FullMethodName:    {core.AdminRole, core.UserRole},
+	pb.StarService_GetStar_FullMethodName:      {core.AdminRole, core.UserRole},
+	pb.StarService_CreateStar_FullMethodName:   {core.AdminRole},
+	pb.StarService_UpdateStar_FullMethodName:   {core.AdminRole},
+	pb.StarService_DeleteStar_FullMethodName:   {core.AdminRole},
+}
+
+// authInterceptor is the gRPC counterpart of jwtMiddleware and AllowedRoleChecker:
+// it verifies the bearer token from the authorization metadata, checks the role
+// and puts it into the context.
+func authInterceptor(authService auth.Service) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := publicMethods[info.FullMethod]; ok {
+			return handler(ctx, req)
+		}
+
+		token, err := bearerToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		userData, err := authService.VerifyToken(token)
+		if err != nil {
+			if errors.Is(err, jwt.ErrInvalidToken) {
+				return nil, status.Error(codes.Unauthenticated, core.UnauthorizedCode)
+			}
+			logger.Log.ErrorContext(ctx, "verify token", "error", err.Error())
+			return nil, status.Error(codes.Internal, core.InternalErrorCode)
+		}
+
+		if !slices.Contains(methodRoles[info.FullMethod], userData.Role) {
+			return nil, status.Error(codes.PermissionDenied, core.ForbiddenErrorCode)
+		}
+
+		ctx = context.WithValue(ctx, "user_role", userData.Role) //nolint
+		return handler(ctx, req)
+	}
+}
+
+// localeInterceptor is the gRPC counterpart of localeMiddleware: it negotiates the
+// locales of the accept-language metadata and puts them into the context.
+func localeInterceptor(locales *locale.Negotiator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		ctx = locale.ContextWith(ctx, locales.Negotiate(strings.Join(md.Get("accept-language"), ",")))
+		return handler(ctx, req)
+	}
+}
+
+func bearerToken(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", status.Error(codes.Unauthenticated, core.AuthHeaderRequiredCode)
+	}
+
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || scheme != "Bearer" || token == "" {
+		return "", status.Error(codes.Unauthenticated, core.InvalidHeaderCode)
+	}
+
+	return token, nil
+}
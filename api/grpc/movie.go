@@ -0,0 +1,155 @@
+package grpc
+
+import (
+	"context"
+
+	"vk-test-task/api/grpc/pb"
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/pkg/web"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type movieServer struct {
+	pb.UnimplementedMovieServiceServer
+	service filmoteka.Service
+}
+
+func (s *movieServer) ListMovies(ctx context.Context, req *pb.ListMoviesRequest) (*pb.ListMoviesResponse, error) {
+	model := filmoteka.GetMoviesModel{
+		PaginationQuery: web.NewPaginationQuery(int(req.GetPage()), int(req.GetLimit())),
+		SearchTerm:      req.GetQ(),
+		Sort:            req.GetSort(),
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	data, total, err := s.service.GetMovies(ctx, model)
+	if err != nil {
+		return nil, serviceError(err, core.MovieNotFoundCode)
+	}
+
+	return &pb.ListMoviesResponse{
+		Movies:     presentMovies(data),
+		Pagination: presentPagination(model.PaginationBody(total)),
+	}, nil
+}
+
+func (s *movieServer) GetMovie(ctx context.Context, req *pb.GetMovieRequest) (*pb.Movie, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.service.GetMovieByID(ctx, id)
+	if err != nil {
+		return nil, serviceError(err, core.MovieNotFoundCode)
+	}
+
+	return presentMovie(data), nil
+}
+
+func (s *movieServer) CreateMovie(ctx context.Context, req *pb.CreateMovieRequest) (*pb.Movie, error) {
+	starsID, err := parseIDs(req.GetStarsId())
+	if err != nil {
+		return nil, err
+	}
+
+	model := filmoteka.CreateMovieModel{
+		Title:       req.GetTitle(),
+		Description: req.GetDescription(),
+		ReleaseDate: formatTimestamp(req.GetReleaseDate()),
+		Rating:      int(req.GetRating()),
+		StarsID:     starsID,
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	data, err := s.service.CreateMovie(ctx, model)
+	if err != nil {
+		return nil, serviceError(err, core.MovieNotFoundCode)
+	}
+
+	return presentMovie(data), nil
+}
+
+func (s *movieServer) UpdateMovie(ctx context.Context, req *pb.UpdateMovieRequest) (*pb.Movie, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	starsID, err := parseIDs(req.GetStarsId())
+	if err != nil {
+		return nil, err
+	}
+
+	model := filmoteka.UpdateMovieModel{
+		Title:       req.Title,
+		Description: req.Description,
+		ReleaseDate: formatOptionalTimestamp(req.GetReleaseDate()),
+		StarsID:     starsID,
+	}
+	if req.Rating != nil {
+		rating := int(req.GetRating())
+		model.Rating = &rating
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	data, err := s.service.UpdateMovie(ctx, id, model)
+	if err != nil {
+		return nil, serviceError(err, core.MovieNotFoundCode)
+	}
+
+	return presentMovie(data), nil
+}
+
+func (s *movieServer) DeleteMovie(ctx context.Context, req *pb.DeleteMovieRequest) (*emptypb.Empty, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteMovie(ctx, id); err != nil {
+		return nil, serviceError(err, core.MovieNotFoundCode)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func presentMovie(entity movie.Entity) *pb.Movie {
+	return &pb.Movie{
+		Id:          int64(entity.ID),
+		Title:       entity.Title,
+		Description: entity.Description,
+		ReleaseDate: timestamppb.New(entity.ReleaseDate),
+		Rating:      int32(entity.Rating),
+		CreatedAt:   timestamppb.New(entity.CreatedAt),
+		UpdatedAt:   timestamppb.New(entity.UpdatedAt),
+	}
+}
+
+func presentMovies(entities []movie.Entity) []*pb.Movie {
+	movies := make([]*pb.Movie, len(entities))
+	for i, entity := range entities {
+		movies[i] = presentMovie(entity)
+	}
+
+	return movies
+}
+
+func presentPagination(body web.PaginationBody) *pb.Pagination {
+	return &pb.Pagination{
+		TotalCount:  int32(body.TotalCount),
+		PageCount:   int32(body.PageCount),
+		CurrentPage: int32(body.CurrentPage),
+		PerPage:     int32(body.PerPage),
+	}
+}
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"net"
+
+	"vk-test-task/api/grpc/pb"
+	"vk-test-task/internal/service/auth"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/pkg/locale"
+	"vk-test-task/pkg/ratelimit"
+
+	"google.golang.org/grpc"
+)
+
+type Server struct {
+	host   string
+	server *grpc.Server
+}
+
+// New registers the movies, stars and auth services. Calls are authorized by
+// the same JWT and roles as the REST API, see authInterceptor. Logins share
+// the lockout with the REST API, nil turns it off. Movies are localised to the
+// locales of the calls, nil locales leave them as they are.
+func New(
+	host string,
+	filmotekaService filmoteka.Service,
+	authService auth.Service,
+	lockout *ratelimit.Lockout,
+	locales *locale.Negotiator,
+) *Server {
+	interceptors := []grpc.UnaryServerInterceptor{authInterceptor(authService)}
+	if locales != nil {
+		interceptors = append(interceptors, localeInterceptor(locales))
+	}
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+
+	pb.RegisterAuthServiceServer(server, &authServer{service: authService, lockout: lockout})
+	pb.RegisterMovieServiceServer(server, &movieServer{service: filmotekaService})
+	pb.RegisterStarServiceServer(server, &starServer{service: filmotekaService})
+
+	return &Server{
+		host:   host,
+		server: server,
+	}
+}
+
+func (s *Server) Run() error {
+	lis, err := net.Listen("tcp", s.host)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(lis)
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	return s.server.Serve(lis)
+}
+
+func (s *Server) Stop() {
+	s.server.GracefulStop()
+}
+
+// Shutdown waits for the calls in flight like Stop, but cancels them once ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) GetAddr() string {
+	return s.host
+}
@@ -0,0 +1,130 @@
+package grpc
+
+import (
+	"context"
+
+	"vk-test-task/api/grpc/pb"
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/internal/store/star"
+	"vk-test-task/pkg/web"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type starServer struct {
+	pb.UnimplementedStarServiceServer
+	service filmoteka.Service
+}
+
+func (s *starServer) ListStars(ctx context.Context, req *pb.ListStarsRequest) (*pb.ListStarsResponse, error) {
+	model := filmoteka.GetStarsModel{
+		PaginationQuery: web.NewPaginationQuery(int(req.GetPage()), int(req.GetLimit())),
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	data, total, err := s.service.GetStars(ctx, model)
+	if err != nil {
+		return nil, serviceError(err, core.StarNotFoundCode)
+	}
+
+	stars := make([]*pb.Star, len(data))
+	for i, entity := range data {
+		stars[i] = presentStar(entity)
+	}
+
+	return &pb.ListStarsResponse{
+		Stars:      stars,
+		Pagination: presentPagination(model.PaginationBody(total)),
+	}, nil
+}
+
+func (s *starServer) GetStar(ctx context.Context, req *pb.GetStarRequest) (*pb.StarWithMovies, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	data, movies, err := s.service.GetStarByID(ctx, id)
+	if err != nil {
+		return nil, serviceError(err, core.StarNotFoundCode)
+	}
+
+	return presentStarWithMovies(data, movies), nil
+}
+
+func (s *starServer) CreateStar(ctx context.Context, req *pb.CreateStarRequest) (*pb.Star, error) {
+	model := filmoteka.CreateStarModel{
+		Name:      req.GetName(),
+		Sex:       req.GetSex(),
+		BirthDate: formatTimestamp(req.GetBirthDate()),
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	data, err := s.service.CreateStar(ctx, model)
+	if err != nil {
+		return nil, serviceError(err, core.StarNotFoundCode)
+	}
+
+	return presentStar(data), nil
+}
+
+func (s *starServer) UpdateStar(ctx context.Context, req *pb.UpdateStarRequest) (*pb.StarWithMovies, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	model := filmoteka.UpdateStarModel{
+		Name:      req.Name,
+		Sex:       req.Sex,
+		BirthDate: formatOptionalTimestamp(req.GetBirthDate()),
+	}
+	if err := validate(model); err != nil {
+		return nil, err
+	}
+
+	data, movies, err := s.service.UpdateStar(ctx, id, model)
+	if err != nil {
+		return nil, serviceError(err, core.StarNotFoundCode)
+	}
+
+	return presentStarWithMovies(data, movies), nil
+}
+
+func (s *starServer) DeleteStar(ctx context.Context, req *pb.DeleteStarRequest) (*emptypb.Empty, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteStar(ctx, id); err != nil {
+		return nil, serviceError(err, core.StarNotFoundCode)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func presentStar(entity star.Entity) *pb.Star {
+	return &pb.Star{
+		Id:        int64(entity.ID),
+		Name:      entity.Name,
+		Sex:       entity.Sex,
+		BirthDate: timestamppb.New(entity.BirthDate),
+		CreatedAt: timestamppb.New(entity.CreatedAt),
+		UpdatedAt: timestamppb.New(entity.UpdatedAt),
+	}
+}
+
+func presentStarWithMovies(entity star.Entity, movies []movie.Entity) *pb.StarWithMovies {
+	return &pb.StarWithMovies{
+		Star:   presentStar(entity),
+		Movies: presentMovies(movies),
+	}
+}
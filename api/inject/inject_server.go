@@ -1,19 +1,140 @@
 package inject
 
 import (
+	"context"
+
+	"vk-test-task/api/admin"
+	"vk-test-task/api/grpc"
 	"vk-test-task/api/rest/handlers"
+	"vk-test-task/internal/config"
 	"vk-test-task/internal/service/auth"
 	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/migrations"
+	"vk-test-task/pkg/health"
+	"vk-test-task/pkg/locale"
+	"vk-test-task/pkg/metrics"
+	"vk-test-task/pkg/ratelimit"
 
 	"github.com/google/wire"
-	"github.com/urfave/cli/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // wire set for loading the server.
 var serverSet = wire.NewSet( // nolint
+	provideLockout,
+	provideLocales,
 	provideResolver,
+	provideGRPCServer,
+	provideAdminServer,
 )
 
-func provideResolver(c *cli.Context, filmotekaService filmoteka.Service, authService auth.Service) *handlers.Resolver {
-	return handlers.NewResolver(c.String("server-host"), filmotekaService, authService)
+// provideLockout returns the lockout of accounts shared by the REST and gRPC logins.
+func provideLockout(cfg *config.Config) *ratelimit.Lockout {
+	return ratelimit.NewLockout(cfg.Lockout.Threshold, cfg.Lockout.Duration, cfg.Lockout.MaxDuration)
+}
+
+// provideLocales returns the negotiator of the locales of requests shared by the REST
+// and gRPC APIs.
+func provideLocales(cfg *config.Config) (*locale.Negotiator, error) {
+	return locale.NewNegotiator(cfg.Locales)
+}
+
+// RateLimits parses the rates of the config, it is also used to apply them on reload.
+func RateLimits(cfg config.RateLimits) (handlers.RateLimits, error) {
+	var rateLimits handlers.RateLimits
+	for _, field := range []struct {
+		value string
+		rate  *ratelimit.Rate
+	}{
+		{cfg.Default, &rateLimits.Default},
+		{cfg.Auth, &rateLimits.Auth},
+		{cfg.Batch, &rateLimits.Batch},
+		{cfg.Export, &rateLimits.Export},
+	} {
+		var err error
+		if *field.rate, err = ratelimit.ParseRate(field.value); err != nil {
+			return handlers.RateLimits{}, err
+		}
+	}
+
+	return rateLimits, nil
+}
+
+func provideResolver(
+	cfg *config.Config,
+	filmotekaService filmoteka.Service,
+	authService auth.Service,
+	lockout *ratelimit.Lockout,
+	locales *locale.Negotiator,
+) (*handlers.Resolver, error) {
+	rateLimits, err := RateLimits(cfg.RateLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := handlers.Options{
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxBodyBytes:      cfg.Server.MaxBodyBytes,
+		MaxImageBytes:     cfg.Images.MaxBytes,
+		ImagesURL:         cfg.Images.PublicURL,
+		Locales:           locales,
+		QueryTimeouts: handlers.QueryTimeouts{
+			Default: cfg.QueryTimeouts.Default,
+			Batch:   cfg.QueryTimeouts.Batch,
+			Export:  cfg.QueryTimeouts.Export,
+		},
+		RateLimits: rateLimits,
+		Lockout:    lockout,
+		CORS: handlers.CORS{
+			AllowedOrigins:   cfg.CORS.AllowedOrigins,
+			AllowedMethods:   cfg.CORS.AllowedMethods,
+			AllowedHeaders:   cfg.CORS.AllowedHeaders,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge,
+		},
+		TLSCertFile: cfg.Server.TLSCertFile,
+		TLSKeyFile:  cfg.Server.TLSKeyFile,
+	}
+
+	return handlers.NewResolver(cfg.Server.Host, opts, filmotekaService, authService)
+}
+
+func provideGRPCServer(
+	cfg *config.Config,
+	filmotekaService filmoteka.Service,
+	authService auth.Service,
+	lockout *ratelimit.Lockout,
+	locales *locale.Negotiator,
+) *grpc.Server {
+	return grpc.New(cfg.GRPC.Host, filmotekaService, authService, lockout, locales)
+}
+
+// provideAdminServer registers the collectors of the connection pool and the number of
+// movies and stars, which are read on every scrape of the metrics, and the readiness
+// checks of the database, its schema and the JWT config.
+func provideAdminServer(cfg *config.Config, db *pgxpool.Pool, s stores) (*admin.Server, error) {
+	metricsHandler, err := metrics.Handler(
+		metrics.NewPoolCollector(db),
+		metrics.NewEntitiesCollector(map[string]metrics.CountFunc{
+			"movies": s.movies.Count,
+			"stars":  s.stars.Count,
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := health.New()
+	checker.Add("postgres", db.Ping)
+	checker.Add("migrations", func(ctx context.Context) error {
+		return migrations.Check(ctx, db)
+	})
+	checker.Add("jwt", func(context.Context) error {
+		return cfg.JWT.Validate()
+	})
+
+	return admin.New(cfg.Admin.Host, checker, metricsHandler), nil
 }
@@ -1,21 +1,46 @@
 package inject
 
 import (
+	"vk-test-task/internal/config"
 	"vk-test-task/internal/service/auth"
 	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/internal/service/importer"
+	"vk-test-task/pkg/storage"
 
 	"github.com/google/wire"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var serviceSet = wire.NewSet( // nolint
+	provideStorage,
 	provideFilmotekaService,
 	provideAuthService,
 )
 
-func provideFilmotekaService(s stores) filmoteka.Service {
-	return filmoteka.New(s.stars, s.movies)
+var importerSet = wire.NewSet( // nolint
+	provideImporterService,
+)
+
+var exporterSet = wire.NewSet( // nolint
+	provideExporterService,
+)
+
+func provideFilmotekaService(cfg *config.Config, s stores, files storage.Storage) filmoteka.Service {
+	return filmoteka.WithTracing(filmoteka.New(s.stars, s.movies, s.images, files, cfg.Images.MaxPixels))
+}
+
+// provideExporterService leaves out the storage of images, the export reads no files
+// and decodes no images.
+func provideExporterService(s stores) filmoteka.Service {
+	return filmoteka.WithTracing(filmoteka.New(s.stars, s.movies, s.images, nil, 0))
+}
+
+func provideAuthService(cfg *config.Config, s stores) (auth.Service, error) {
+	return auth.New(s.users, &cfg.JWT)
 }
 
-func provideAuthService(s stores) (auth.Service, error) {
-	return auth.New(s.users)
+// provideImporterService closes the database pool on cleanup, the importer is
+// the only user of the pool in the import command.
+func provideImporterService(s stores, db *pgxpool.Pool) (importer.Service, func()) {
+	return importer.New(s.stars, s.movies), db.Close
 }
@@ -1,15 +1,19 @@
 package inject
 
 import (
-	"fmt"
+	"context"
+	"net/url"
 
+	"vk-test-task/internal/config"
+	"vk-test-task/internal/store/image"
 	"vk-test-task/internal/store/movie"
 	"vk-test-task/internal/store/star"
 	"vk-test-task/internal/store/user"
 	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/storage"
+	"vk-test-task/pkg/tracing"
 
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/urfave/cli/v2"
 
 	"github.com/google/wire"
 )
@@ -23,33 +27,57 @@ type stores struct {
 	stars  star.Store
 	movies movie.Store
 	users  user.Store
+	images image.Store
 }
 
-func provideStores(c *cli.Context, db *pgxpool.Pool) stores {
+func provideStores(db *pgxpool.Pool) stores {
 	return stores{
-		stars:  star.New(db),
-		movies: movie.New(db),
-		users:  user.New(db),
+		stars:  star.WithMetrics(star.New(db)),
+		movies: movie.WithMetrics(movie.New(db)),
+		users:  user.WithMetrics(user.New(db)),
+		images: image.WithMetrics(image.New(db)),
 	}
 }
 
-func createDBClient(c *cli.Context) (*pgxpool.Pool, error) {
-	databaseURL := fmt.Sprintf(
-		"postgres://%s:%s@%s/%s?sslmode=%s",
-		c.String("filmoteka-db-user"),
-		c.String("filmoteka-db-pass"),
-		c.String("filmoteka-db-host"),
-		c.String("filmoteka-db-name"),
-		c.String("filmoteka-db-sslmode"),
-	)
-	logger.Log.Debug("connecting to database", "url", databaseURL)
-
-	postgresClient, err := pgxpool.New(c.Context, databaseURL)
+// provideStorage returns the storage of the image files chosen by the config.
+func provideStorage(cfg *config.Config) (storage.Storage, error) {
+	if cfg.Images.Storage == config.StorageS3 {
+		return storage.NewS3(storage.S3Options{
+			Endpoint:  cfg.Images.S3.Endpoint,
+			Bucket:    cfg.Images.S3.Bucket,
+			AccessKey: cfg.Images.S3.AccessKey,
+			SecretKey: cfg.Images.S3.SecretKey,
+			Region:    cfg.Images.S3.Region,
+			Secure:    cfg.Images.S3.Secure,
+		})
+	}
+
+	return storage.NewLocal(cfg.Images.Dir)
+}
+
+func createDBClient(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	// url.URL escapes the credentials, so any password can be used
+	databaseURL := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.DB.User, cfg.DB.Password),
+		Host:     cfg.DB.Host,
+		Path:     cfg.DB.Name,
+		RawQuery: url.Values{"sslmode": {cfg.DB.SSLMode}}.Encode(),
+	}
+	logger.Log.Debug("connecting to database", "url", databaseURL.Redacted())
+
+	config, err := pgxpool.ParseConfig(databaseURL.String())
+	if err != nil {
+		return nil, err
+	}
+	config.ConnConfig.Tracer = tracing.QueryTracer{}
+
+	postgresClient, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := postgresClient.Ping(c.Context); err != nil {
+	if err := postgresClient.Ping(ctx); err != nil {
 		return nil, err
 	}
 
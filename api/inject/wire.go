@@ -6,12 +6,14 @@ package inject
 import (
 	"context"
 	"vk-test-task/api"
+	"vk-test-task/internal/config"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/internal/service/importer"
 
 	"github.com/google/wire"
-	"github.com/urfave/cli/v2"
 )
 
-func InitializeApplication(c *cli.Context, appCtx context.Context) (api.Container, error) {
+func InitializeApplication(ctx context.Context, cfg *config.Config) (api.Container, error) {
 	wire.Build(
 		serverSet,
 		storeSet,
@@ -20,3 +22,19 @@ func InitializeApplication(c *cli.Context, appCtx context.Context) (api.Containe
 	)
 	return api.Container{}, nil
 }
+
+func InitializeImporter(ctx context.Context, cfg *config.Config) (importer.Service, func(), error) {
+	wire.Build(
+		storeSet,
+		importerSet,
+	)
+	return nil, nil, nil
+}
+
+func InitializeExporter(ctx context.Context, cfg *config.Config) (filmoteka.Service, error) {
+	wire.Build(
+		storeSet,
+		exporterSet,
+	)
+	return nil, nil
+}
@@ -8,24 +8,65 @@ package inject
 
 import (
 	"context"
-	"github.com/urfave/cli/v2"
 	"vk-test-task/api"
+	"vk-test-task/internal/config"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/internal/service/importer"
 )
 
 // Injectors from wire.go:
 
-func InitializeApplication(c *cli.Context, appCtx context.Context) (api.Container, error) {
-	pool, err := createDBClient(c)
+func InitializeApplication(ctx context.Context, cfg *config.Config) (api.Container, error) {
+	pool, err := createDBClient(ctx, cfg)
 	if err != nil {
 		return api.Container{}, err
 	}
-	injectStores := provideStores(c, pool)
-	service := provideFilmotekaService(injectStores)
-	authService, err := provideAuthService(injectStores)
+	injectStores := provideStores(pool)
+	storageStorage, err := provideStorage(cfg)
 	if err != nil {
 		return api.Container{}, err
 	}
-	resolver := provideResolver(c, service, authService)
-	container := api.NewContainer(resolver)
+	service := provideFilmotekaService(cfg, injectStores, storageStorage)
+	authService, err := provideAuthService(cfg, injectStores)
+	if err != nil {
+		return api.Container{}, err
+	}
+	lockout := provideLockout(cfg)
+	negotiator, err := provideLocales(cfg)
+	if err != nil {
+		return api.Container{}, err
+	}
+	resolver, err := provideResolver(cfg, service, authService, lockout, negotiator)
+	if err != nil {
+		return api.Container{}, err
+	}
+	server := provideGRPCServer(cfg, service, authService, lockout, negotiator)
+	adminServer, err := provideAdminServer(cfg, pool, injectStores)
+	if err != nil {
+		return api.Container{}, err
+	}
+	container := api.NewContainer(resolver, server, adminServer, pool)
 	return container, nil
 }
+
+func InitializeImporter(ctx context.Context, cfg *config.Config) (importer.Service, func(), error) {
+	pool, err := createDBClient(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	injectStores := provideStores(pool)
+	service, cleanup := provideImporterService(injectStores, pool)
+	return service, func() {
+		cleanup()
+	}, nil
+}
+
+func InitializeExporter(ctx context.Context, cfg *config.Config) (filmoteka.Service, error) {
+	pool, err := createDBClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	injectStores := provideStores(pool)
+	service := provideExporterService(injectStores)
+	return service, nil
+}
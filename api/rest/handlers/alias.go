@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"vk-test-task/api/rest/presenters/alias"
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/pkg/web"
+	"vk-test-task/pkg/webutil"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *Resolver) handleMovieAliases(w http.ResponseWriter, req *http.Request) {
+	switch {
+	case req.Method == http.MethodGet && isAliasesPath(req):
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole, core.UserRole) {
+			r.getMovieAliases(w, req)
+		}
+	case req.Method == http.MethodPost && isAliasesPath(req):
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole) {
+			r.createMovieAlias(w, req)
+		}
+	case req.Method == http.MethodDelete && !isAliasesPath(req):
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole) {
+			r.deleteMovieAlias(w, req)
+		}
+	default:
+		webutil.SendJSONResponse(w, http.StatusMethodNotAllowed, web.ErrorResponse(core.UnsupportedMethodCode, nil, nil))
+	}
+}
+
+func (r *Resolver) handleStarAliases(w http.ResponseWriter, req *http.Request) {
+	switch {
+	case req.Method == http.MethodGet && isAliasesPath(req):
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole, core.UserRole) {
+			r.getStarAliases(w, req)
+		}
+	case req.Method == http.MethodPost && isAliasesPath(req):
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole) {
+			r.createStarAlias(w, req)
+		}
+	case req.Method == http.MethodDelete && !isAliasesPath(req):
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole) {
+			r.deleteStarAlias(w, req)
+		}
+	default:
+		webutil.SendJSONResponse(w, http.StatusMethodNotAllowed, web.ErrorResponse(core.UnsupportedMethodCode, nil, nil))
+	}
+}
+
+// isAliasPath reports whether the request is to the aliases of a movie or a star, or
+// to one of them.
+func isAliasPath(req *http.Request) bool {
+	return isAliasesPath(req) || strings.Contains(req.URL.Path, "/aliases/")
+}
+
+// isAliasesPath reports whether the request is to all the aliases of a movie or a star.
+func isAliasesPath(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, "/aliases")
+}
+
+// @Title Get Movie Aliases
+// @Resource Movies
+// @Description Get the original, working and regional titles of the movie
+// @Param id path int true "Movie ID"
+// @Success 200 object model.GetMovieAliasesResponse "Successful get aliases"
+// @Failure 400 object model.BadRequestInvalidIDResponse "Bad request error"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 404 object model.MovieNotFoundResponse "Not found error"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/movie/{id}/aliases [get]
+func (r *Resolver) getMovieAliases(w http.ResponseWriter, req *http.Request) {
+	id := webutil.ParseParentID(w, req)
+	if id == 0 {
+		return
+	}
+
+	data, err := r.filmotekaService.GetMovieAliases(req.Context(), id)
+	if err != nil {
+		sendAliasError(w, req, err, core.MovieNotFoundCode)
+		return
+	}
+
+	pres := alias.PresentMovieAliases(data)
+
+	webutil.SendJSONResponse(w, http.StatusOK, pres.Response(core.AliasesReceivedCode))
+}
+
+// @Title Create Movie Alias
+// @Resource Movies
+// @Description Add an original, working or regional title of the movie, movies are searched by their aliases too
+// @Param id path int true "Movie ID"
+// @Param alias body model.CreateMovieAliasRequest true "Alias"
+// @Success 201 object model.CreateAliasResponse "Successful create alias"
+// @Failure 400 object model.BadRequestResponse "Bad request error"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 404 object model.MovieNotFoundResponse "Not found error"
+// @Failure 409 object model.ConflictAliasResponse "Alias exists"
+// @Failure 413 object model.PayloadTooLargeResponse "Request body too large"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/movie/{id}/aliases [post]
+func (r *Resolver) createMovieAlias(w http.ResponseWriter, req *http.Request) {
+	id := webutil.ParseParentID(w, req)
+	if id == 0 {
+		return
+	}
+
+	var model filmoteka.CreateMovieAliasModel
+
+	if !webutil.BodyCheck(w, req, &model) {
+		return
+	}
+
+	data, err := r.filmotekaService.CreateMovieAlias(req.Context(), id, model)
+	if err != nil {
+		sendAliasError(w, req, err, core.MovieNotFoundCode)
+		return
+	}
+
+	pres := alias.PresentMovieAlias(data)
+
+	webutil.SendJSONResponse(w, http.StatusCreated, pres.Response(core.AliasCreatedCode))
+}
+
+// @Title Delete Movie Alias
+// @Resource Movies
+// @Param id path int true "Movie ID"
+// @Param aliasID path int true "Alias ID"
+// @Success 200 object model.DeleteAliasResponse "Successful delete alias"
+// @Failure 400 object model.BadRequestInvalidIDResponse "Bad request error"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 404 object model.AliasNotFoundResponse "Not found error"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/movie/{id}/aliases/{aliasID} [delete]
+func (r *Resolver) deleteMovieAlias(w http.ResponseWriter, req *http.Request) {
+	id, aliasID, ok := parseAliasPath(w, req)
+	if !ok {
+		return
+	}
+
+	err := r.filmotekaService.DeleteMovieAlias(req.Context(), id, aliasID)
+	if err != nil {
+		sendAliasError(w, req, err, core.AliasNotFoundCode)
+		return
+	}
+
+	webutil.SendJSONResponse(w, http.StatusOK, web.OKResponse(core.AliasDeletedCode, nil, nil))
+}
+
+// @Title Get Star Aliases
+// @Resource Stars
+// @Description Get the stage names and transliterations of the star's name
+// @Param id path int true "Star ID"
+// @Success 200 object model.GetStarAliasesResponse "Successful get aliases"
+// @Failure 400 object model.BadRequestInvalidIDResponse "Bad request error"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 404 object model.StarNotFoundResponse "Not found error"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/star/{id}/aliases [get]
+func (r *Resolver) getStarAliases(w http.ResponseWriter, req *http.Request) {
+	id := webutil.ParseParentID(w, req)
+	if id == 0 {
+		return
+	}
+
+	data, err := r.filmotekaService.GetStarAliases(req.Context(), id)
+	if err != nil {
+		sendAliasError(w, req, err, core.StarNotFoundCode)
+		return
+	}
+
+	pres := alias.PresentStarAliases(data)
+
+	webutil.SendJSONResponse(w, http.StatusOK, pres.Response(core.AliasesReceivedCode))
+}
+
+// @Title Create Star Alias
+// @Resource Stars
+// @Description Add a stage name or a transliteration of the star's name, stars are searched by their aliases too
+// @Param id path int true "Star ID"
+// @Param alias body model.CreateStarAliasRequest true "Alias"
+// @Success 201 object model.CreateAliasResponse "Successful create alias"
+// @Failure 400 object model.BadRequestResponse "Bad request error"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 404 object model.StarNotFoundResponse "Not found error"
+// @Failure 409 object model.ConflictAliasResponse "Alias exists"
+// @Failure 413 object model.PayloadTooLargeResponse "Request body too large"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/star/{id}/aliases [post]
+func (r *Resolver) createStarAlias(w http.ResponseWriter, req *http.Request) {
+	id := webutil.ParseParentID(w, req)
+	if id == 0 {
+		return
+	}
+
+	var model filmoteka.CreateStarAliasModel
+
+	if !webutil.BodyCheck(w, req, &model) {
+		return
+	}
+
+	data, err := r.filmotekaService.CreateStarAlias(req.Context(), id, model)
+	if err != nil {
+		sendAliasError(w, req, err, core.StarNotFoundCode)
+		return
+	}
+
+	pres := alias.PresentStarAlias(data)
+
+	webutil.SendJSONResponse(w, http.StatusCreated, pres.Response(core.AliasCreatedCode))
+}
+
+// @Title Delete Star Alias
+// @Resource Stars
+// @Param id path int true "Star ID"
+// @Param aliasID path int true "Alias ID"
+// @Success 200 object model.DeleteAliasResponse "Successful delete alias"
+// @Failure 400 object model.BadRequestInvalidIDResponse "Bad request error"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 404 object model.AliasNotFoundResponse "Not found error"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/star/{id}/aliases/{aliasID} [delete]
+func (r *Resolver) deleteStarAlias(w http.ResponseWriter, req *http.Request) {
+	id, aliasID, ok := parseAliasPath(w, req)
+	if !ok {
+		return
+	}
+
+	err := r.filmotekaService.DeleteStarAlias(req.Context(), id, aliasID)
+	if err != nil {
+		sendAliasError(w, req, err, core.AliasNotFoundCode)
+		return
+	}
+
+	webutil.SendJSONResponse(w, http.StatusOK, web.OKResponse(core.AliasDeletedCode, nil, nil))
+}
+
+// parseAliasPath parses /movie/{id}/aliases/{aliasID} and the same path of stars.
+func parseAliasPath(w http.ResponseWriter, req *http.Request) (int, int, bool) {
+	id := webutil.ParseIDAt(w, req, 2)
+	if id == 0 {
+		return 0, 0, false
+	}
+
+	aliasID := webutil.ParseID(w, req)
+	if aliasID == 0 {
+		return 0, 0, false
+	}
+
+	return id, aliasID, true
+}
+
+// sendAliasError answers errors of the alias handlers, notFoundCode tells what was not
+// found: the movie, the star or the alias.
+func sendAliasError(w http.ResponseWriter, req *http.Request, err error, notFoundCode string) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(notFoundCode, nil, nil))
+	case errors.Is(err, core.ErrAliasExists):
+		webutil.SendJSONResponse(w, http.StatusConflict, web.ErrorResponse(core.AliasExistsCode, nil, nil))
+	default:
+		webutil.SendServiceError(w, req, err)
+	}
+}
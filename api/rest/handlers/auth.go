@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"errors"
 	"net/http"
 
@@ -10,6 +9,7 @@ import (
 	"vk-test-task/internal/core"
 	"vk-test-task/internal/service/auth"
 	"vk-test-task/pkg/hash"
+	"vk-test-task/pkg/metrics"
 	"vk-test-task/pkg/web"
 	"vk-test-task/pkg/webutil"
 
@@ -23,8 +23,11 @@ import (
 // @Success 200 object model.LoginResponse "Successful login"
 // @Failure 400 object model.BadRequestInvalidBodyResponse "Bad request error"
 // @Failure 401 object model.WrongCredentialsResponse "Unauthorized error"
+// @Failure 413 object model.PayloadTooLargeResponse "Request body too large"
 // @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
 // @Failure 500 object model.InternalResponse "Internal server error"
+// @Failure 503 object model.QueryTimeoutResponse "Query timeout"
 // @Route /api/v1/auth/login [post]
 func (r *Resolver) login(w http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodPost {
@@ -37,32 +40,41 @@ func (r *Resolver) login(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if left, locked := r.lockout.Locked(model.Username); locked {
+		metrics.Login(metrics.LoginLocked)
+		sendTooManyRequests(w, core.AccountLockedCode, left)
+		return
+	}
+
 	reqPassHash := hash.CalculateHash(model.Password)
 
-	passHash, role, err := r.authService.GetPassHashAndRoleByUsername(context.Background(), model.Username)
+	passHash, role, err := r.authService.GetPassHashAndRoleByUsername(req.Context(), model.Username)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		webutil.SendServiceError(w, req, err)
+		return
+	}
 	if err != nil || passHash != reqPassHash {
-		switch {
-		case err == pgx.ErrNoRows || passHash != reqPassHash:
-			webutil.SendJSONResponse(w, http.StatusUnauthorized, web.ErrorResponse(core.WrongCredentialsCode, nil, nil))
-			return
-		default:
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
-			return
-		}
+		metrics.Login(metrics.LoginFailure)
+		r.lockout.Fail(model.Username)
+		webutil.SendJSONResponse(w, http.StatusUnauthorized, web.ErrorResponse(core.WrongCredentialsCode, nil, nil))
+		return
 	}
 
-	data, err := r.authService.CreateToken(context.Background(), model.Username, role)
+	r.lockout.Succeed(model.Username)
+
+	data, err := r.authService.CreateToken(req.Context(), model.Username, role)
 	if err != nil {
 		switch {
 		case errors.Is(err, core.ErrUsernameExists):
 			webutil.SendJSONResponse(w, http.StatusConflict, web.ErrorResponse(core.UsernameIsTaken, nil, nil))
 			return
 		default:
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+			webutil.SendServiceError(w, req, err)
 			return
 		}
 	}
 
+	metrics.Login(metrics.LoginSuccess)
 	webutil.SendJSONResponse(w, http.StatusOK, web.OKResponse(core.JWTRecievedCode, data, nil))
 }
 
@@ -70,10 +82,12 @@ func (r *Resolver) login(w http.ResponseWriter, req *http.Request) {
 // @Resource Auth
 // @Description Sign-up user
 // @Param user body model.SignUpRequest true "Sign-up Request"
-// @Success 200 object model.SignUpResponse "Successful sign-up"
+// @Success 201 object model.SignUpResponse "Successful sign-up"
 // @Failure 400 object model.BadRequestInvalidBodyResponse "Bad request error"
 // @Failure 409 object model.ConflictUsernameResponse "Username is taken error"
+// @Failure 413 object model.PayloadTooLargeResponse "Request body too large"
 // @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
 // @Failure 500 object model.InternalResponse "Internal server error"
 // @Route /api/v1/auth/signup [post]
 func (r *Resolver) signup(w http.ResponseWriter, req *http.Request) {
@@ -87,14 +101,14 @@ func (r *Resolver) signup(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	data, err := r.authService.SignUp(context.Background(), model)
+	data, err := r.authService.SignUp(req.Context(), model)
 	if err != nil {
 		switch {
 		case errors.Is(err, core.ErrUsernameExists):
 			webutil.SendJSONResponse(w, http.StatusConflict, web.ErrorResponse(core.UsernameIsTaken, nil, nil))
 			return
 		default:
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+			webutil.SendServiceError(w, req, err)
 			return
 		}
 	}
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"vk-test-task/api/rest/presenters/batch"
+	"vk-test-task/api/rest/presenters/movie"
+	"vk-test-task/api/rest/presenters/star"
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/web"
+	"vk-test-task/pkg/webutil"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// batchQuery is the query of batch endpoints.
+type batchQuery struct {
+	Atomic bool `query:"atomic"`
+}
+
+func (r *Resolver) handleMoviesBatch(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPost:
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole) {
+			r.batchMovies(w, req)
+		}
+	default:
+		webutil.SendJSONResponse(w, http.StatusMethodNotAllowed, web.ErrorResponse(core.UnsupportedMethodCode, nil, nil))
+	}
+}
+
+func (r *Resolver) handleStarsBatch(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPost:
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole) {
+			r.batchStars(w, req)
+		}
+	default:
+		webutil.SendJSONResponse(w, http.StatusMethodNotAllowed, web.ErrorResponse(core.UnsupportedMethodCode, nil, nil))
+	}
+}
+
+// @Title Batch Movies
+// @Resource Movies
+// @Description Create, update and delete up to 1000 movies in one request
+// @Param atomic query bool false "Roll back the whole batch if any operation fails (default false)"
+// @Param operations body model.BatchMoviesRequest true "Operations to apply"
+// @Success 200 object model.BatchMoviesResponse "Successful batch processing"
+// @Failure 400 object model.BadRequestResponse "Bad request error"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 413 object model.PayloadTooLargeResponse "Request body too large"
+// @Failure 422 object model.BatchAbortedResponse "Validation error or atomic batch aborted"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/movies:batch [post]
+func (r *Resolver) batchMovies(w http.ResponseWriter, req *http.Request) {
+	var query batchQuery
+
+	if !webutil.QueryCheck(w, req, &query) {
+		return
+	}
+
+	var body filmoteka.BatchRequestModel
+
+	if !webutil.BodyCheck(w, req, &body) {
+		return
+	}
+
+	atomic := query.Atomic
+	model := filmoteka.BatchMoviesModel{Atomic: atomic}
+	items := make([]batch.ItemPresenter, len(body.Operations))
+
+	var indexes []int
+	for i, op := range body.Operations {
+		operation := filmoteka.BatchMovieOperation{Op: op.Op, ID: op.ID}
+
+		var payload any
+		switch op.Op {
+		case core.BatchCreateOp:
+			operation.Create = &filmoteka.CreateMovieModel{}
+			payload = operation.Create
+		case core.BatchUpdateOp:
+			operation.Update = &filmoteka.UpdateMovieModel{}
+			payload = operation.Update
+		}
+
+		if code, verrors := checkBatchOperation(op, payload); code != "" {
+			items[i] = batch.Failed(i, op.Op, code, verrors)
+			continue
+		}
+
+		model.Operations = append(model.Operations, operation)
+		indexes = append(indexes, i)
+	}
+
+	if atomic && len(indexes) != len(items) {
+		for _, i := range indexes {
+			items[i] = batch.Failed(i, body.Operations[i].Op, core.BatchAbortedCode, nil)
+		}
+		pres := batch.PresentList(items)
+		webutil.SendJSONResponse(w, http.StatusUnprocessableEntity, pres.AbortedResponse())
+		return
+	}
+
+	results, err := r.filmotekaService.BatchMovies(req.Context(), model)
+	if err != nil && !errors.Is(err, core.ErrBatchAborted) {
+		webutil.SendServiceError(w, req, err)
+		return
+	}
+
+	for j, result := range results {
+		i := indexes[j]
+		op := body.Operations[i].Op
+
+		if result.Err != nil {
+			items[i] = batch.Failed(i, op, movieBatchErrorCode(result.Err), nil)
+			continue
+		}
+		if err != nil {
+			items[i] = batch.Failed(i, op, core.BatchAbortedCode, nil)
+			continue
+		}
+
+		pres := movie.PresentMovie(result.Entity, nil)
+		items[i] = batch.Succeeded(i, op, movieBatchSuccessCode(op), pres)
+	}
+
+	pres := batch.PresentList(items)
+
+	if err != nil {
+		webutil.SendJSONResponse(w, http.StatusUnprocessableEntity, pres.AbortedResponse())
+		return
+	}
+
+	webutil.SendJSONResponse(w, http.StatusOK, pres.Response(core.MoviesBatchProcessedCode))
+}
+
+// @Title Batch Stars
+// @Resource Stars
+// @Description Create, update and delete up to 1000 stars in one request
+// @Param atomic query bool false "Roll back the whole batch if any operation fails (default false)"
+// @Param operations body model.BatchStarsRequest true "Operations to apply"
+// @Success 200 object model.BatchStarsResponse "Successful batch processing"
+// @Failure 400 object model.BadRequestResponse "Bad request error"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 413 object model.PayloadTooLargeResponse "Request body too large"
+// @Failure 422 object model.BatchAbortedResponse "Validation error or atomic batch aborted"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/stars:batch [post]
+func (r *Resolver) batchStars(w http.ResponseWriter, req *http.Request) {
+	var query batchQuery
+
+	if !webutil.QueryCheck(w, req, &query) {
+		return
+	}
+
+	var body filmoteka.BatchRequestModel
+
+	if !webutil.BodyCheck(w, req, &body) {
+		return
+	}
+
+	atomic := query.Atomic
+	model := filmoteka.BatchStarsModel{Atomic: atomic}
+	items := make([]batch.ItemPresenter, len(body.Operations))
+
+	var indexes []int
+	for i, op := range body.Operations {
+		operation := filmoteka.BatchStarOperation{Op: op.Op, ID: op.ID}
+
+		var payload any
+		switch op.Op {
+		case core.BatchCreateOp:
+			operation.Create = &filmoteka.CreateStarModel{}
+			payload = operation.Create
+		case core.BatchUpdateOp:
+			operation.Update = &filmoteka.UpdateStarModel{}
+			payload = operation.Update
+		}
+
+		if code, verrors := checkBatchOperation(op, payload); code != "" {
+			items[i] = batch.Failed(i, op.Op, code, verrors)
+			continue
+		}
+
+		model.Operations = append(model.Operations, operation)
+		indexes = append(indexes, i)
+	}
+
+	if atomic && len(indexes) != len(items) {
+		for _, i := range indexes {
+			items[i] = batch.Failed(i, body.Operations[i].Op, core.BatchAbortedCode, nil)
+		}
+		pres := batch.PresentList(items)
+		webutil.SendJSONResponse(w, http.StatusUnprocessableEntity, pres.AbortedResponse())
+		return
+	}
+
+	results, err := r.filmotekaService.BatchStars(req.Context(), model)
+	if err != nil && !errors.Is(err, core.ErrBatchAborted) {
+		webutil.SendServiceError(w, req, err)
+		return
+	}
+
+	for j, result := range results {
+		i := indexes[j]
+		op := body.Operations[i].Op
+
+		if result.Err != nil {
+			items[i] = batch.Failed(i, op, starBatchErrorCode(result.Err), nil)
+			continue
+		}
+		if err != nil {
+			items[i] = batch.Failed(i, op, core.BatchAbortedCode, nil)
+			continue
+		}
+
+		pres := star.PresentStar(result.Entity, nil, nil)
+		items[i] = batch.Succeeded(i, op, starBatchSuccessCode(op), pres.Star)
+	}
+
+	pres := batch.PresentList(items)
+
+	if err != nil {
+		webutil.SendJSONResponse(w, http.StatusUnprocessableEntity, pres.AbortedResponse())
+		return
+	}
+
+	webutil.SendJSONResponse(w, http.StatusOK, pres.Response(core.StarsBatchProcessedCode))
+}
+
+// checkBatchOperation validates the operation envelope and decodes its data into payload.
+// It returns an empty code for a valid operation.
+func checkBatchOperation(op filmoteka.BatchOperationModel, payload any) (string, []web.ValidationError) {
+	verrors, err := webutil.StructErrors(op)
+	if err != nil {
+		logger.Log.Debug("error validation", "error", err.Error())
+		return core.InternalErrorCode, nil
+	}
+	if len(verrors) != 0 {
+		return core.ValidationCode, verrors
+	}
+
+	if payload == nil {
+		return "", nil
+	}
+	if len(op.Data) == 0 {
+		return core.BodyRequiredCode, nil
+	}
+	if err := webutil.UnmarshalStrict(op.Data, payload); err != nil {
+		return core.InvalidBodyCode, nil
+	}
+
+	verrors, err = webutil.StructErrors(payload)
+	if err != nil {
+		logger.Log.Debug("error validation", "error", err.Error())
+		return core.InternalErrorCode, nil
+	}
+	if len(verrors) != 0 {
+		return core.ValidationCode, verrors
+	}
+
+	return "", nil
+}
+
+func movieBatchSuccessCode(op string) string {
+	switch op {
+	case core.BatchCreateOp:
+		return core.MovieCreatedCode
+	case core.BatchUpdateOp:
+		return core.MovieUpdatedCode
+	default:
+		return core.MovieDeletedCode
+	}
+}
+
+func movieBatchErrorCode(err error) string {
+	switch {
+	case errors.Is(err, core.ErrStarIDNotExists):
+		return core.StarNotFoundCode
+	case errors.Is(err, pgx.ErrNoRows):
+		return core.MovieNotFoundCode
+	default:
+		return core.InternalErrorCode
+	}
+}
+
+func starBatchSuccessCode(op string) string {
+	switch op {
+	case core.BatchCreateOp:
+		return core.StarCreatedCode
+	case core.BatchUpdateOp:
+		return core.StarUpdatedCode
+	default:
+		return core.StarDeletedCode
+	}
+}
+
+func starBatchErrorCode(err error) string {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return core.StarNotFoundCode
+	default:
+		return core.InternalErrorCode
+	}
+}
@@ -0,0 +1,738 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"vk-test-task/api/doc"
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/auth"
+	"vk-test-task/internal/service/filmoteka"
+	imagestore "vk-test-task/internal/store/image"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/internal/store/star"
+	"vk-test-task/internal/store/user"
+	"vk-test-task/pkg/jwt"
+	"vk-test-task/pkg/locale"
+	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/storage"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/exp/slog"
+)
+
+// missingID is never found by fakeFilmoteka.
+const missingID = 404
+
+// specHost matches the server of the OpenAPI document so that routes can be found.
+const specHost = "http://localhost:8080"
+
+// formBoundary separates the parts of the upload forms of the contract cases.
+const formBoundary = "contract-boundary"
+
+const multipartForm = "multipart/form-data; boundary=" + formBoundary
+
+// takenAlias is an alias every movie and star of fakeFilmoteka already has.
+const takenAlias = "Taken"
+
+// timeoutUsername makes memoryUsers fail as a query that ran out of time.
+const timeoutUsername = "timeout"
+
+// missingImageKey is never found by fakeFilmoteka.
+const missingImageKey = "00000000000000000000000000000000.png"
+
+// fakePNG is a 1x1 image, anything else uploaded to fakeFilmoteka is not an image.
+var fakePNG = func() []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}()
+
+type (
+	memoryUsers struct {
+		mu    sync.Mutex
+		users map[string]user.CreateEntity
+	}
+
+	// fakeFilmoteka answers with canned entities. Entities with missingID are not found,
+	// as well as stars with missingID passed to movies and the image of missingImageKey.
+	fakeFilmoteka struct{}
+
+	contractCase struct {
+		name   string
+		method string
+		path   string
+		role   string
+		header string
+		accept string
+		// acceptLanguage asks for the locales of movies
+		acceptLanguage string
+		body           string
+		// contentType of the body, application/json if empty
+		contentType string
+		status      int
+		// invalid marks requests that break the spec on purpose to check handler errors
+		invalid bool
+	}
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	for _, contentType := range []string{"application/xml", "application/x-ndjson", "text/html", "image/png"} {
+		openapi3filter.RegisterBodyDecoder(contentType, stringBodyDecoder)
+	}
+
+	os.Exit(m.Run())
+}
+
+func stringBodyDecoder(body io.Reader, _ http.Header, _ *openapi3.SchemaRef, _ openapi3filter.EncodingFn) (any, error) {
+	data, err := io.ReadAll(body)
+	return string(data), err
+}
+
+func (s *memoryUsers) Create(_ context.Context, entity user.CreateEntity) (user.Entity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[entity.Username] = entity
+	now := time.Now().UTC()
+	return user.Entity{ID: len(s.users), Username: entity.Username, Role: entity.Role, CreatedAt: now, UpdatedAt: now}, nil
+}
+
+func (s *memoryUsers) GetPassHashAndRoleByUsername(_ context.Context, username string) (string, string, error) {
+	if username == timeoutUsername {
+		return "", "", context.DeadlineExceeded
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entity, ok := s.users[username]
+	if !ok {
+		return "", "", pgx.ErrNoRows
+	}
+	return entity.PassHash, entity.Role, nil
+}
+
+func (s *memoryUsers) CheckExistence(_ context.Context, username string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.users[username]
+	return ok, nil
+}
+
+func fakeMovie(id int) movie.Entity {
+	date := time.Date(2024, 3, 15, 21, 16, 36, 0, time.UTC)
+	return movie.Entity{
+		ID:          id,
+		Title:       "Drive",
+		Description: "Night Call",
+		ReleaseDate: time.Date(2011, 11, 3, 0, 0, 0, 0, time.UTC),
+		Rating:      8,
+		CreatedAt:   date,
+		UpdatedAt:   date,
+	}
+}
+
+func fakeStar(id int) star.Entity {
+	date := time.Date(2024, 3, 15, 21, 16, 36, 0, time.UTC)
+	return star.Entity{
+		ID:        id,
+		Name:      "Ryan Gosling",
+		Sex:       "male",
+		BirthDate: time.Date(1980, 11, 12, 0, 0, 0, 0, time.UTC),
+		CreatedAt: date,
+		UpdatedAt: date,
+	}
+}
+
+func fakeImage(id int, movieID, starID *int) imagestore.Entity {
+	kind := imagestore.PosterKind
+	if starID != nil {
+		kind = imagestore.HeadshotKind
+	}
+	return imagestore.Entity{
+		ID:          id,
+		MovieID:     movieID,
+		StarID:      starID,
+		Kind:        kind,
+		Key:         "3f2a9c0e1b7d4a5f8c6e2d1b0a9f8e7d.png",
+		ContentType: "image/png",
+		Width:       1,
+		Height:      1,
+		Thumbnails: map[string]string{
+			"small":  "3f2a9c0e1b7d4a5f8c6e2d1b0a9f8e7d_small.png",
+			"medium": "3f2a9c0e1b7d4a5f8c6e2d1b0a9f8e7d_medium.png",
+			"large":  "3f2a9c0e1b7d4a5f8c6e2d1b0a9f8e7d_large.png",
+		},
+		CreatedAt: time.Date(2024, 3, 16, 10, 41, 18, 0, time.UTC),
+	}
+}
+
+// imageForm makes the multipart upload form, the file part is left out if file is nil.
+func imageForm(kind string, file []byte) string {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err := writer.SetBoundary(formBoundary); err != nil {
+		panic(err)
+	}
+	if err := writer.WriteField("kind", kind); err != nil {
+		panic(err)
+	}
+	if file != nil {
+		part, err := writer.CreateFormFile("file", "image.png")
+		if err != nil {
+			panic(err)
+		}
+		if _, err := part.Write(file); err != nil {
+			panic(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
+
+func hasMissing(ids []int) bool {
+	for _, id := range ids {
+		if id == missingID {
+			return true
+		}
+	}
+	return false
+}
+
+func (fakeFilmoteka) GetMovies(ctx context.Context, model filmoteka.GetMoviesModel) ([]movie.Entity, int, error) {
+	movies := []movie.Entity{fakeMovie(1), fakeMovie(2)}
+	if model.SearchTerm != "" {
+		movies[1].MatchedAlias = "Drive (2011)"
+	}
+	// movies come in the most preferred locale, as if they were all translated to it
+	if locales := locale.FromContext(ctx); len(locales) != 0 {
+		for i := range movies {
+			movies[i].Locale = locales[0]
+		}
+	}
+	return movies, 2, nil
+}
+
+func (fakeFilmoteka) GetMovieByID(_ context.Context, id int) (movie.Entity, error) {
+	if id == missingID {
+		return movie.Entity{}, pgx.ErrNoRows
+	}
+	return fakeMovie(id), nil
+}
+
+func (fakeFilmoteka) CreateMovie(_ context.Context, model filmoteka.CreateMovieModel) (movie.Entity, error) {
+	if hasMissing(model.StarsID) {
+		return movie.Entity{}, core.ErrStarIDNotExists
+	}
+	return fakeMovie(1), nil
+}
+
+func (fakeFilmoteka) UpdateMovie(_ context.Context, id int, model filmoteka.UpdateMovieModel) (movie.Entity, error) {
+	if hasMissing(model.StarsID) {
+		return movie.Entity{}, core.ErrStarIDNotExists
+	}
+	if id == missingID {
+		return movie.Entity{}, pgx.ErrNoRows
+	}
+	return fakeMovie(id), nil
+}
+
+func (fakeFilmoteka) DeleteMovie(_ context.Context, id int) error {
+	if id == missingID {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (fakeFilmoteka) GetMoviesByStarIDs(_ context.Context, ids []int) (map[int][]movie.Entity, error) {
+	movies := make(map[int][]movie.Entity, len(ids))
+	for _, id := range ids {
+		movies[id] = []movie.Entity{fakeMovie(1)}
+	}
+	return movies, nil
+}
+
+func (fakeFilmoteka) GetStars(_ context.Context, model filmoteka.GetStarsModel) ([]star.Entity, int, error) {
+	stars := []star.Entity{fakeStar(1), fakeStar(2)}
+	if model.SearchTerm != "" {
+		stars[1].MatchedAlias = "Ryan Gosling"
+	}
+	return stars, 2, nil
+}
+
+func (fakeFilmoteka) GetStarByID(_ context.Context, id int) (star.Entity, []movie.Entity, error) {
+	if id == missingID {
+		return star.Entity{}, nil, pgx.ErrNoRows
+	}
+	return fakeStar(id), []movie.Entity{fakeMovie(1)}, nil
+}
+
+func (fakeFilmoteka) CreateStar(_ context.Context, _ filmoteka.CreateStarModel) (star.Entity, error) {
+	return fakeStar(1), nil
+}
+
+func (fakeFilmoteka) UpdateStar(_ context.Context, id int, _ filmoteka.UpdateStarModel) (star.Entity, []movie.Entity, error) {
+	if id == missingID {
+		return star.Entity{}, nil, pgx.ErrNoRows
+	}
+	return fakeStar(id), []movie.Entity{fakeMovie(1)}, nil
+}
+
+func (fakeFilmoteka) DeleteStar(_ context.Context, id int) error {
+	if id == missingID {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (fakeFilmoteka) GetStarsByMovieIDs(_ context.Context, ids []int) (map[int][]star.Entity, error) {
+	stars := make(map[int][]star.Entity, len(ids))
+	for _, id := range ids {
+		stars[id] = []star.Entity{fakeStar(1)}
+	}
+	return stars, nil
+}
+
+func (fakeFilmoteka) BatchMovies(_ context.Context, model filmoteka.BatchMoviesModel) ([]movie.BatchResult, error) {
+	var failed bool
+	results := make([]movie.BatchResult, len(model.Operations))
+	for i, op := range model.Operations {
+		if op.ID == missingID {
+			results[i].Err = pgx.ErrNoRows
+			failed = true
+			continue
+		}
+		results[i].Entity = fakeMovie(op.ID)
+	}
+	if failed && model.Atomic {
+		return results, core.ErrBatchAborted
+	}
+	return results, nil
+}
+
+func (fakeFilmoteka) BatchStars(_ context.Context, model filmoteka.BatchStarsModel) ([]star.BatchResult, error) {
+	var failed bool
+	results := make([]star.BatchResult, len(model.Operations))
+	for i, op := range model.Operations {
+		if op.ID == missingID {
+			results[i].Err = pgx.ErrNoRows
+			failed = true
+			continue
+		}
+		results[i].Entity = fakeStar(op.ID)
+	}
+	if failed && model.Atomic {
+		return results, core.ErrBatchAborted
+	}
+	return results, nil
+}
+
+func (fakeFilmoteka) ExportMovies(_ context.Context, _ filmoteka.ExportMoviesModel, fn func(movie.ExportEntity) error) error {
+	return fn(movie.ExportEntity{Entity: fakeMovie(1), StarsID: []int{1, 2}})
+}
+
+func (fakeFilmoteka) ExportStars(_ context.Context, fn func(star.ExportEntity) error) error {
+	return fn(star.ExportEntity{Entity: fakeStar(1)})
+}
+
+func (fakeFilmoteka) UploadMovieImage(_ context.Context, id int, model filmoteka.UploadMovieImageModel) (imagestore.Entity, error) {
+	if id == missingID {
+		return imagestore.Entity{}, pgx.ErrNoRows
+	}
+	if !bytes.Equal(model.Data, fakePNG) {
+		return imagestore.Entity{}, core.ErrUnsupportedImage
+	}
+	return fakeImage(1, &id, nil), nil
+}
+
+func (fakeFilmoteka) UploadStarImage(_ context.Context, id int, model filmoteka.UploadStarImageModel) (imagestore.Entity, error) {
+	if id == missingID {
+		return imagestore.Entity{}, pgx.ErrNoRows
+	}
+	if !bytes.Equal(model.Data, fakePNG) {
+		return imagestore.Entity{}, core.ErrUnsupportedImage
+	}
+	return fakeImage(1, nil, &id), nil
+}
+
+func (fakeFilmoteka) GetImagesByMovieIDs(_ context.Context, ids []int) (map[int][]imagestore.Entity, error) {
+	images := make(map[int][]imagestore.Entity, len(ids))
+	for _, id := range ids {
+		movieID := id
+		images[id] = []imagestore.Entity{fakeImage(1, &movieID, nil)}
+	}
+	return images, nil
+}
+
+func (fakeFilmoteka) GetImagesByStarIDs(_ context.Context, ids []int) (map[int][]imagestore.Entity, error) {
+	images := make(map[int][]imagestore.Entity, len(ids))
+	for _, id := range ids {
+		starID := id
+		images[id] = []imagestore.Entity{fakeImage(2, nil, &starID)}
+	}
+	return images, nil
+}
+
+func (fakeFilmoteka) UpsertMovieTranslation(_ context.Context, id int, localeCode string, model filmoteka.UpsertMovieTranslationModel) (movie.TranslationEntity, error) {
+	if id == missingID {
+		return movie.TranslationEntity{}, pgx.ErrNoRows
+	}
+	date := time.Date(2024, 3, 15, 21, 16, 36, 0, time.UTC)
+	return movie.TranslationEntity{
+		MovieID:     id,
+		Locale:      localeCode,
+		Title:       model.Title,
+		Description: model.Description,
+		CreatedAt:   date,
+		UpdatedAt:   date,
+	}, nil
+}
+
+func (fakeFilmoteka) DeleteMovieTranslation(_ context.Context, id int, _ string) error {
+	if id == missingID {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (fakeFilmoteka) GetMovieAliases(_ context.Context, id int) ([]movie.AliasEntity, error) {
+	if id == missingID {
+		return nil, pgx.ErrNoRows
+	}
+	return []movie.AliasEntity{{ID: 1, MovieID: id, Alias: takenAlias, Kind: "original", CreatedAt: time.Date(2024, 3, 15, 21, 16, 36, 0, time.UTC)}}, nil
+}
+
+func (fakeFilmoteka) CreateMovieAlias(_ context.Context, id int, model filmoteka.CreateMovieAliasModel) (movie.AliasEntity, error) {
+	if id == missingID {
+		return movie.AliasEntity{}, pgx.ErrNoRows
+	}
+	if model.Alias == takenAlias {
+		return movie.AliasEntity{}, core.ErrAliasExists
+	}
+	return movie.AliasEntity{ID: 2, MovieID: id, Alias: model.Alias, Kind: model.Kind, CreatedAt: time.Date(2024, 3, 15, 21, 16, 36, 0, time.UTC)}, nil
+}
+
+func (fakeFilmoteka) DeleteMovieAlias(_ context.Context, _, aliasID int) error {
+	if aliasID == missingID {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (fakeFilmoteka) GetStarAliases(_ context.Context, id int) ([]star.AliasEntity, error) {
+	if id == missingID {
+		return nil, pgx.ErrNoRows
+	}
+	return []star.AliasEntity{{ID: 1, StarID: id, Alias: takenAlias, Kind: "stage", CreatedAt: time.Date(2024, 3, 15, 21, 16, 36, 0, time.UTC)}}, nil
+}
+
+func (fakeFilmoteka) CreateStarAlias(_ context.Context, id int, model filmoteka.CreateStarAliasModel) (star.AliasEntity, error) {
+	if id == missingID {
+		return star.AliasEntity{}, pgx.ErrNoRows
+	}
+	if model.Alias == takenAlias {
+		return star.AliasEntity{}, core.ErrAliasExists
+	}
+	return star.AliasEntity{ID: 2, StarID: id, Alias: model.Alias, Kind: model.Kind, CreatedAt: time.Date(2024, 3, 15, 21, 16, 36, 0, time.UTC)}, nil
+}
+
+func (fakeFilmoteka) DeleteStarAlias(_ context.Context, _, aliasID int) error {
+	if aliasID == missingID {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (fakeFilmoteka) GetImageFile(_ context.Context, key string) (storage.Object, error) {
+	if key == missingImageKey {
+		return storage.Object{}, storage.ErrNotFound
+	}
+	return storage.Object{
+		Body:        io.NopCloser(bytes.NewReader(fakePNG)),
+		ContentType: "image/png",
+		Size:        int64(len(fakePNG)),
+	}, nil
+}
+
+var contractCases = []contractCase{
+	{name: "login", method: http.MethodPost, path: "/api/v1/auth/login", body: `{"username":"admin","password":"secret"}`, status: http.StatusOK},
+	{name: "login wrong password", method: http.MethodPost, path: "/api/v1/auth/login", body: `{"username":"admin","password":"wrong"}`, status: http.StatusUnauthorized},
+	{name: "login timeout", method: http.MethodPost, path: "/api/v1/auth/login", body: `{"username":"` + timeoutUsername + `","password":"secret"}`, status: http.StatusServiceUnavailable},
+	{name: "login invalid body", method: http.MethodPost, path: "/api/v1/auth/login", body: `{`, status: http.StatusBadRequest, invalid: true},
+	{name: "login validation", method: http.MethodPost, path: "/api/v1/auth/login", body: `{}`, status: http.StatusUnprocessableEntity},
+	{name: "login unknown field", method: http.MethodPost, path: "/api/v1/auth/login", body: `{"username":"admin","password":"secret","role":"admin"}`, status: http.StatusBadRequest},
+	{name: "sign-up", method: http.MethodPost, path: "/api/v1/auth/signup", body: `{"username":"newbie","password":"secret","role":"user"}`, status: http.StatusCreated},
+	{name: "sign-up taken", method: http.MethodPost, path: "/api/v1/auth/signup", body: `{"username":"admin","password":"secret","role":"admin"}`, status: http.StatusConflict},
+	{name: "sign-up validation", method: http.MethodPost, path: "/api/v1/auth/signup", body: `{"username":"root","password":"secret","role":"root"}`, status: http.StatusUnprocessableEntity},
+
+	{name: "openapi", method: http.MethodGet, path: "/api/v1/openapi.json", status: http.StatusOK},
+	{name: "swagger ui", method: http.MethodGet, path: "/api/v1/docs", status: http.StatusOK},
+
+	{name: "movies", method: http.MethodGet, path: "/api/v1/filmoteka/movies?q=drive&sort=rating,desc&page=1&limit=2", role: core.UserRole, status: http.StatusOK},
+	{name: "movies xml", method: http.MethodGet, path: "/api/v1/filmoteka/movies", role: core.UserRole, accept: "application/xml", status: http.StatusOK},
+	{name: "movies csv", method: http.MethodGet, path: "/api/v1/filmoteka/movies", role: core.UserRole, accept: "text/csv", status: http.StatusOK},
+	{name: "movies not acceptable", method: http.MethodGet, path: "/api/v1/filmoteka/movies", role: core.UserRole, accept: "image/png", status: http.StatusNotAcceptable},
+	{name: "movies english", method: http.MethodGet, path: "/api/v1/filmoteka/movies", role: core.UserRole, acceptLanguage: "en-US,en;q=0.9", status: http.StatusOK},
+	{name: "movies no token", method: http.MethodGet, path: "/api/v1/filmoteka/movies", status: http.StatusUnauthorized},
+	{name: "movies invalid token", method: http.MethodGet, path: "/api/v1/filmoteka/movies", header: "Bearer invalid", status: http.StatusUnauthorized},
+	{name: "movies malformed header", method: http.MethodGet, path: "/api/v1/filmoteka/movies", header: "Token", status: http.StatusUnprocessableEntity},
+	{name: "movies invalid page", method: http.MethodGet, path: "/api/v1/filmoteka/movies?page=first", role: core.UserRole, status: http.StatusUnprocessableEntity, invalid: true},
+	{name: "movies limit too big", method: http.MethodGet, path: "/api/v1/filmoteka/movies?limit=1000", role: core.UserRole, status: http.StatusUnprocessableEntity, invalid: true},
+	{name: "movies invalid sort", method: http.MethodGet, path: "/api/v1/filmoteka/movies?sort=budget", role: core.UserRole, status: http.StatusUnprocessableEntity},
+	{name: "create movie", method: http.MethodPost, path: "/api/v1/filmoteka/movies", role: core.AdminRole, body: `{"title":"Drive","description":"Night Call","release_date":"2011-11-03T00:00:00Z","rating":8,"stars_id":[1]}`, status: http.StatusCreated},
+	{name: "create movie forbidden", method: http.MethodPost, path: "/api/v1/filmoteka/movies", role: core.UserRole, body: `{}`, status: http.StatusForbidden},
+	{name: "create movie missing star", method: http.MethodPost, path: "/api/v1/filmoteka/movies", role: core.AdminRole, body: `{"title":"Drive","description":"Night Call","release_date":"2011-11-03T00:00:00Z","rating":8,"stars_id":[404]}`, status: http.StatusNotFound},
+	{name: "create movie invalid body", method: http.MethodPost, path: "/api/v1/filmoteka/movies", role: core.AdminRole, body: `{`, status: http.StatusBadRequest, invalid: true},
+	{name: "create movie validation", method: http.MethodPost, path: "/api/v1/filmoteka/movies", role: core.AdminRole, body: `{}`, status: http.StatusUnprocessableEntity},
+
+	{name: "movie", method: http.MethodGet, path: "/api/v1/filmoteka/movie/1", role: core.UserRole, status: http.StatusOK},
+	{name: "movie not found", method: http.MethodGet, path: "/api/v1/filmoteka/movie/404", role: core.UserRole, status: http.StatusNotFound},
+	{name: "movie invalid id", method: http.MethodGet, path: "/api/v1/filmoteka/movie/first", role: core.UserRole, status: http.StatusUnprocessableEntity, invalid: true},
+	{name: "movie zero id", method: http.MethodGet, path: "/api/v1/filmoteka/movie/0", role: core.UserRole, status: http.StatusUnprocessableEntity, invalid: true},
+	{name: "update movie", method: http.MethodPatch, path: "/api/v1/filmoteka/movie/1", role: core.AdminRole, body: `{"rating":9}`, status: http.StatusOK},
+	{name: "update movie forbidden", method: http.MethodPatch, path: "/api/v1/filmoteka/movie/1", role: core.UserRole, body: `{"rating":9}`, status: http.StatusForbidden},
+	{name: "update movie not found", method: http.MethodPatch, path: "/api/v1/filmoteka/movie/404", role: core.AdminRole, body: `{"rating":9}`, status: http.StatusNotFound},
+	{name: "update movie missing star", method: http.MethodPatch, path: "/api/v1/filmoteka/movie/1", role: core.AdminRole, body: `{"rating":9,"stars_id":[404]}`, status: http.StatusNotFound},
+	{name: "update movie invalid body", method: http.MethodPatch, path: "/api/v1/filmoteka/movie/1", role: core.AdminRole, body: `{`, status: http.StatusBadRequest, invalid: true},
+	{name: "update movie validation", method: http.MethodPatch, path: "/api/v1/filmoteka/movie/1", role: core.AdminRole, body: `{}`, status: http.StatusUnprocessableEntity},
+	{name: "delete movie", method: http.MethodDelete, path: "/api/v1/filmoteka/movie/1", role: core.AdminRole, status: http.StatusOK},
+	{name: "delete movie not found", method: http.MethodDelete, path: "/api/v1/filmoteka/movie/404", role: core.AdminRole, status: http.StatusNotFound},
+	{name: "upsert movie translation", method: http.MethodPut, path: "/api/v1/filmoteka/movie/1/translations/en", role: core.AdminRole, body: `{"title":"Drive","description":"Night Call"}`, status: http.StatusOK},
+	{name: "upsert movie translation forbidden", method: http.MethodPut, path: "/api/v1/filmoteka/movie/1/translations/en", role: core.UserRole, body: `{"title":"Drive","description":"Night Call"}`, status: http.StatusForbidden},
+	{name: "upsert movie translation not found", method: http.MethodPut, path: "/api/v1/filmoteka/movie/404/translations/en", role: core.AdminRole, body: `{"title":"Drive","description":"Night Call"}`, status: http.StatusNotFound},
+	{name: "upsert movie translation unsupported locale", method: http.MethodPut, path: "/api/v1/filmoteka/movie/1/translations/fr", role: core.AdminRole, body: `{"title":"Drive","description":"Night Call"}`, status: http.StatusNotFound},
+	{name: "upsert movie translation default locale", method: http.MethodPut, path: "/api/v1/filmoteka/movie/1/translations/ru", role: core.AdminRole, body: `{"title":"Драйв","description":"Ночной звонок"}`, status: http.StatusNotFound},
+	{name: "upsert movie translation invalid locale", method: http.MethodPut, path: "/api/v1/filmoteka/movie/1/translations/english", role: core.AdminRole, body: `{"title":"Drive","description":"Night Call"}`, status: http.StatusUnprocessableEntity, invalid: true},
+	{name: "upsert movie translation validation", method: http.MethodPut, path: "/api/v1/filmoteka/movie/1/translations/en", role: core.AdminRole, body: `{"title":""}`, status: http.StatusUnprocessableEntity},
+	{name: "delete movie translation", method: http.MethodDelete, path: "/api/v1/filmoteka/movie/1/translations/en", role: core.AdminRole, status: http.StatusOK},
+	{name: "delete movie translation not found", method: http.MethodDelete, path: "/api/v1/filmoteka/movie/404/translations/en", role: core.AdminRole, status: http.StatusNotFound},
+	{name: "movie aliases", method: http.MethodGet, path: "/api/v1/filmoteka/movie/1/aliases", role: core.UserRole, status: http.StatusOK},
+	{name: "movie aliases not found", method: http.MethodGet, path: "/api/v1/filmoteka/movie/404/aliases", role: core.UserRole, status: http.StatusNotFound},
+	{name: "create movie alias", method: http.MethodPost, path: "/api/v1/filmoteka/movie/1/aliases", role: core.AdminRole, body: `{"alias":"Drive (2011)","kind":"regional"}`, status: http.StatusCreated},
+	{name: "create movie alias forbidden", method: http.MethodPost, path: "/api/v1/filmoteka/movie/1/aliases", role: core.UserRole, body: `{"alias":"Drive (2011)","kind":"regional"}`, status: http.StatusForbidden},
+	{name: "create movie alias not found", method: http.MethodPost, path: "/api/v1/filmoteka/movie/404/aliases", role: core.AdminRole, body: `{"alias":"Drive (2011)","kind":"regional"}`, status: http.StatusNotFound},
+	{name: "create movie alias exists", method: http.MethodPost, path: "/api/v1/filmoteka/movie/1/aliases", role: core.AdminRole, body: `{"alias":"Taken","kind":"original"}`, status: http.StatusConflict},
+	{name: "create movie alias validation", method: http.MethodPost, path: "/api/v1/filmoteka/movie/1/aliases", role: core.AdminRole, body: `{"alias":"Drive","kind":"stage"}`, status: http.StatusUnprocessableEntity},
+	{name: "delete movie alias", method: http.MethodDelete, path: "/api/v1/filmoteka/movie/1/aliases/1", role: core.AdminRole, status: http.StatusOK},
+	{name: "delete movie alias not found", method: http.MethodDelete, path: "/api/v1/filmoteka/movie/1/aliases/404", role: core.AdminRole, status: http.StatusNotFound},
+	{name: "upload movie image", method: http.MethodPost, path: "/api/v1/filmoteka/movie/1/images", role: core.AdminRole, body: imageForm("poster", fakePNG), contentType: multipartForm, status: http.StatusCreated},
+	{name: "upload movie image forbidden", method: http.MethodPost, path: "/api/v1/filmoteka/movie/1/images", role: core.UserRole, body: imageForm("poster", fakePNG), contentType: multipartForm, status: http.StatusForbidden},
+	{name: "upload movie image not found", method: http.MethodPost, path: "/api/v1/filmoteka/movie/404/images", role: core.AdminRole, body: imageForm("poster", fakePNG), contentType: multipartForm, status: http.StatusNotFound},
+	{name: "upload movie image unsupported", method: http.MethodPost, path: "/api/v1/filmoteka/movie/1/images", role: core.AdminRole, body: imageForm("still", []byte("GIF89a")), contentType: multipartForm, status: http.StatusUnsupportedMediaType},
+	{name: "upload movie image no file", method: http.MethodPost, path: "/api/v1/filmoteka/movie/1/images", role: core.AdminRole, body: imageForm("poster", nil), contentType: multipartForm, status: http.StatusBadRequest, invalid: true},
+	{name: "upload movie image validation", method: http.MethodPost, path: "/api/v1/filmoteka/movie/1/images", role: core.AdminRole, body: imageForm("headshot", fakePNG), contentType: multipartForm, status: http.StatusUnprocessableEntity, invalid: true},
+
+	{name: "stars", method: http.MethodGet, path: "/api/v1/filmoteka/stars?page=1&limit=2", role: core.UserRole, status: http.StatusOK},
+	{name: "stars search", method: http.MethodGet, path: "/api/v1/filmoteka/stars?q=gosling", role: core.UserRole, status: http.StatusOK},
+	{name: "stars xml", method: http.MethodGet, path: "/api/v1/filmoteka/stars", role: core.UserRole, accept: "application/xml", status: http.StatusOK},
+	{name: "stars not acceptable", method: http.MethodGet, path: "/api/v1/filmoteka/stars", role: core.UserRole, accept: "image/png", status: http.StatusNotAcceptable},
+	{name: "create star", method: http.MethodPost, path: "/api/v1/filmoteka/stars", role: core.AdminRole, body: `{"name":"Ryan Gosling","sex":"male","birth_date":"1980-11-12T00:00:00Z"}`, status: http.StatusCreated},
+	{name: "create star forbidden", method: http.MethodPost, path: "/api/v1/filmoteka/stars", role: core.UserRole, body: `{}`, status: http.StatusForbidden},
+	{name: "create star validation", method: http.MethodPost, path: "/api/v1/filmoteka/stars", role: core.AdminRole, body: `{"name":"Ryan Gosling","sex":"robot"}`, status: http.StatusUnprocessableEntity},
+
+	{name: "star", method: http.MethodGet, path: "/api/v1/filmoteka/star/1", role: core.UserRole, status: http.StatusOK},
+	{name: "star not found", method: http.MethodGet, path: "/api/v1/filmoteka/star/404", role: core.UserRole, status: http.StatusNotFound},
+	{name: "update star", method: http.MethodPatch, path: "/api/v1/filmoteka/star/1", role: core.AdminRole, body: `{"name":"Zendaya"}`, status: http.StatusOK},
+	{name: "update star not found", method: http.MethodPatch, path: "/api/v1/filmoteka/star/404", role: core.AdminRole, body: `{"name":"Zendaya"}`, status: http.StatusNotFound},
+	{name: "update star invalid id", method: http.MethodPatch, path: "/api/v1/filmoteka/star/first", role: core.AdminRole, body: `{"name":"Zendaya"}`, status: http.StatusUnprocessableEntity, invalid: true},
+	{name: "delete star", method: http.MethodDelete, path: "/api/v1/filmoteka/star/1", role: core.AdminRole, status: http.StatusOK},
+	{name: "delete star not found", method: http.MethodDelete, path: "/api/v1/filmoteka/star/404", role: core.AdminRole, status: http.StatusNotFound},
+	{name: "star aliases", method: http.MethodGet, path: "/api/v1/filmoteka/star/1/aliases", role: core.UserRole, status: http.StatusOK},
+	{name: "star aliases not found", method: http.MethodGet, path: "/api/v1/filmoteka/star/404/aliases", role: core.UserRole, status: http.StatusNotFound},
+	{name: "create star alias", method: http.MethodPost, path: "/api/v1/filmoteka/star/1/aliases", role: core.AdminRole, body: `{"alias":"Ryan Gosling","kind":"transliteration"}`, status: http.StatusCreated},
+	{name: "create star alias exists", method: http.MethodPost, path: "/api/v1/filmoteka/star/1/aliases", role: core.AdminRole, body: `{"alias":"Taken","kind":"stage"}`, status: http.StatusConflict},
+	{name: "create star alias validation", method: http.MethodPost, path: "/api/v1/filmoteka/star/1/aliases", role: core.AdminRole, body: `{"alias":"Ryan Gosling","kind":"original"}`, status: http.StatusUnprocessableEntity},
+	{name: "delete star alias", method: http.MethodDelete, path: "/api/v1/filmoteka/star/1/aliases/1", role: core.AdminRole, status: http.StatusOK},
+	{name: "delete star alias forbidden", method: http.MethodDelete, path: "/api/v1/filmoteka/star/1/aliases/1", role: core.UserRole, status: http.StatusForbidden},
+	{name: "delete star alias not found", method: http.MethodDelete, path: "/api/v1/filmoteka/star/1/aliases/404", role: core.AdminRole, status: http.StatusNotFound},
+	{name: "upload star image", method: http.MethodPost, path: "/api/v1/filmoteka/star/1/images", role: core.AdminRole, body: imageForm("headshot", fakePNG), contentType: multipartForm, status: http.StatusCreated},
+	{name: "upload star image not found", method: http.MethodPost, path: "/api/v1/filmoteka/star/404/images", role: core.AdminRole, body: imageForm("headshot", fakePNG), contentType: multipartForm, status: http.StatusNotFound},
+
+	{name: "image", method: http.MethodGet, path: "/api/v1/images/3f2a9c0e1b7d4a5f8c6e2d1b0a9f8e7d_small.png", status: http.StatusOK},
+	{name: "image not found", method: http.MethodGet, path: "/api/v1/images/" + missingImageKey, status: http.StatusNotFound},
+
+	{name: "batch movies", method: http.MethodPost, path: "/api/v1/filmoteka/movies:batch", role: core.AdminRole, body: `{"operations":[{"op":"create","data":{"title":"Drive","description":"Night Call","release_date":"2011-11-03T00:00:00Z","rating":8,"stars_id":[1]}},{"op":"update","id":404,"data":{"rating":9}},{"op":"delete"}]}`, status: http.StatusOK},
+	{name: "batch movies aborted", method: http.MethodPost, path: "/api/v1/filmoteka/movies:batch?atomic=true", role: core.AdminRole, body: `{"operations":[{"op":"update","id":1,"data":{"rating":9}},{"op":"delete","id":404}]}`, status: http.StatusUnprocessableEntity},
+	{name: "batch movies validation", method: http.MethodPost, path: "/api/v1/filmoteka/movies:batch", role: core.AdminRole, body: `{"operations":[]}`, status: http.StatusUnprocessableEntity},
+	{name: "batch movies invalid atomic", method: http.MethodPost, path: "/api/v1/filmoteka/movies:batch?atomic=yes", role: core.AdminRole, body: `{"operations":[]}`, status: http.StatusUnprocessableEntity, invalid: true},
+	{name: "batch stars", method: http.MethodPost, path: "/api/v1/filmoteka/stars:batch", role: core.AdminRole, body: `{"operations":[{"op":"create","data":{"name":"Ryan Gosling","sex":"male","birth_date":"1980-11-12T00:00:00Z"}},{"op":"delete","id":404}]}`, status: http.StatusOK},
+	{name: "batch stars forbidden", method: http.MethodPost, path: "/api/v1/filmoteka/stars:batch", role: core.UserRole, body: `{"operations":[]}`, status: http.StatusForbidden},
+
+	{name: "export movies", method: http.MethodGet, path: "/api/v1/filmoteka/export/movies?q=drive", role: core.UserRole, status: http.StatusOK},
+	{name: "export movies jsonl", method: http.MethodGet, path: "/api/v1/filmoteka/export/movies?format=jsonl", role: core.UserRole, status: http.StatusOK},
+	{name: "export movies invalid format", method: http.MethodGet, path: "/api/v1/filmoteka/export/movies?format=xlsx", role: core.UserRole, status: http.StatusUnprocessableEntity, invalid: true},
+	{name: "export stars", method: http.MethodGet, path: "/api/v1/filmoteka/export/stars?format=jsonl", role: core.UserRole, status: http.StatusOK},
+
+	{name: "graphql", method: http.MethodPost, path: "/api/v1/graphql", role: core.UserRole, body: `{"query":"{ movies(limit: 2) { items { id title stars { name } } } }"}`, status: http.StatusOK},
+	{name: "graphql invalid body", method: http.MethodPost, path: "/api/v1/graphql", role: core.UserRole, body: `{"query":""}`, status: http.StatusBadRequest},
+	{name: "graphql no token", method: http.MethodPost, path: "/api/v1/graphql", body: `{"query":"{ movies { items { id } } }"}`, status: http.StatusUnauthorized},
+}
+
+func newContractResolver(t *testing.T) (*Resolver, map[string]string) {
+	t.Helper()
+	authService, err := auth.New(
+		&memoryUsers{users: map[string]user.CreateEntity{}},
+		&jwt.Config{Secret: "test-secret", AccessTokenExpiration: 15 * time.Minute},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	tokens := make(map[string]string)
+	for _, role := range []string{core.AdminRole, core.UserRole} {
+		if _, err := authService.SignUp(context.Background(), auth.SignUpModel{Username: role, Password: "secret", Role: role}); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		token, err := authService.CreateToken(context.Background(), role, role)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		tokens[role] = token.AccessToken
+	}
+
+	locales, err := locale.NewNegotiator([]string{"ru", "en"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	resolver, err := NewResolver("", Options{Locales: locales}, fakeFilmoteka{}, authService)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	return resolver, tokens
+}
+
+// TestContract runs every route against the OpenAPI document: requests must match a
+// documented operation and responses must match the documented status and schema.
+func TestContract(t *testing.T) {
+	ctx := context.Background()
+
+	spec, err := openapi3.NewLoader().LoadFromData(doc.OpenAPI)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := spec.Validate(ctx); err != nil {
+		t.Fatalf("invalid openapi document: %s", err.Error())
+	}
+
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	resolver, tokens := newContractResolver(t)
+	handler := resolver.Handler()
+
+	coveredRoutes := make(map[string]bool)
+	coveredOperations := make(map[string]bool)
+
+	for _, tc := range contractCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, specHost+tc.path, strings.NewReader(tc.body))
+			switch {
+			case tc.contentType != "":
+				req.Header.Set("Content-Type", tc.contentType)
+			case tc.body != "":
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			if tc.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tc.acceptLanguage)
+			}
+			switch {
+			case tc.header != "":
+				req.Header.Set("Authorization", tc.header)
+			case tc.role != "":
+				req.Header.Set("Authorization", "Bearer "+tokens[tc.role])
+			}
+
+			route, pathParams, err := router.FindRoute(req)
+			if err != nil {
+				t.Fatalf("undocumented operation %s %s: %s", tc.method, tc.path, err.Error())
+			}
+			_, pattern := resolver.mux.Handler(req)
+			coveredRoutes[pattern] = true
+			coveredOperations[tc.method+" "+route.Path] = true
+
+			requestInput := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				},
+			}
+			err = openapi3filter.ValidateRequest(ctx, requestInput)
+			if tc.invalid && err == nil {
+				t.Fatalf("expected request to break the spec")
+			}
+			if !tc.invalid && err != nil {
+				t.Fatalf("request does not match the spec: %s", err.Error())
+			}
+
+			req.Body = io.NopCloser(strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+
+			err = openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: requestInput,
+				Status:                 rec.Code,
+				Header:                 rec.Header(),
+				Body:                   io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+				Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+			})
+			if err != nil {
+				t.Fatalf("response does not match the spec: %s", err.Error())
+			}
+		})
+	}
+
+	for _, route := range resolver.routes() {
+		if !coveredRoutes[route.pattern] {
+			t.Errorf("route %s is not covered by contract cases", route.pattern)
+		}
+	}
+
+	var operations []string
+	for path, item := range spec.Paths.Map() {
+		for method := range item.Operations() {
+			operations = append(operations, method+" "+path)
+		}
+	}
+	sort.Strings(operations)
+	for _, operation := range operations {
+		if !coveredOperations[operation] {
+			t.Errorf("operation %s is not covered by contract cases", operation)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// corsExposedHeaders are the response headers of the API that scripts may read.
+var corsExposedHeaders = strings.Join([]string{
+	requestIDHeader,
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+	"Retry-After",
+	"X-Total-Count",
+	"X-Page-Count",
+	"X-Current-Page",
+	"X-Per-Page",
+	"Content-Disposition",
+}, ", ")
+
+// CORS lets browser clients on other origins call the API. No allowed origins turn it off.
+type CORS struct {
+	// AllowedOrigins are origins like https://app.example.com, "*" allows any
+	// but is ignored when AllowCredentials is set
+	AllowedOrigins []string
+	AllowedMethods []string
+	// AllowedHeaders are the request headers scripts may set, "*" allows any
+	AllowedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight response
+	MaxAge time.Duration
+}
+
+func (c CORS) allows(values []string, value string) bool {
+	for _, allowed := range values {
+		if allowed == "*" || strings.EqualFold(allowed, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowsOrigin reports whether responses to origin get the CORS headers. With credentials
+// only the listed origins do, so that "*" never lets any site read them.
+func (c CORS) allowsOrigin(origin string) bool {
+	if !c.AllowCredentials {
+		return c.allows(c.AllowedOrigins, origin)
+	}
+
+	return slices.ContainsFunc(c.AllowedOrigins, func(allowed string) bool {
+		return strings.EqualFold(allowed, origin)
+	})
+}
+
+// corsMiddleware answers preflight requests before they reach the routes, so no JWT is
+// needed for them, and adds the CORS headers to the responses to allowed origins.
+// Requests from other origins are served without the headers and blocked by browsers.
+func corsMiddleware(cors CORS, next http.Handler) http.Handler {
+	// an empty origin, e.g. of an empty env var, allows nothing
+	cors.AllowedOrigins = slices.DeleteFunc(slices.Clone(cors.AllowedOrigins), func(origin string) bool {
+		return origin == ""
+	})
+	if len(cors.AllowedOrigins) == 0 {
+		return next
+	}
+
+	methods := strings.Join(cors.AllowedMethods, ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		header := w.Header()
+		header.Add("Vary", "Origin")
+
+		origin := req.Header.Get("Origin")
+		preflight := req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if origin == "" || !cors.allowsOrigin(origin) {
+			if preflight {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		if cors.AllowCredentials {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Credentials", "true")
+		} else if slices.Contains(cors.AllowedOrigins, "*") {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+
+		if !preflight {
+			header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		if !cors.allows(cors.AllowedMethods, req.Header.Get("Access-Control-Request-Method")) {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		for _, name := range strings.Split(req.Header.Get("Access-Control-Request-Headers"), ",") {
+			if name = strings.TrimSpace(name); name != "" && !cors.allows(cors.AllowedHeaders, name) {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+
+		header.Set("Access-Control-Allow-Methods", methods)
+		if requested := req.Header.Get("Access-Control-Request-Headers"); requested != "" {
+			header.Set("Access-Control-Allow-Headers", requested)
+		}
+		if cors.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(cors.MaxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"vk-test-task/internal/core"
+)
+
+const spaOrigin = "https://app.example.com"
+
+func newCORSResolver(t *testing.T, cors CORS) (*Resolver, map[string]string) {
+	t.Helper()
+
+	contractResolver, tokens := newContractResolver(t)
+	resolver, err := NewResolver("", Options{CORS: cors}, fakeFilmoteka{}, contractResolver.authService)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	return resolver, tokens
+}
+
+func preflight(resolver *Resolver, origin, method, headers string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/filmoteka/movies", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		req.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	rec := httptest.NewRecorder()
+	resolver.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORSPreflight(t *testing.T) {
+	resolver, _ := newCORSResolver(t, CORS{
+		AllowedOrigins: []string{spaOrigin},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		MaxAge:         10 * time.Minute,
+	})
+
+	for _, test := range []struct {
+		Name    string
+		Origin  string
+		Method  string
+		Headers string
+		Allowed bool
+	}{
+		{Name: "allowed", Origin: spaOrigin, Method: http.MethodPost, Headers: "authorization, content-type", Allowed: true},
+		{Name: "other origin", Origin: "https://evil.example.com", Method: http.MethodPost},
+		{Name: "method not allowed", Origin: spaOrigin, Method: http.MethodDelete},
+		{Name: "header not allowed", Origin: spaOrigin, Method: http.MethodGet, Headers: "X-Secret"},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			// no token is sent, preflight is answered before the JWT is checked
+			rec := preflight(resolver, test.Origin, test.Method, test.Headers)
+
+			if rec.Code != http.StatusNoContent {
+				t.Fatalf("wrong status. Expected %d but got %d", http.StatusNoContent, rec.Code)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Methods") != ""; got != test.Allowed {
+				t.Fatalf("wrong preflight result. Expected allowed %t, headers %v", test.Allowed, rec.Header())
+			}
+			if !test.Allowed {
+				return
+			}
+
+			for header, want := range map[string]string{
+				"Access-Control-Allow-Origin":  spaOrigin,
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Allow-Headers": "authorization, content-type",
+				"Access-Control-Max-Age":       "600",
+			} {
+				if got := rec.Header().Get(header); got != want {
+					t.Errorf("wrong %s. Expected %q but got %q", header, want, got)
+				}
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+				t.Errorf("credentials are allowed: %s", got)
+			}
+		})
+	}
+}
+
+func TestCORSRequest(t *testing.T) {
+	get := func(resolver *Resolver, token, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/filmoteka/movies", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		resolver.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	resolver, tokens := newCORSResolver(t, CORS{
+		AllowedOrigins:   []string{spaOrigin},
+		AllowedMethods:   []string{http.MethodGet},
+		AllowCredentials: true,
+	})
+
+	rec := get(resolver, tokens[core.UserRole], spaOrigin)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("wrong status. Expected %d but got %d", http.StatusOK, rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != spaOrigin {
+		t.Errorf("origin is not echoed for credentials. Expected %s but got %s", spaOrigin, got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("credentials are not allowed: %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); !strings.Contains(got, requestIDHeader) {
+		t.Errorf("%s is not exposed: %q", requestIDHeader, got)
+	}
+	if got := rec.Header().Values("Vary"); len(got) == 0 || got[0] != "Origin" {
+		t.Errorf("response does not vary by origin: %v", got)
+	}
+
+	for _, test := range []struct {
+		Name    string
+		Origins []string
+	}{
+		{Name: "unlisted origin", Origins: []string{spaOrigin}},
+		{Name: "wildcard", Origins: []string{"*"}},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			resolver, tokens := newCORSResolver(t, CORS{
+				AllowedOrigins:   test.Origins,
+				AllowedMethods:   []string{http.MethodGet},
+				AllowCredentials: true,
+			})
+
+			rec := get(resolver, tokens[core.UserRole], "https://evil.example.com")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("wrong status. Expected %d but got %d", http.StatusOK, rec.Code)
+			}
+			for _, header := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Credentials"} {
+				if got := rec.Header().Get(header); got != "" {
+					t.Errorf("%s is sent to an unlisted origin: %s", header, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSDisabled(t *testing.T) {
+	resolver, _ := newCORSResolver(t, CORS{AllowedOrigins: []string{""}})
+
+	rec := preflight(resolver, spaOrigin, http.MethodGet, "")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("CORS is not disabled: %s", got)
+	}
+	if rec.Code == http.StatusNoContent {
+		t.Errorf("preflight is answered with CORS disabled")
+	}
+}
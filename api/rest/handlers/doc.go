@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"vk-test-task/api/doc"
+	"vk-test-task/internal/core"
+	"vk-test-task/pkg/web"
+	"vk-test-task/pkg/webutil"
+)
+
+// @Title OpenAPI Document
+// @Resource Docs
+// @Description OpenAPI document of the REST API
+// @Success 200 object object "This document"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Route /api/v1/openapi.json [get]
+func (r *Resolver) getOpenAPI(w http.ResponseWriter, req *http.Request) {
+	sendDoc(w, req, "application/json", doc.OpenAPI)
+}
+
+// @Title Swagger UI
+// @Resource Docs
+// @Description Swagger UI for the OpenAPI document
+// @Success 200 string string "Swagger UI page"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Route /api/v1/docs [get]
+func (r *Resolver) getDocs(w http.ResponseWriter, req *http.Request) {
+	sendDoc(w, req, "text/html; charset=utf-8", doc.SwaggerUI)
+}
+
+func sendDoc(w http.ResponseWriter, req *http.Request, contentType string, body []byte) {
+	if req.Method != http.MethodGet {
+		webutil.SendJSONResponse(w, http.StatusMethodNotAllowed, web.ErrorResponse(core.UnsupportedMethodCode, nil, nil))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body) //nolint
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"vk-test-task/api/rest/presenters/export"
+	"vk-test-task/api/rest/presenters/movie"
+	"vk-test-task/api/rest/presenters/star"
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+	moviestore "vk-test-task/internal/store/movie"
+	starstore "vk-test-task/internal/store/star"
+	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/web"
+	"vk-test-task/pkg/webutil"
+)
+
+// exportFlushRows is the number of rows sent to the client at once.
+const exportFlushRows = 100
+
+// exportStream writes rows to the response as they come. Headers are sent with the
+// first row, so an error before it can still be answered with a JSON error.
+type exportStream struct {
+	req    *http.Request
+	w      http.ResponseWriter
+	format string
+	name   string
+	header []string
+	writer export.Writer
+	rows   int
+}
+
+func (r *Resolver) handleMoviesExport(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole, core.UserRole) {
+			r.exportMovies(w, req)
+		}
+	default:
+		webutil.SendJSONResponse(w, http.StatusMethodNotAllowed, web.ErrorResponse(core.UnsupportedMethodCode, nil, nil))
+	}
+}
+
+func (r *Resolver) handleStarsExport(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole, core.UserRole) {
+			r.exportStars(w, req)
+		}
+	default:
+		webutil.SendJSONResponse(w, http.StatusMethodNotAllowed, web.ErrorResponse(core.UnsupportedMethodCode, nil, nil))
+	}
+}
+
+// @Title Export Movies
+// @Resource Movies
+// @Description Stream all movies as CSV or JSON Lines (with sorting and search term)
+// @Param format query string false "csv (default) or jsonl"
+// @Param q query string false "Search term"
+// @Param sort query string false "Sort result"
+// @Success 200 array model.ExportMovie "Successful export, one row per movie"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/export/movies [get]
+func (r *Resolver) exportMovies(w http.ResponseWriter, req *http.Request) {
+	model := filmoteka.ExportMoviesModel{Format: export.CSVFormat}
+
+	if !webutil.QueryCheck(w, req, &model) {
+		return
+	}
+
+	stream := &exportStream{req: req, w: w, format: model.Format, name: "movies", header: movie.ExportHeader}
+	err := r.filmotekaService.ExportMovies(req.Context(), model, func(entity moviestore.ExportEntity) error {
+		return stream.write(movie.PresentExport(entity))
+	})
+	stream.finish(err)
+}
+
+// @Title Export Stars
+// @Resource Stars
+// @Description Stream all stars as CSV or JSON Lines
+// @Param format query string false "csv (default) or jsonl"
+// @Success 200 array model.ExportStar "Successful export, one row per star"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/export/stars [get]
+func (r *Resolver) exportStars(w http.ResponseWriter, req *http.Request) {
+	model := filmoteka.ExportStarsModel{Format: export.CSVFormat}
+
+	if !webutil.QueryCheck(w, req, &model) {
+		return
+	}
+
+	stream := &exportStream{req: req, w: w, format: model.Format, name: "stars", header: star.ExportHeader}
+	err := r.filmotekaService.ExportStars(req.Context(), func(entity starstore.ExportEntity) error {
+		return stream.write(star.PresentExport(entity))
+	})
+	stream.finish(err)
+}
+
+func (s *exportStream) start() error {
+	if s.writer != nil {
+		return nil
+	}
+
+	writer, err := export.NewWriter(s.w, s.format, s.header)
+	if err != nil {
+		return err
+	}
+
+	// the stream outlasts the write timeout of the server, it is bounded by the query deadline
+	err = http.NewResponseController(s.w).SetWriteDeadline(time.Time{})
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	s.w.Header().Set("Content-Type", export.ContentType(s.format))
+	s.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", s.name+"."+s.format))
+	s.w.WriteHeader(http.StatusOK)
+	s.writer = writer
+
+	return nil
+}
+
+func (s *exportStream) write(row export.Row) error {
+	if err := s.start(); err != nil {
+		return err
+	}
+
+	if err := s.writer.Write(row); err != nil {
+		return err
+	}
+
+	s.rows++
+	if s.rows%exportFlushRows == 0 {
+		return s.flush()
+	}
+
+	return nil
+}
+
+func (s *exportStream) flush() error {
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+	if flusher, ok := s.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
+
+// finish completes the response. Once rows are sent the status cannot be changed,
+// so a failed export is cut short and the client gets a truncated body.
+func (s *exportStream) finish(err error) {
+	if err == nil {
+		err = s.start()
+	}
+	if err == nil {
+		err = s.flush()
+	}
+	if err == nil {
+		return
+	}
+
+	logger.Log.ErrorContext(s.req.Context(), "export "+s.name, "rows", s.rows, "error", err.Error())
+	if s.writer == nil {
+		webutil.SendServiceError(s.w, s.req, err)
+	}
+}
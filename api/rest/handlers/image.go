@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"vk-test-task/api/rest/presenters/image"
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/storage"
+	"vk-test-task/pkg/web"
+	"vk-test-task/pkg/webutil"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxKindBytes bounds the kind field of the upload form, kinds are short words.
+const maxKindBytes = 64
+
+func (r *Resolver) handleMovieImages(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPost:
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole) {
+			r.uploadMovieImage(w, req)
+		}
+	default:
+		webutil.SendJSONResponse(w, http.StatusMethodNotAllowed, web.ErrorResponse(core.UnsupportedMethodCode, nil, nil))
+	}
+}
+
+func (r *Resolver) handleStarImages(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPost:
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole) {
+			r.uploadStarImage(w, req)
+		}
+	default:
+		webutil.SendJSONResponse(w, http.StatusMethodNotAllowed, web.ErrorResponse(core.UnsupportedMethodCode, nil, nil))
+	}
+}
+
+func (r *Resolver) handleImage(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		r.getImage(w, req)
+	default:
+		webutil.SendJSONResponse(w, http.StatusMethodNotAllowed, web.ErrorResponse(core.UnsupportedMethodCode, nil, nil))
+	}
+}
+
+// isImagesPath reports whether the request is to the images of a movie or a star.
+func isImagesPath(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, "/images")
+}
+
+// @Title Upload Movie Image
+// @Resource Movies
+// @Description Upload a poster or a still of the movie as multipart/form-data. JPEG, PNG and GIF images are accepted, thumbnails are made of them.
+// @Param id path int true "Movie ID"
+// @Param kind formData string true "poster or still"
+// @Param file formData file true "Image"
+// @Success 201 object model.UploadImageResponse "Successful upload image"
+// @Failure 400 object model.BadRequestInvalidFormResponse "Bad request error"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 404 object model.MovieNotFoundResponse "Not found error"
+// @Failure 413 object model.PayloadTooLargeResponse "Request body too large"
+// @Failure 415 object model.UnsupportedImageResponse "Unsupported image type"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/movie/{id}/images [post]
+func (r *Resolver) uploadMovieImage(w http.ResponseWriter, req *http.Request) {
+	id := webutil.ParseParentID(w, req)
+	if id == 0 {
+		return
+	}
+
+	kind, data, ok := r.readImageForm(w, req)
+	if !ok {
+		return
+	}
+
+	model := filmoteka.UploadMovieImageModel{Kind: kind, Data: data}
+	if !webutil.Validate(w, req, &model) {
+		return
+	}
+
+	entity, err := r.filmotekaService.UploadMovieImage(req.Context(), id, model)
+	if err != nil {
+		r.sendUploadError(w, req, err, core.MovieNotFoundCode)
+		return
+	}
+
+	pres := image.PresentImage(entity, r.imagesURL)
+
+	webutil.SendJSONResponse(w, http.StatusCreated, pres.Response(core.ImageUploadedCode))
+}
+
+// @Title Upload Star Image
+// @Resource Stars
+// @Description Upload a headshot or a still of the star as multipart/form-data. JPEG, PNG and GIF images are accepted, thumbnails are made of them.
+// @Param id path int true "Star ID"
+// @Param kind formData string true "headshot or still"
+// @Param file formData file true "Image"
+// @Success 201 object model.UploadImageResponse "Successful upload image"
+// @Failure 400 object model.BadRequestInvalidFormResponse "Bad request error"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 404 object model.StarNotFoundResponse "Not found error"
+// @Failure 413 object model.PayloadTooLargeResponse "Request body too large"
+// @Failure 415 object model.UnsupportedImageResponse "Unsupported image type"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/star/{id}/images [post]
+func (r *Resolver) uploadStarImage(w http.ResponseWriter, req *http.Request) {
+	id := webutil.ParseParentID(w, req)
+	if id == 0 {
+		return
+	}
+
+	kind, data, ok := r.readImageForm(w, req)
+	if !ok {
+		return
+	}
+
+	model := filmoteka.UploadStarImageModel{Kind: kind, Data: data}
+	if !webutil.Validate(w, req, &model) {
+		return
+	}
+
+	entity, err := r.filmotekaService.UploadStarImage(req.Context(), id, model)
+	if err != nil {
+		r.sendUploadError(w, req, err, core.StarNotFoundCode)
+		return
+	}
+
+	pres := image.PresentImage(entity, r.imagesURL)
+
+	webutil.SendJSONResponse(w, http.StatusCreated, pres.Response(core.ImageUploadedCode))
+}
+
+// @Title Get Image
+// @Resource Images
+// @Description Get an uploaded image or its thumbnail by the key in its URL. Images are served without a JWT, so that pages can show them.
+// @Param key path string true "Image key"
+// @Success 200 file file "Image"
+// @Failure 404 object model.ImageNotFoundResponse "Not found error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/images/{key} [get]
+func (r *Resolver) getImage(w http.ResponseWriter, req *http.Request) {
+	key := strings.TrimPrefix(req.URL.Path, imagesPrefix+"/")
+
+	object, err := r.filmotekaService.GetImageFile(req.Context(), key)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNotFound), errors.Is(err, storage.ErrInvalidKey):
+			webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(core.ImageNotFoundCode, nil, nil))
+			return
+		default:
+			webutil.SendServiceError(w, req, err)
+			return
+		}
+	}
+	defer object.Body.Close()
+
+	w.Header().Set("Content-Type", object.ContentType)
+	if object.Size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(object.Size, 10))
+	}
+	// keys are random and never reused, so an image never changes
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, object.Body); err != nil {
+		logger.Log.ErrorContext(req.Context(), "error write image", "key", key, "error", err.Error())
+	}
+}
+
+// readImageForm reads the kind and the file of the multipart upload form, answering
+// the request itself on errors.
+func (r *Resolver) readImageForm(w http.ResponseWriter, req *http.Request) (string, []byte, bool) {
+	reader, err := req.MultipartReader()
+	if err != nil {
+		logger.Log.DebugContext(req.Context(), "error read multipart form", "error", err.Error())
+		webutil.SendJSONResponse(w, http.StatusBadRequest, web.ErrorResponse(core.InvalidBodyCode, nil, nil))
+		return "", nil, false
+	}
+
+	var (
+		kind string
+		data []byte
+	)
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			webutil.SendBodyError(w, err)
+			return "", nil, false
+		}
+
+		switch part.FormName() {
+		case "kind":
+			value, err := io.ReadAll(io.LimitReader(part, maxKindBytes))
+			if err != nil {
+				webutil.SendBodyError(w, err)
+				return "", nil, false
+			}
+			kind = string(value)
+		case "file":
+			var file io.Reader = part
+			if r.maxImageBytes > 0 {
+				// a byte past the limit tells a too large image
+				file = io.LimitReader(part, r.maxImageBytes+1)
+			}
+			data, err = io.ReadAll(file)
+			if err != nil {
+				webutil.SendBodyError(w, err)
+				return "", nil, false
+			}
+			if r.maxImageBytes > 0 && int64(len(data)) > r.maxImageBytes {
+				webutil.SendJSONResponse(w, http.StatusRequestEntityTooLarge, web.ErrorResponse(core.BodyTooLargeCode, nil, nil))
+				return "", nil, false
+			}
+		}
+	}
+
+	if data == nil {
+		webutil.SendJSONResponse(w, http.StatusBadRequest, web.ErrorResponse(core.FileRequiredCode, nil, nil))
+		return "", nil, false
+	}
+
+	return kind, data, true
+}
+
+func (r *Resolver) sendUploadError(w http.ResponseWriter, req *http.Request, err error, notFoundCode string) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(notFoundCode, nil, nil))
+	case errors.Is(err, core.ErrUnsupportedImage):
+		logger.Log.DebugContext(req.Context(), "unsupported image", "error", err.Error())
+		webutil.SendJSONResponse(w, http.StatusUnsupportedMediaType, web.ErrorResponse(core.UnsupportedImageCode, nil, nil))
+	default:
+		webutil.SendServiceError(w, req, err)
+	}
+}
+
+// moviesImages presents the images of the movies.
+func (r *Resolver) moviesImages(req *http.Request, moviesID ...int) (map[int][]image.Presenter, error) {
+	if len(moviesID) == 0 {
+		return nil, nil
+	}
+
+	images, err := r.filmotekaService.GetImagesByMovieIDs(req.Context(), moviesID)
+	if err != nil {
+		return nil, err
+	}
+
+	return image.PresentByOwner(images, r.imagesURL), nil
+}
+
+// starsImages presents the images of the stars.
+func (r *Resolver) starsImages(req *http.Request, starsID ...int) (map[int][]image.Presenter, error) {
+	if len(starsID) == 0 {
+		return nil, nil
+	}
+
+	images, err := r.filmotekaService.GetImagesByStarIDs(req.Context(), starsID)
+	if err != nil {
+		return nil, err
+	}
+
+	return image.PresentByOwner(images, r.imagesURL), nil
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/pkg/ratelimit"
+	"vk-test-task/pkg/web"
+	"vk-test-task/pkg/webutil"
+)
+
+// deadlineMiddleware cancels the context of the request, and so its queries, once timeout
+// has passed. The context is cancelled anyway when the client goes away.
+func deadlineMiddleware(timeout time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	if timeout <= 0 {
+		return next
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+
+		next(w, req.WithContext(ctx))
+	}
+}
+
+// multipartOverhead is the room left in multipart bodies for the headers of the parts
+// and the form fields next to the file.
+const multipartOverhead = 64 << 10
+
+// uploadLimit returns the limit of multipart bodies carrying an image of maxImageBytes.
+func uploadLimit(maxImageBytes int64) int64 {
+	if maxImageBytes <= 0 {
+		return 0
+	}
+
+	return maxImageBytes + multipartOverhead
+}
+
+// bodyLimitMiddleware fails reading a request body past limit bytes, the readers answer
+// it with 413. Multipart bodies posted to the /images paths, the uploads of images, are
+// limited by uploadLimit instead. Routes without uploads pass a zero uploadLimit.
+func bodyLimitMiddleware(limit, uploadLimit int64, next http.HandlerFunc) http.HandlerFunc {
+	if limit <= 0 && uploadLimit <= 0 {
+		return next
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		n := limit
+		if uploadLimit > 0 && req.Method == http.MethodPost && isImagesPath(req) {
+			if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+				n = uploadLimit
+			}
+		}
+		if req.Body != nil && n > 0 {
+			req.Body = http.MaxBytesReader(w, req.Body, n)
+		}
+
+		next(w, req)
+	}
+}
+
+// rateLimitMiddleware takes a token from the bucket of the client: its username behind
+// the JWT, its IP on public routes. The state of the bucket is reported in the
+// RateLimit-* headers, a client out of tokens is answered with 429. The headers are left
+// out while the rate of the limiter limits nothing.
+func rateLimitMiddleware(limiter *ratelimit.Limiter, public bool, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		result := limiter.Allow(rateLimitKey(req, public))
+		if result.Limit == 0 {
+			next(w, req)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", ceilSeconds(result.Reset))
+
+		if !result.Allowed {
+			sendTooManyRequests(w, core.TooManyRequestsCode, result.RetryAfter)
+			return
+		}
+
+		next(w, req)
+	}
+}
+
+func rateLimitKey(req *http.Request, public bool) string {
+	if username, ok := webutil.UsernameFromContext(req.Context()); ok && !public {
+		return "user:" + username
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+func sendTooManyRequests(w http.ResponseWriter, msgCode string, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", ceilSeconds(retryAfter))
+	webutil.SendJSONResponse(w, http.StatusTooManyRequests, web.ErrorResponse(msgCode, nil, nil))
+}
+
+// ceilSeconds formats d as whole seconds for the headers, rounding up.
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/pkg/ratelimit"
+)
+
+// blockingFilmoteka holds GetMovies like a slow query until its context is done.
+type blockingFilmoteka struct {
+	fakeFilmoteka
+	started chan struct{}
+	aborted chan error
+}
+
+func (s blockingFilmoteka) GetMovies(ctx context.Context, _ filmoteka.GetMoviesModel) ([]movie.Entity, int, error) {
+	close(s.started)
+	<-ctx.Done()
+	s.aborted <- ctx.Err()
+	return nil, 0, ctx.Err()
+}
+
+func newBlockingResolver(t *testing.T, timeouts QueryTimeouts) (*Resolver, blockingFilmoteka, string) {
+	t.Helper()
+
+	contractResolver, tokens := newContractResolver(t)
+	service := blockingFilmoteka{started: make(chan struct{}), aborted: make(chan error, 1)}
+	resolver, err := NewResolver("", Options{QueryTimeouts: timeouts}, service, contractResolver.authService)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	return resolver, service, tokens[core.UserRole]
+}
+
+func assertMsgCode(t *testing.T, rec *httptest.ResponseRecorder, status int, msgCode string) {
+	t.Helper()
+
+	if rec.Code != status {
+		t.Fatalf("wrong status. Expected %d but got %d", status, rec.Code)
+	}
+	var body struct {
+		MsgCode string `json:"msg_code"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if body.MsgCode != msgCode {
+		t.Errorf("wrong msg code. Expected %s but got %s", msgCode, body.MsgCode)
+	}
+}
+
+func TestCanceledRequestAbortsQuery(t *testing.T) {
+	resolver, service, token := newBlockingResolver(t, QueryTimeouts{Default: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/filmoteka/movies", nil).WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		resolver.Handler().ServeHTTP(rec, req)
+		close(done)
+	}()
+
+	<-service.started
+	cancel()
+
+	select {
+	case err := <-service.aborted:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("query aborted with %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("query is not aborted")
+	}
+	<-done
+
+	assertMsgCode(t, rec, core.StatusClientClosedRequest, core.RequestCanceledCode)
+}
+
+func TestQueryDeadline(t *testing.T) {
+	resolver, service, token := newBlockingResolver(t, QueryTimeouts{Default: 10 * time.Millisecond})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/filmoteka/movies", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	resolver.Handler().ServeHTTP(rec, req)
+
+	if err := <-service.aborted; !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("query aborted with %v, want %v", err, context.DeadlineExceeded)
+	}
+	assertMsgCode(t, rec, http.StatusServiceUnavailable, core.QueryTimeoutCode)
+}
+
+func TestBodyLimit(t *testing.T) {
+	contractResolver, tokens := newContractResolver(t)
+	resolver, err := NewResolver("", Options{MaxBodyBytes: 64}, fakeFilmoteka{}, contractResolver.authService)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	padding := strings.Repeat("a", 64)
+	for _, test := range []struct {
+		name  string
+		path  string
+		token string
+		body  string
+	}{
+		{name: "public route", path: "/api/v1/auth/login", body: `{"username":"admin","password":"` + padding + `"}`},
+		{name: "validated body", path: "/api/v1/filmoteka/movies", token: tokens[core.AdminRole], body: `{"title":"Drive","description":"` + padding + `","release_date":"2011-11-03T00:00:00Z","rating":8}`},
+		{name: "graphql", path: "/api/v1/graphql", token: tokens[core.AdminRole], body: `{"query":"{ movies { items { id title description } } }` + padding + `"}`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, test.path, strings.NewReader(test.body))
+			req.Header.Set("Content-Type", "application/json")
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			rec := httptest.NewRecorder()
+			resolver.Handler().ServeHTTP(rec, req)
+
+			assertMsgCode(t, rec, http.StatusRequestEntityTooLarge, core.BodyTooLargeCode)
+		})
+	}
+}
+
+func TestImageLimit(t *testing.T) {
+	contractResolver, tokens := newContractResolver(t)
+	resolver, err := NewResolver("", Options{MaxBodyBytes: 16, MaxImageBytes: int64(len(fakePNG))}, fakeFilmoteka{}, contractResolver.authService)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	for _, test := range []struct {
+		name   string
+		method string
+		path   string
+		file   []byte
+		status int
+	}{
+		// uploads are limited by MaxImageBytes rather than MaxBodyBytes
+		{name: "image within the limit", method: http.MethodPost, path: "/api/v1/filmoteka/movie/1/images", file: fakePNG, status: http.StatusCreated},
+		{name: "image too large", method: http.MethodPost, path: "/api/v1/filmoteka/movie/1/images", file: append(append([]byte{}, fakePNG...), 0), status: http.StatusRequestEntityTooLarge},
+		// other routes keep MaxBodyBytes whatever the content type
+		{name: "multipart body of another route", method: http.MethodPost, path: "/api/v1/filmoteka/movies", file: fakePNG, status: http.StatusRequestEntityTooLarge},
+		{name: "multipart body of another path", method: http.MethodPatch, path: "/api/v1/filmoteka/movie/1", file: fakePNG, status: http.StatusRequestEntityTooLarge},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader(imageForm("poster", test.file)))
+			req.Header.Set("Content-Type", multipartForm)
+			req.Header.Set("Authorization", "Bearer "+tokens[core.AdminRole])
+			rec := httptest.NewRecorder()
+			resolver.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != test.status {
+				t.Errorf("wrong status. Expected %d but got %d: %s", test.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestBatchUnknownField(t *testing.T) {
+	resolver, tokens := newContractResolver(t)
+
+	body := `{"operations":[{"op":"update","id":1,"data":{"rating":9,"score":9}}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/filmoteka/movies:batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+tokens[core.AdminRole])
+	rec := httptest.NewRecorder()
+	resolver.Handler().ServeHTTP(rec, req)
+
+	if !strings.Contains(rec.Body.String(), core.InvalidBodyCode) {
+		t.Errorf("unknown field is accepted: %s", rec.Body.String())
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	contractResolver, tokens := newContractResolver(t)
+	resolver, err := NewResolver("", Options{
+		RateLimits: RateLimits{
+			Default: ratelimit.Rate{Requests: 1, Per: time.Minute},
+			Auth:    ratelimit.Rate{Requests: 2, Per: time.Minute},
+		},
+	}, fakeFilmoteka{}, contractResolver.authService)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	serve := func(method, path, token, remoteAddr, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = remoteAddr
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		resolver.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("public route by IP", func(t *testing.T) {
+		login := func(remoteAddr string) *httptest.ResponseRecorder {
+			return serve(http.MethodPost, "/api/v1/auth/login", "", remoteAddr, `{"username":"admin","password":"secret"}`)
+		}
+
+		for remaining := 1; remaining >= 0; remaining-- {
+			rec := login("10.0.0.1:1234")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("wrong status. Expected %d but got %d", http.StatusOK, rec.Code)
+			}
+			if got := rec.Header().Get("RateLimit-Remaining"); got != strconv.Itoa(remaining) {
+				t.Errorf("wrong RateLimit-Remaining. Expected %d but got %s", remaining, got)
+			}
+		}
+
+		rec := login("10.0.0.1:4321")
+		assertMsgCode(t, rec, http.StatusTooManyRequests, core.TooManyRequestsCode)
+		if got := rec.Header().Get("Retry-After"); got != "30" {
+			t.Errorf("wrong Retry-After. Expected 30 but got %s", got)
+		}
+		if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("wrong RateLimit-Limit. Expected 2 but got %s", got)
+		}
+
+		if rec := login("10.0.0.2:1234"); rec.Code != http.StatusOK {
+			t.Errorf("other IP is limited: %d", rec.Code)
+		}
+	})
+
+	t.Run("authenticated route by username", func(t *testing.T) {
+		get := func(role string) *httptest.ResponseRecorder {
+			return serve(http.MethodGet, "/api/v1/filmoteka/movies", tokens[role], "10.0.0.3:1234", "")
+		}
+
+		if rec := get(core.UserRole); rec.Code != http.StatusOK {
+			t.Fatalf("wrong status. Expected %d but got %d", http.StatusOK, rec.Code)
+		}
+		assertMsgCode(t, get(core.UserRole), http.StatusTooManyRequests, core.TooManyRequestsCode)
+
+		if rec := get(core.AdminRole); rec.Code != http.StatusOK {
+			t.Errorf("other user from the same IP is limited: %d", rec.Code)
+		}
+	})
+
+	t.Run("reloaded rates", func(t *testing.T) {
+		get := func() *httptest.ResponseRecorder {
+			return serve(http.MethodGet, "/api/v1/filmoteka/movies", tokens[core.UserRole], "10.0.0.3:1234", "")
+		}
+
+		resolver.SetRateLimits(RateLimits{Default: ratelimit.Rate{Requests: 3, Per: time.Minute}})
+		rec := get()
+		if rec.Code != http.StatusOK {
+			t.Fatalf("wrong status. Expected %d but got %d", http.StatusOK, rec.Code)
+		}
+		if got := rec.Header().Get("RateLimit-Limit"); got != "3" {
+			t.Errorf("wrong RateLimit-Limit. Expected 3 but got %s", got)
+		}
+
+		resolver.SetRateLimits(RateLimits{})
+		for i := 0; i < 5; i++ {
+			rec := get()
+			if rec.Code != http.StatusOK {
+				t.Fatalf("wrong status. Expected %d but got %d", http.StatusOK, rec.Code)
+			}
+			if got := rec.Header().Get("RateLimit-Limit"); got != "" {
+				t.Fatalf("RateLimit-Limit is sent without a limit: %s", got)
+			}
+		}
+	})
+}
+
+func TestLoginLockout(t *testing.T) {
+	contractResolver, _ := newContractResolver(t)
+	resolver, err := NewResolver("", Options{
+		Lockout: ratelimit.NewLockout(2, time.Minute, time.Hour),
+	}, fakeFilmoteka{}, contractResolver.authService)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	login := func(username, password string) *httptest.ResponseRecorder {
+		body := `{"username":"` + username + `","password":"` + password + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		resolver.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		assertMsgCode(t, login(core.AdminRole, "wrong"), http.StatusUnauthorized, core.WrongCredentialsCode)
+	}
+
+	rec := login(core.AdminRole, "secret")
+	assertMsgCode(t, rec, http.StatusTooManyRequests, core.AccountLockedCode)
+	if got := rec.Header().Get("Retry-After"); got != "60" {
+		t.Errorf("wrong Retry-After. Expected 60 but got %s", got)
+	}
+
+	if rec := login(core.UserRole, "secret"); rec.Code != http.StatusOK {
+		t.Errorf("other account is locked: %d", rec.Code)
+	}
+
+	for i := 0; i < 3; i++ {
+		assertMsgCode(t, login(timeoutUsername, "secret"), http.StatusServiceUnavailable, core.QueryTimeoutCode)
+	}
+}
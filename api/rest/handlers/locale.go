@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+
+	"vk-test-task/pkg/locale"
+)
+
+// localeMiddleware negotiates the locales of Accept-Language and puts them into the
+// request context, the service localises the movies it reads to them. Nil locales
+// leave movies as they are.
+func localeMiddleware(locales *locale.Negotiator, next http.HandlerFunc) http.HandlerFunc {
+	if locales == nil {
+		return next
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Add("Vary", "Accept-Language")
+
+		ctx := locale.ContextWith(req.Context(), locales.Negotiate(req.Header.Get("Accept-Language")))
+		next(w, req.WithContext(ctx))
+	}
+}
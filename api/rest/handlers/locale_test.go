@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"vk-test-task/internal/core"
+)
+
+func TestLocaleNegotiation(t *testing.T) {
+	resolver, tokens := newContractResolver(t)
+
+	for _, test := range []struct {
+		In   string
+		Want string
+	}{
+		{In: "", Want: "ru"},
+		{In: "en-US,en;q=0.9", Want: "en"},
+		{In: "fr,ru;q=0.8,en;q=0.5", Want: "ru"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/filmoteka/movies", nil)
+		req.Header.Set("Authorization", "Bearer "+tokens[core.UserRole])
+		if test.In != "" {
+			req.Header.Set("Accept-Language", test.In)
+		}
+		rec := httptest.NewRecorder()
+		resolver.Handler().ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%q: wrong status. Expected %d but got %d", test.In, http.StatusOK, rec.Code)
+		}
+		if vary := rec.Header().Values("Vary"); !slices.Contains(vary, "Accept-Language") {
+			t.Errorf("%q: wrong Vary header. Expected Accept-Language but got %v", test.In, vary)
+		}
+
+		var resp struct {
+			Data []struct {
+				Locale string `json:"locale"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if len(resp.Data) == 0 || resp.Data[0].Locale != test.Want {
+			t.Errorf("%q: wrong locale. Expected %s but got %+v", test.In, test.Want, resp.Data)
+		}
+	}
+}
+
+func TestLocaleVaryOnRejected(t *testing.T) {
+	resolver, tokens := newContractResolver(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/filmoteka/movies?limit=1000", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens[core.UserRole])
+	req.Header.Set("Accept-Language", "en")
+	rec := httptest.NewRecorder()
+	resolver.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("wrong status. Expected %d but got %d", http.StatusUnprocessableEntity, rec.Code)
+	}
+	if vary := rec.Header().Values("Vary"); !slices.Contains(vary, "Accept-Language") {
+		t.Errorf("wrong Vary header. Expected Accept-Language but got %v", vary)
+	}
+}
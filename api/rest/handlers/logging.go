@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"time"
+
+	"vk-test-task/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// requestIDPattern limits the ids taken from clients, others are replaced to keep the logs clean.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// responseRecorder remembers the status code and the size of the response written by the handler.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// code returns the written status, handlers that write nothing respond with 200.
+func (w *responseRecorder) code() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Flush keeps streamed exports working through the recorder.
+func (w *responseRecorder) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to change its deadlines.
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// requestIDMiddleware takes the request id from the X-Request-ID header or generates one,
+// returns it in the same header and scopes it to the request context, so every log of
+// the request down to the stores carries request_id.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		ctx := logger.ContextWith(r.Context(), "request_id", id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+type accessLogKey struct{}
+
+// accessLogEntry collects the fields of the access log that are known deeper in the chain.
+type accessLogEntry struct {
+	username string
+}
+
+// setAccessLogUsername records the authenticated user for the access log of the request.
+func setAccessLogUsername(ctx context.Context, username string) {
+	if entry, ok := ctx.Value(accessLogKey{}).(*accessLogEntry); ok {
+		entry.username = username
+	}
+}
+
+// accessLogMiddleware logs every request once it is served, with its status, response
+// size, duration and the user of the token.
+func accessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		entry := &accessLogEntry{}
+		recorder := &responseRecorder{ResponseWriter: w}
+
+		ctx := context.WithValue(r.Context(), accessLogKey{}, entry)
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		logger.Log.InfoContext(ctx, "request",
+			"method", r.Method,
+			"url", r.URL.String(),
+			"status", recorder.code(),
+			"bytes", recorder.bytes,
+			"duration", time.Since(start),
+			"username", entry.username,
+			"remote_addr", r.RemoteAddr,
+			"user-agent", r.UserAgent(),
+		)
+	})
+}
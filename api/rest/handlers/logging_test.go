@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/pkg/logger"
+
+	"golang.org/x/exp/slog"
+)
+
+// loggingFilmoteka logs in GetMovieByID with the context it is given, as the service
+// and the stores do.
+type loggingFilmoteka struct {
+	fakeFilmoteka
+}
+
+func (s loggingFilmoteka) GetMovieByID(ctx context.Context, id int) (movie.Entity, error) {
+	logger.Log.InfoContext(ctx, "get movie")
+	return s.fakeFilmoteka.GetMovieByID(ctx, id)
+}
+
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	handler := requestIDMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	for _, test := range []struct {
+		Name   string
+		Header string
+		Keep   bool
+	}{
+		{Name: "Client id", Header: "b3e1c6d2-8f4a-4c1e-9d7a-2f5b6c8e9a01", Keep: true},
+		{Name: "No id"},
+		{Name: "Invalid id", Header: "id with spaces\n"},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.Header != "" {
+				req.Header.Set(requestIDHeader, test.Header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(requestIDHeader)
+			switch {
+			case test.Keep && id != test.Header:
+				t.Errorf("wrong request id. Expected %q but got %q", test.Header, id)
+			case !test.Keep && (id == "" || id == test.Header):
+				t.Errorf("request id is not generated, got %q", id)
+			}
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	resolver, tokens := newContractResolver(t)
+
+	var buf bytes.Buffer
+	prev := logger.Log
+	logger.Log = slog.New(logger.ContextHandler(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { logger.Log = prev })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/filmoteka/movie/1", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens[core.AdminRole])
+	req.Header.Set(requestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	resolver.Handler().ServeHTTP(rec, req)
+
+	var entry map[string]any
+	for _, record := range logRecords(t, &buf) {
+		if record["msg"] == "request" {
+			entry = record
+		}
+	}
+	if entry == nil {
+		t.Fatalf("no access log in %s", buf.String())
+	}
+
+	for key, want := range map[string]any{
+		"request_id": "req-1",
+		"method":     http.MethodGet,
+		"url":        "/api/v1/filmoteka/movie/1",
+		"status":     float64(http.StatusOK),
+		"bytes":      float64(rec.Body.Len()),
+		"username":   core.AdminRole,
+	} {
+		if entry[key] != want {
+			t.Errorf("wrong %s. Expected %v but got %v", key, want, entry[key])
+		}
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Error("access log has no duration")
+	}
+}
+
+func TestServiceLogRequestID(t *testing.T) {
+	contractResolver, tokens := newContractResolver(t)
+	resolver, err := NewResolver("", Options{}, loggingFilmoteka{}, contractResolver.authService)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	var buf bytes.Buffer
+	prev := logger.Log
+	logger.Log = slog.New(logger.ContextHandler(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { logger.Log = prev })
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/filmoteka/movie/1", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens[core.AdminRole])
+	req.Header.Set(requestIDHeader, "req-1")
+	resolver.Handler().ServeHTTP(httptest.NewRecorder(), req)
+
+	for _, record := range logRecords(t, &buf) {
+		if record["msg"] != "get movie" {
+			continue
+		}
+		if record["request_id"] != "req-1" {
+			t.Errorf("wrong request_id. Expected req-1 but got %v", record["request_id"])
+		}
+		return
+	}
+	t.Fatalf("no service log in %s", buf.String())
+}
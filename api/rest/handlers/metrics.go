@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"vk-test-task/pkg/metrics"
+)
+
+// metricsMiddleware counts requests and their durations by method, status and route
+// template. The template is the path of the OpenAPI operation, e.g.
+// /api/v1/filmoteka/movie/{id}, or the pattern of the route when it is not documented,
+// so the labels don't grow with ids in the path.
+func (r *Resolver) metricsMiddleware(pattern string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		recorder := &responseRecorder{ResponseWriter: w}
+
+		next(recorder, req)
+
+		metrics.ObserveRequest(req.Method, r.routeTemplate(req, pattern), recorder.code(), start)
+	}
+}
+
+// routeTemplate returns the path of the OpenAPI operation of the request, or the
+// pattern of the route when it is not documented.
+func (r *Resolver) routeTemplate(req *http.Request, pattern string) string {
+	if route, _, err := r.openAPI.FindRoute(req); err == nil {
+		return route.Path
+	}
+
+	return pattern
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/pkg/metrics"
+)
+
+// TestMetricsMiddleware checks the labels only, the counters are shared with the other tests.
+func TestMetricsMiddleware(t *testing.T) {
+	resolver, tokens := newContractResolver(t)
+
+	for _, path := range []string{"/api/v1/filmoteka/movie/1", "/api/v1/filmoteka/movie/404"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+tokens[core.AdminRole])
+		resolver.Handler().ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	handler, err := metrics.Handler()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	for _, want := range []string{
+		`filmoteka_http_requests_total{method="GET",route="/api/v1/filmoteka/movie/{id}",status="200"}`,
+		`filmoteka_http_requests_total{method="GET",route="/api/v1/filmoteka/movie/{id}",status="404"}`,
+		`filmoteka_http_request_duration_seconds_count{method="GET",route="/api/v1/filmoteka/movie/{id}",status="200"}`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics don't contain %s", want)
+		}
+	}
+}
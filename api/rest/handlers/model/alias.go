@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+type Alias struct {
+	ID        int       `json:"id"`
+	Alias     string    `json:"alias"`
+	Kind      string    `json:"kind" enum:"original,working,regional,stage,transliteration"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateMovieAliasRequest struct {
+	Alias string `json:"alias" example:"The Godfather"`
+	Kind  string `json:"kind" example:"original"`
+}
+
+type CreateStarAliasRequest struct {
+	Alias string `json:"alias" example:"Ryan Gosling"`
+	Kind  string `json:"kind" example:"transliteration"`
+}
+
+type GetMovieAliasesResponse struct {
+	Status  string  `json:"status" example:"OK"`
+	MsgCode string  `json:"msg_code" example:"aliases_received"`
+	Data    []Alias `json:"data" example:"[{\"id\":1,\"alias\":\"The Godfather\",\"kind\":\"original\",\"created_at\":\"2024-03-16T10:41:18Z\"},{\"id\":2,\"alias\":\"Mario Puzo's The Godfather\",\"kind\":\"working\",\"created_at\":\"2024-03-16T10:42:20Z\"}]"`
+}
+
+type GetStarAliasesResponse struct {
+	Status  string  `json:"status" example:"OK"`
+	MsgCode string  `json:"msg_code" example:"aliases_received"`
+	Data    []Alias `json:"data" example:"[{\"id\":1,\"alias\":\"Ryan Gosling\",\"kind\":\"transliteration\",\"created_at\":\"2024-03-16T10:41:18Z\"}]"`
+}
+
+type CreateAliasResponse struct {
+	Status  string `json:"status" example:"OK"`
+	MsgCode string `json:"msg_code" example:"alias_created"`
+	Data    Alias  `json:"data" example:"{\"id\":1,\"alias\":\"The Godfather\",\"kind\":\"original\",\"created_at\":\"2024-03-16T10:41:18Z\"}"`
+}
+
+type DeleteAliasResponse struct {
+	Status  string `json:"status" example:"OK"`
+	MsgCode string `json:"msg_code" example:"alias_deleted"`
+}
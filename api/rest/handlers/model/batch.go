@@ -0,0 +1,53 @@
+package model
+
+import "vk-test-task/pkg/web"
+
+type BatchOperationRequest struct {
+	Op   string `json:"op" example:"create"`
+	ID   int    `json:"id" example:"0"`
+	Data any    `json:"data"`
+}
+
+type BatchMoviesRequest struct {
+	Operations []BatchOperationRequest `json:"operations" example:"[{\"op\":\"create\",\"data\":{\"title\":\"Drive\",\"description\":\"Night Call\",\"release_date\":\"2011-11-03T00:00:00Z\",\"rating\":8,\"stars_id\":[1]}},{\"op\":\"update\",\"id\":2,\"data\":{\"rating\":9}},{\"op\":\"delete\",\"id\":3}]"`
+}
+
+type BatchStarsRequest struct {
+	Operations []BatchOperationRequest `json:"operations" example:"[{\"op\":\"create\",\"data\":{\"name\":\"Ryan Gosling\",\"sex\":\"male\",\"birth_date\":\"1980-11-12T00:00:00Z\"}},{\"op\":\"update\",\"id\":2,\"data\":{\"name\":\"Zendaya\"}},{\"op\":\"delete\",\"id\":3}]"`
+}
+
+type BatchItem struct {
+	Index   int                   `json:"index"`
+	Op      string                `json:"op"`
+	Status  string                `json:"status"`
+	MsgCode string                `json:"msg_code"`
+	Data    any                   `json:"data"`
+	Errors  []web.ValidationError `json:"errors"`
+}
+
+type BatchMeta struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+type BatchMoviesResponse struct {
+	Status  string      `json:"status" example:"OK"`
+	MsgCode string      `json:"msg_code" example:"movies_batch_processed"`
+	Data    []BatchItem `json:"data" example:"[{\"index\":0,\"op\":\"create\",\"status\":\"OK\",\"msg_code\":\"movie_created\",\"data\":{\"id\":1,\"title\":\"Drive\",\"description\":\"Night Call\",\"release_date\":\"2011-11-03T00:00:00Z\",\"rating\":8,\"created_at\":\"2024-03-15T21:16:36Z\",\"updated_at\":\"2024-03-15T21:16:36Z\",\"deleted_at\":null}},{\"index\":1,\"op\":\"update\",\"status\":\"ERROR\",\"msg_code\":\"movie_not_found\"},{\"index\":2,\"op\":\"delete\",\"status\":\"ERROR\",\"msg_code\":\"validation\",\"errors\":[{\"tag\":\"required_unless\",\"field\":\"ID\",\"param\":\"Op create\"}]}]"`
+	Meta    BatchMeta   `json:"_meta" example:"{\"total\": 3, \"succeeded\": 1, \"failed\": 2}"`
+}
+
+type BatchStarsResponse struct {
+	Status  string      `json:"status" example:"OK"`
+	MsgCode string      `json:"msg_code" example:"stars_batch_processed"`
+	Data    []BatchItem `json:"data" example:"[{\"index\":0,\"op\":\"create\",\"status\":\"OK\",\"msg_code\":\"star_created\",\"data\":{\"id\":1,\"name\":\"Ryan Gosling\",\"sex\":\"male\",\"birth_date\":\"1980-11-12T00:00:00Z\",\"created_at\":\"2024-03-15T21:16:36Z\",\"updated_at\":\"2024-03-15T21:16:36Z\",\"deleted_at\":null}},{\"index\":1,\"op\":\"delete\",\"status\":\"ERROR\",\"msg_code\":\"star_not_found\"}]"`
+	Meta    BatchMeta   `json:"_meta" example:"{\"total\": 2, \"succeeded\": 1, \"failed\": 1}"`
+}
+
+type BatchAbortedResponse struct {
+	Status  string      `json:"status" example:"ERROR"`
+	MsgCode string      `json:"msg_code" example:"batch_aborted"`
+	Data    []BatchItem `json:"data" example:"[{\"index\":0,\"op\":\"create\",\"status\":\"ERROR\",\"msg_code\":\"batch_aborted\"},{\"index\":1,\"op\":\"delete\",\"status\":\"ERROR\",\"msg_code\":\"movie_not_found\"}]"`
+	Meta    BatchMeta   `json:"_meta" example:"{\"total\": 2, \"succeeded\": 0, \"failed\": 2}"`
+}
@@ -6,63 +6,128 @@ type ValidationErrors struct {
 	Errors []web.ValidationError `json:"errors"`
 }
 
+type BadRequestResponse struct {
+	Status  string `json:"status" example:"ERROR"`
+	MsgCode string `json:"msg_code" example:"invalid_request_body" enum:"invalid_id,invalid_request_body,request_body_is_required,invalid_query_params"`
+}
+
 type BadRequestInvalidBodyResponse struct {
 	Status  string `json:"status" example:"ERROR"`
-	MsgCode string `json:"msg_code" example:"invalid_body"`
+	MsgCode string `json:"msg_code" example:"invalid_request_body" enum:"invalid_request_body,request_body_is_required"`
+}
+
+type BadRequestInvalidFormResponse struct {
+	Status  string `json:"status" example:"ERROR"`
+	MsgCode string `json:"msg_code" example:"invalid_request_body" enum:"invalid_request_body,file_is_required"`
+}
+
+type PayloadTooLargeResponse struct {
+	Status  string `json:"status" example:"ERROR"`
+	MsgCode string `json:"msg_code" example:"request_body_too_large" enum:"request_body_too_large"`
+}
+
+type TooManyRequestsResponse struct {
+	Status  string `json:"status" example:"ERROR"`
+	MsgCode string `json:"msg_code" example:"general_too_many_requests" enum:"general_too_many_requests,account_locked"`
 }
 
 type BadRequestInvalidIDResponse struct {
 	Status  string `json:"status" example:"ERROR"`
-	MsgCode string `json:"msg_code" example:"invalid_id"`
+	MsgCode string `json:"msg_code" example:"invalid_id" enum:"invalid_id"`
 }
 
 type BadRequestInvalidQueryResponse struct {
 	Status  string `json:"status" example:"ERROR"`
-	MsgCode string `json:"msg_code" example:"invalid_request_query"`
+	MsgCode string `json:"msg_code" example:"invalid_query_params" enum:"invalid_query_params"`
 }
 
 type BadRequestInvalidHeaderResponse struct {
 	Status  string `json:"status" example:"ERROR"`
-	MsgCode string `json:"msg_code" example:"invalid_header"`
+	MsgCode string `json:"msg_code" example:"invalid_header" enum:"invalid_header"`
 }
 
 type MovieNotFoundResponse struct {
 	Status  string `json:"status" example:"ERROR"`
-	MsgCode string `json:"msg_code" example:"movie_not_found"`
+	MsgCode string `json:"msg_code" example:"movie_not_found" enum:"movie_not_found"`
 }
 
 type StarNotFoundResponse struct {
 	Status  string `json:"status" example:"ERROR"`
-	MsgCode string `json:"msg_code" example:"star_not_found"`
+	MsgCode string `json:"msg_code" example:"star_not_found" enum:"star_not_found"`
+}
+
+type ImageNotFoundResponse struct {
+	Status  string `json:"status" example:"ERROR"`
+	MsgCode string `json:"msg_code" example:"image_not_found" enum:"image_not_found"`
+}
+
+type UnsupportedImageResponse struct {
+	Status  string `json:"status" example:"ERROR"`
+	MsgCode string `json:"msg_code" example:"unsupported_image_type" enum:"unsupported_image_type"`
+}
+
+type MovieOrLocaleNotFoundResponse struct {
+	Status  string `json:"status" example:"ERROR"`
+	MsgCode string `json:"msg_code" example:"movie_not_found" enum:"movie_not_found,unsupported_locale"`
+}
+
+type TranslationNotFoundResponse struct {
+	Status  string `json:"status" example:"ERROR"`
+	MsgCode string `json:"msg_code" example:"translation_not_found" enum:"translation_not_found,unsupported_locale"`
+}
+
+type AliasNotFoundResponse struct {
+	Status  string `json:"status" example:"ERROR"`
+	MsgCode string `json:"msg_code" example:"alias_not_found" enum:"alias_not_found"`
+}
+
+type MovieOrStarNotFoundResponse struct {
+	Status  string `json:"status" example:"ERROR"`
+	MsgCode string `json:"msg_code" example:"movie_not_found" enum:"movie_not_found,star_not_found"`
 }
 
 type ValidationResponse struct {
 	Status  string           `json:"status" example:"ERROR"`
-	MsgCode string           `json:"msg_code" example:"go_validation"`
+	MsgCode string           `json:"msg_code" example:"validation" enum:"validation"`
 	Data    ValidationErrors `json:"data" example:"{\"errors\": [{\"tag\": \"<tag>\", \"field\": \"<field>\", \"param\": \"<param>\"}]}"`
 }
 
 type UnauthorizedResponse struct {
 	Status  string `json:"status" example:"ERROR"`
-	MsgCode string `json:"msg_code" example:"general_unauthorized"`
+	MsgCode string `json:"msg_code" example:"general_unauthorized" enum:"general_unauthorized,auth_header_is_required"`
 }
 
 type InternalResponse struct {
 	Status  string `json:"status" example:"ERROR"`
-	MsgCode string `json:"msg_code" example:"general_internal"`
+	MsgCode string `json:"msg_code" example:"general_internal" enum:"general_internal"`
+}
+
+type QueryTimeoutResponse struct {
+	Status  string `json:"status" example:"ERROR"`
+	MsgCode string `json:"msg_code" example:"general_query_timeout" enum:"general_query_timeout"`
 }
 
 type ForbiddenResponse struct {
 	Status  string `json:"status" example:"ERROR"`
-	MsgCode string `json:"msg_code" example:"general_forbidden"`
+	MsgCode string `json:"msg_code" example:"general_forbidden" enum:"general_forbidden"`
+}
+
+type NotAcceptableResponse struct {
+	Status  string `json:"status" example:"ERROR"`
+	MsgCode string `json:"msg_code" example:"general_not_acceptable" enum:"general_not_acceptable"`
 }
 
 type ConflictUsernameResponse struct {
 	Status  string `json:"status" example:"ERROR"`
-	MsgCode string `json:"msg_code" example:"username_is_taken"`
+	MsgCode string `json:"msg_code" example:"username_is_taken" enum:"username_is_taken"`
+}
+
+type ConflictAliasResponse struct {
+	Status  string `json:"status" example:"ERROR"`
+	MsgCode string `json:"msg_code" example:"alias_exists" enum:"alias_exists"`
 }
 
 type WrongCredentialsResponse struct {
 	Status  string `json:"status" example:"ERROR"`
-	MsgCode string `json:"msg_code" example:"wrong_credentials"`
+	MsgCode string `json:"msg_code" example:"wrong_credentials" enum:"wrong_credentials"`
 }
@@ -0,0 +1,12 @@
+package model
+
+type GraphQLRequest struct {
+	Query         string         `json:"query" example:"{ movies(limit: 2) { items { id title stars { name } } } }"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type GraphQLResponse struct {
+	Data   map[string]any   `json:"data" nullable:"true"`
+	Errors []map[string]any `json:"errors"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type Image struct {
+	ID          int               `json:"id"`
+	Kind        string            `json:"kind" enum:"poster,still,headshot"`
+	URL         string            `json:"url"`
+	ContentType string            `json:"content_type" enum:"image/jpeg,image/png,image/gif"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+	Thumbnails  map[string]string `json:"thumbnails"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+type UploadImageResponse struct {
+	Status  string `json:"status" example:"OK"`
+	MsgCode string `json:"msg_code" example:"image_uploaded"`
+	Data    Image  `json:"data" example:"{\"id\":1,\"kind\":\"poster\",\"url\":\"/api/v1/images/3f2a9c0e1b7d4a5f8c6e2d1b0a9f8e7d.jpg\",\"content_type\":\"image/jpeg\",\"width\":2000,\"height\":3000,\"thumbnails\":{\"large\":\"/api/v1/images/3f2a9c0e1b7d4a5f8c6e2d1b0a9f8e7d_large.jpg\",\"medium\":\"/api/v1/images/3f2a9c0e1b7d4a5f8c6e2d1b0a9f8e7d_medium.jpg\",\"small\":\"/api/v1/images/3f2a9c0e1b7d4a5f8c6e2d1b0a9f8e7d_small.jpg\"},\"created_at\":\"2024-03-16T10:41:18Z\"}"`
+}
@@ -7,14 +7,17 @@ import (
 )
 
 type Movie struct {
-	ID          int        `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	ReleaseDate time.Time  `json:"release_date"`
-	Rating      int        `json:"rating"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DeletedAt   *time.Time `json:"deleted_at"`
+	ID           int        `json:"id"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	ReleaseDate  time.Time  `json:"release_date"`
+	Rating       int        `json:"rating"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at" nullable:"true"`
+	Locale       string     `json:"locale,omitempty"`
+	MatchedAlias string     `json:"matched_alias,omitempty"`
+	Images       []Image    `json:"images,omitempty"`
 }
 
 type CreateMovieRequest struct {
@@ -22,6 +25,7 @@ type CreateMovieRequest struct {
 	Description string    `json:"description" example:"Description"`
 	ReleaseDate time.Time `json:"release_date" example:"2024-06-06T00:00:00Z"`
 	Rating      int       `json:"rating" example:"9"`
+	StarsID     []int     `json:"stars_id" example:"1,2"`
 }
 
 type UpdateMovieRequest struct {
@@ -29,6 +33,7 @@ type UpdateMovieRequest struct {
 	Description string    `json:"description" example:"New Description"`
 	ReleaseDate time.Time `json:"release_date" example:"2024-10-10T00:00:00Z"`
 	Rating      int       `json:"rating" example:"8"`
+	StarsID     []int     `json:"stars_id" example:"1"`
 }
 
 type GetMoviesResponse struct {
@@ -41,13 +46,13 @@ type GetMoviesResponse struct {
 type GetMovieByIDResponse struct {
 	Status  string `json:"status" example:"OK"`
 	MsgCode string `json:"msg_code" example:"movie_received"`
-	Data    Movie  `json:"data" example:"{\"id\":1,\"title\":\"Drive\",\"description\":\"Night Call\",\"release_date\":\"2012-26-01T00:00:00Z\",\"rating\":8,\"created_at\":\"2024-03-15T21:16:36Z\",\"updated_at\":\"2024-03-15T22:16:03Z\",\"deleted_at\":null}"`
+	Data    Movie  `json:"data" example:"{\"id\":1,\"title\":\"Drive\",\"description\":\"Night Call\",\"release_date\":\"2012-01-26T00:00:00Z\",\"rating\":8,\"created_at\":\"2024-03-15T21:16:36Z\",\"updated_at\":\"2024-03-15T22:16:03Z\",\"deleted_at\":null}"`
 }
 
 type CreateMovieResponse struct {
 	Status  string `json:"status" example:"OK"`
 	MsgCode string `json:"msg_code" example:"movie_created"`
-	Data    Movie  `json:"data" example:"{\"id\":1,\"title\":\"Drive\",\"description\":\"Night Call\",\"release_date\":\"2012-26-01T00:00:00Z\",\"rating\":8,\"created_at\":\"2024-03-15T21:16:36Z\",\"updated_at\":\"2024-03-15T22:16:03Z\",\"deleted_at\":null}"`
+	Data    Movie  `json:"data" example:"{\"id\":1,\"title\":\"Drive\",\"description\":\"Night Call\",\"release_date\":\"2012-01-26T00:00:00Z\",\"rating\":8,\"created_at\":\"2024-03-15T21:16:36Z\",\"updated_at\":\"2024-03-15T22:16:03Z\",\"deleted_at\":null}"`
 }
 
 type UpdateMovieResponse struct {
@@ -60,3 +65,15 @@ type DeleteMovieResponse struct {
 	Status  string `json:"status" example:"OK"`
 	MsgCode string `json:"msg_code" example:"movie_deleted"`
 }
+
+type ExportMovie struct {
+	ID          int       `json:"id" example:"1"`
+	ExternalID  *string   `json:"external_id" example:"tt0780504" nullable:"true"`
+	Title       string    `json:"title" example:"Drive"`
+	Description string    `json:"description" example:"Night Call"`
+	ReleaseDate time.Time `json:"release_date" example:"2011-11-03T00:00:00Z"`
+	Rating      int       `json:"rating" example:"8"`
+	StarsID     []int     `json:"stars_id" example:"1,2"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-03-15T21:16:36Z"`
+	UpdatedAt   time.Time `json:"updated_at" example:"2024-03-15T22:16:03Z"`
+}
@@ -7,13 +7,15 @@ import (
 )
 
 type Star struct {
-	ID        int        `json:"id"`
-	Name      string     `json:"name"`
-	Sex       string     `json:"sex"`
-	BirthDate time.Time  `json:"birth_date"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at"`
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`
+	Sex          string     `json:"sex"`
+	BirthDate    time.Time  `json:"birth_date"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at" nullable:"true"`
+	MatchedAlias string     `json:"matched_alias,omitempty"`
+	Images       []Image    `json:"images,omitempty"`
 }
 
 type StarWithMovies struct {
@@ -62,3 +64,13 @@ type DeleteStarResponse struct {
 	Status  string `json:"status" example:"OK"`
 	MsgCode string `json:"msg_code" example:"star_deleted"`
 }
+
+type ExportStar struct {
+	ID         int       `json:"id" example:"1"`
+	ExternalID *string   `json:"external_id" example:"nm0331516" nullable:"true"`
+	Name       string    `json:"name" example:"Ryan Gosling"`
+	Sex        string    `json:"sex" example:"male"`
+	BirthDate  time.Time `json:"birth_date" example:"1980-11-12T00:00:00Z"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-03-15T21:16:36Z"`
+	UpdatedAt  time.Time `json:"updated_at" example:"2024-03-15T22:16:03Z"`
+}
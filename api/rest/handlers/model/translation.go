@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+type Translation struct {
+	MovieID     int       `json:"movie_id"`
+	Locale      string    `json:"locale"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type UpsertMovieTranslationRequest struct {
+	Title       string `json:"title" example:"Drive"`
+	Description string `json:"description" example:"A stuntman moonlights as a getaway driver"`
+}
+
+type UpsertMovieTranslationResponse struct {
+	Status  string      `json:"status" example:"OK"`
+	MsgCode string      `json:"msg_code" example:"translation_upserted"`
+	Data    Translation `json:"data" example:"{\"movie_id\":1,\"locale\":\"en\",\"title\":\"Drive\",\"description\":\"A stuntman moonlights as a getaway driver\",\"created_at\":\"2024-03-16T10:41:18Z\",\"updated_at\":\"2024-03-16T10:41:18Z\"}"`
+}
+
+type DeleteMovieTranslationResponse struct {
+	Status  string `json:"status" example:"OK"`
+	MsgCode string `json:"msg_code" example:"translation_deleted"`
+}
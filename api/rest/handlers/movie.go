@@ -1,15 +1,12 @@
 package handlers
 
 import (
-	"context"
 	"errors"
 	"net/http"
-	"strconv"
 
 	"vk-test-task/api/rest/presenters/movie"
 	"vk-test-task/internal/core"
 	"vk-test-task/internal/service/filmoteka"
-	"vk-test-task/pkg/logger"
 	"vk-test-task/pkg/web"
 	"vk-test-task/pkg/webutil"
 
@@ -32,6 +29,21 @@ func (r *Resolver) handleMovies(w http.ResponseWriter, req *http.Request) {
 }
 
 func (r *Resolver) handleMovie(w http.ResponseWriter, req *http.Request) {
+	if isTranslationPath(req) {
+		r.handleMovieTranslation(w, req)
+		return
+	}
+
+	if isAliasPath(req) {
+		r.handleMovieAliases(w, req)
+		return
+	}
+
+	if isImagesPath(req) {
+		r.handleMovieImages(w, req)
+		return
+	}
+
 	switch req.Method {
 	case http.MethodGet:
 		if webutil.AllowedRoleChecker(w, req, core.AdminRole, core.UserRole) {
@@ -52,67 +64,65 @@ func (r *Resolver) handleMovie(w http.ResponseWriter, req *http.Request) {
 
 // @Title Get Movies Paginated
 // @Resource Movies
-// @Description Get movies list paginated (with sorting and search term)
+// @Description Get movies list paginated (with sorting and search term, titles are searched in every locale and among aliases) as JSON, XML (Accept: application/xml) or CSV (Accept: text/csv)
 // @Param q query string false "Search term"
 // @Param sort query string false "Sort result"
 // @Param page query int false "Page number"
 // @Param limit query int false "Items per page"
+// @Param Accept-Language header string false "Preferred locales"
 // @Success 200 array model.GetMoviesResponse "Successful get movies"
+// @Failure 400 object model.BadRequestInvalidQueryResponse "Bad request error"
 // @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
 // @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 406 object model.NotAcceptableResponse "Not acceptable error"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
 // @Failure 500 object model.InternalResponse "Internal server error"
 // @Route /api/v1/filmoteka/movies [get]
 func (r *Resolver) getMovies(w http.ResponseWriter, req *http.Request) {
+	encoder, ok := webutil.AcceptChecker(w, req)
+	if !ok {
+		return
+	}
+
 	var model filmoteka.GetMoviesModel
 
-	queries := webutil.QueryParser(req)
-	if _, ok := queries["page"]; ok {
-		num, err := strconv.Atoi(queries["page"])
-		if err != nil {
-			logger.Log.Error("error parse page", "error", err.Error())
-			webutil.SendJSONResponse(w, http.StatusBadRequest, web.ErrorResponse(core.BadRequestErrorCode, nil, nil))
-			return
-		}
-		model.Page = num
-	}
-	if _, ok := queries["limit"]; ok {
-		num, err := strconv.Atoi(queries["limit"])
-		if err != nil {
-			logger.Log.Error("error parse limit", "error", err.Error())
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
-			return
-		}
-		model.Limit = num
-	}
-	if val, ok := queries["q"]; ok {
-		model.SearchTerm = val
-	}
-	if val, ok := queries["sort"]; ok {
-		model.Sort = val
+	if !webutil.QueryCheck(w, req, &model) {
+		return
 	}
-	if !webutil.QueryValidator(w, req, &model) {
+
+	data, total, err := r.filmotekaService.GetMovies(req.Context(), model)
+	if err != nil {
+		webutil.SendServiceError(w, req, err)
 		return
 	}
 
-	data, total, err := r.filmotekaService.GetMovies(context.Background(), model)
+	moviesID := make([]int, len(data))
+	for i, m := range data {
+		moviesID[i] = m.ID
+	}
+	images, err := r.moviesImages(req, moviesID...)
 	if err != nil {
-		webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+		webutil.SendServiceError(w, req, err)
 		return
 	}
 
-	pres := movie.PresentList(data, model.PaginationQuery, total)
+	pres := movie.PresentList(data, images, model.PaginationQuery, total)
 
-	webutil.SendJSONResponse(w, http.StatusOK, pres.Response())
+	webutil.SendResponse(w, encoder, http.StatusOK, pres.Response())
 }
 
 // @Title Get Movie By ID
 // @Resource Movies
 // @Param id path int true "Movie ID"
+// @Param Accept-Language header string false "Preferred locales"
 // @Success 200 object model.GetMovieByIDResponse "Successful get movie"
 // @Failure 400 object model.BadRequestInvalidIDResponse "Bad request error"
 // @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
 // @Failure 403 object model.ForbiddenResponse "Forbidden error"
 // @Failure 404 object model.MovieNotFoundResponse "Not found error"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
 // @Failure 500 object model.InternalResponse "Internal server error"
 // @Route /api/v1/filmoteka/movie/{id} [get]
 func (r *Resolver) getMovieByID(w http.ResponseWriter, req *http.Request) {
@@ -121,19 +131,25 @@ func (r *Resolver) getMovieByID(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	data, err := r.filmotekaService.GetMovieByID(context.Background(), id)
+	data, err := r.filmotekaService.GetMovieByID(req.Context(), id)
 	if err != nil {
 		switch err {
 		case pgx.ErrNoRows:
 			webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(core.MovieNotFoundCode, nil, nil))
 			return
 		default:
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+			webutil.SendServiceError(w, req, err)
 			return
 		}
 	}
 
-	pres := movie.PresentMovie(data)
+	images, err := r.moviesImages(req, id)
+	if err != nil {
+		webutil.SendServiceError(w, req, err)
+		return
+	}
+
+	pres := movie.PresentMovie(data, images[id])
 
 	webutil.SendJSONResponse(w, http.StatusOK, pres.Response(core.MovieReceivedCode))
 }
@@ -145,7 +161,10 @@ func (r *Resolver) getMovieByID(w http.ResponseWriter, req *http.Request) {
 // @Failure 400 object model.BadRequestInvalidBodyResponse "Bad request error"
 // @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
 // @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 404 object model.MovieOrStarNotFoundResponse "Not found error"
+// @Failure 413 object model.PayloadTooLargeResponse "Request body too large"
 // @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
 // @Failure 500 object model.InternalResponse "Internal server error"
 // @Route /api/v1/filmoteka/movies [post]
 func (r *Resolver) createMovie(w http.ResponseWriter, req *http.Request) {
@@ -155,7 +174,7 @@ func (r *Resolver) createMovie(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	data, err := r.filmotekaService.CreateMovie(context.Background(), model)
+	data, err := r.filmotekaService.CreateMovie(req.Context(), model)
 	if err != nil {
 		switch {
 		case errors.Is(err, core.ErrStarIDNotExists):
@@ -165,12 +184,12 @@ func (r *Resolver) createMovie(w http.ResponseWriter, req *http.Request) {
 			webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(core.MovieNotFoundCode, nil, nil))
 			return
 		default:
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+			webutil.SendServiceError(w, req, err)
 			return
 		}
 	}
 
-	pres := movie.PresentMovie(data)
+	pres := movie.PresentMovie(data, nil)
 
 	webutil.SendJSONResponse(w, http.StatusCreated, pres.Response(core.MovieCreatedCode))
 }
@@ -180,11 +199,13 @@ func (r *Resolver) createMovie(w http.ResponseWriter, req *http.Request) {
 // @Param id path int true "Movie ID"
 // @Param movie body model.UpdateMovieRequest true "Movie to update"
 // @Success 200 object model.UpdateMovieResponse "Successful update movie"
-// @Failure 400 object model.BadRequestInvalidBodyResponse "Bad request error"
+// @Failure 400 object model.BadRequestResponse "Bad request error"
 // @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
 // @Failure 403 object model.ForbiddenResponse "Forbidden error"
-// @Failure 404 object model.MovieNotFoundResponse "Not found error"
+// @Failure 404 object model.MovieOrStarNotFoundResponse "Not found error"
+// @Failure 413 object model.PayloadTooLargeResponse "Request body too large"
 // @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
 // @Failure 500 object model.InternalResponse "Internal server error"
 // @Route /api/v1/filmoteka/movie/{id} [patch]
 func (r *Resolver) updateMovie(w http.ResponseWriter, req *http.Request) {
@@ -199,7 +220,7 @@ func (r *Resolver) updateMovie(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	data, err := r.filmotekaService.UpdateMovie(context.Background(), id, model)
+	data, err := r.filmotekaService.UpdateMovie(req.Context(), id, model)
 	if err != nil {
 		switch {
 		case errors.Is(err, core.ErrStarIDNotExists):
@@ -209,12 +230,18 @@ func (r *Resolver) updateMovie(w http.ResponseWriter, req *http.Request) {
 			webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(core.MovieNotFoundCode, nil, nil))
 			return
 		default:
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+			webutil.SendServiceError(w, req, err)
 			return
 		}
 	}
 
-	pres := movie.PresentMovie(data)
+	images, err := r.moviesImages(req, id)
+	if err != nil {
+		webutil.SendServiceError(w, req, err)
+		return
+	}
+
+	pres := movie.PresentMovie(data, images[id])
 
 	webutil.SendJSONResponse(w, http.StatusOK, pres.Response(core.MovieUpdatedCode))
 }
@@ -227,6 +254,8 @@ func (r *Resolver) updateMovie(w http.ResponseWriter, req *http.Request) {
 // @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
 // @Failure 403 object model.ForbiddenResponse "Forbidden error"
 // @Failure 404 object model.MovieNotFoundResponse "Not found error"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
 // @Failure 500 object model.InternalResponse "Internal server error"
 // @Route /api/v1/filmoteka/movie/{id} [delete]
 func (r *Resolver) deleteMovie(w http.ResponseWriter, req *http.Request) {
@@ -235,14 +264,14 @@ func (r *Resolver) deleteMovie(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	err := r.filmotekaService.DeleteMovie(context.Background(), id)
+	err := r.filmotekaService.DeleteMovie(req.Context(), id)
 	if err != nil {
 		switch err {
 		case pgx.ErrNoRows:
 			webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(core.MovieNotFoundCode, nil, nil))
 			return
 		default:
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+			webutil.SendServiceError(w, req, err)
 			return
 		}
 	}
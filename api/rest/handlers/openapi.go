@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"vk-test-task/api/doc"
+	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/web"
+	"vk-test-task/pkg/webutil"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+// newOpenAPIRouter finds operations of the OpenAPI document by the request path,
+// whatever host the server is reached by.
+func newOpenAPIRouter() (routers.Router, error) {
+	spec, err := doc.Load()
+	if err != nil {
+		return nil, err
+	}
+	spec.Servers = nil
+
+	return legacy.NewRouter(spec)
+}
+
+// openAPIMiddleware validates path and query parameters and the JSON body against the
+// operation of the OpenAPI document. Requests to undocumented operations, bodies that
+// are not JSON and rules the schema can't express are left to the handlers.
+func (r *Resolver) openAPIMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		route, pathParams, err := r.openAPI.FindRoute(req)
+		if err != nil {
+			next(w, req)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				ExcludeRequestBody: true,
+				MultiError:         true,
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+		verrors := parameterErrors(openapi3filter.ValidateRequest(req.Context(), input))
+
+		if route.Operation.RequestBody != nil {
+			errs, err := bodyErrors(req, route.Operation.RequestBody.Value)
+			if err != nil {
+				logger.Log.ErrorContext(req.Context(), "error read body", "error", err.Error())
+				webutil.SendBodyError(w, err)
+				return
+			}
+			verrors = append(verrors, errs...)
+		}
+
+		if len(verrors) != 0 {
+			webutil.SendJSONResponse(w, http.StatusUnprocessableEntity, web.ValidationErrorResponse(verrors, nil))
+			return
+		}
+
+		next(w, req)
+	}
+}
+
+func parameterErrors(err error) []web.ValidationError {
+	var verrors []web.ValidationError
+
+	for _, err := range flatten(err) {
+		var rerr *openapi3filter.RequestError
+		if !errors.As(err, &rerr) || rerr.Parameter == nil {
+			logger.Log.Debug("error validate request", "error", err.Error())
+			continue
+		}
+
+		name := rerr.Parameter.Name
+		serrs := schemaErrors(rerr.Err)
+
+		switch {
+		case len(serrs) != 0:
+			for _, serr := range serrs {
+				verrors = append(verrors, web.ValidationError{Tag: serr.SchemaField, Field: name, Param: schemaParam(serr)})
+			}
+		case errors.Is(rerr.Err, openapi3filter.ErrInvalidRequired), errors.Is(rerr.Err, openapi3filter.ErrInvalidEmptyValue):
+			verrors = append(verrors, web.ValidationError{Tag: "required", Field: name})
+		default:
+			// the value could not be parsed into the parameter type
+			var typ string
+			if rerr.Parameter.Schema != nil && rerr.Parameter.Schema.Value != nil {
+				typ = rerr.Parameter.Schema.Value.Type
+			}
+			verrors = append(verrors, web.ValidationError{Tag: "type", Field: name, Param: typ})
+		}
+	}
+
+	return verrors
+}
+
+// bodyErrors validates the JSON body against the schema of requestBody. The body is
+// put back for the handler.
+func bodyErrors(req *http.Request, requestBody *openapi3.RequestBody) ([]web.ValidationError, error) {
+	media := requestBody.Content.Get("application/json")
+	if media == nil || media.Schema == nil || req.Body == nil {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		// an empty or broken body is answered by the handler
+		return nil, nil //nolint:nilerr
+	}
+
+	var verrors []web.ValidationError
+	for _, serr := range schemaErrors(media.Schema.Value.VisitJSON(value, openapi3.MultiErrors())) {
+		verrors = append(verrors, web.ValidationError{
+			Tag:   serr.SchemaField,
+			Field: strings.Join(serr.JSONPointer(), "."),
+			Param: schemaParam(serr),
+		})
+	}
+
+	return verrors, nil
+}
+
+func flatten(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	merr, ok := err.(openapi3.MultiError) //nolint:errorlint // wrapped lists belong to the wrapping error
+	if !ok {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, err := range merr {
+		errs = append(errs, flatten(err)...)
+	}
+
+	return errs
+}
+
+func schemaErrors(err error) []*openapi3.SchemaError {
+	var serrs []*openapi3.SchemaError
+
+	for _, err := range flatten(err) {
+		var serr *openapi3.SchemaError
+		if errors.As(err, &serr) {
+			serrs = append(serrs, serr)
+		}
+	}
+
+	return serrs
+}
+
+// schemaParam returns the value of the schema keyword that failed, like the param of a validator tag.
+func schemaParam(serr *openapi3.SchemaError) string {
+	schema := serr.Schema
+	if schema == nil {
+		return ""
+	}
+
+	switch serr.SchemaField {
+	case "type":
+		return schema.Type
+	case "format":
+		return schema.Format
+	case "pattern":
+		return schema.Pattern
+	case "enum":
+		values := make([]string, len(schema.Enum))
+		for i, value := range schema.Enum {
+			values[i] = fmt.Sprint(value)
+		}
+		return strings.Join(values, " ")
+	case "minimum":
+		return formatFloat(schema.Min)
+	case "maximum":
+		return formatFloat(schema.Max)
+	case "minLength":
+		return fmt.Sprint(schema.MinLength)
+	case "maxLength":
+		return formatUint(schema.MaxLength)
+	case "minItems":
+		return fmt.Sprint(schema.MinItems)
+	case "maxItems":
+		return formatUint(schema.MaxItems)
+	default:
+		return ""
+	}
+}
+
+func formatFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(*value)
+}
+
+func formatUint(value *uint64) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(*value)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/pkg/web"
+)
+
+func TestOpenAPIMiddleware(t *testing.T) {
+	resolver, tokens := newContractResolver(t)
+
+	for _, test := range []struct {
+		Name   string
+		Method string
+		Path   string
+		Body   string
+		Errors []web.ValidationError
+	}{
+		{
+			Name:   "Query types",
+			Method: http.MethodGet,
+			Path:   "/api/v1/filmoteka/movies?page=first&limit=1000",
+			Errors: []web.ValidationError{
+				{Tag: "type", Field: "page", Param: "integer"},
+				{Tag: "maximum", Field: "limit", Param: "500"},
+			},
+		},
+		{
+			Name:   "Path",
+			Method: http.MethodDelete,
+			Path:   "/api/v1/filmoteka/star/0",
+			Errors: []web.ValidationError{{Tag: "minimum", Field: "id", Param: "1"}},
+		},
+		{
+			Name:   "Enum",
+			Method: http.MethodGet,
+			Path:   "/api/v1/filmoteka/export/stars?format=xlsx",
+			Errors: []web.ValidationError{{Tag: "enum", Field: "format", Param: "csv jsonl"}},
+		},
+		{
+			Name:   "Body",
+			Method: http.MethodPatch,
+			Path:   "/api/v1/filmoteka/movie/1",
+			Body:   `{"rating":"nine","stars_id":[1,"two"]}`,
+			Errors: []web.ValidationError{
+				{Tag: "type", Field: "rating", Param: "integer"},
+				{Tag: "type", Field: "stars_id.1", Param: "integer"},
+			},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest(test.Method, test.Path, strings.NewReader(test.Body))
+			req.Header.Set("Authorization", "Bearer "+tokens[core.AdminRole])
+			rec := httptest.NewRecorder()
+
+			resolver.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("expected status 422, got %d: %s", rec.Code, rec.Body.String())
+			}
+
+			var resp struct {
+				MsgCode string `json:"msg_code"`
+				Data    struct {
+					Errors []web.ValidationError `json:"errors"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if resp.MsgCode != core.ValidationCode {
+				t.Errorf("expected msg_code %s, got %s", core.ValidationCode, resp.MsgCode)
+			}
+			if !reflect.DeepEqual(resp.Data.Errors, test.Errors) {
+				t.Errorf("expected errors %+v, got %+v", test.Errors, resp.Data.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIMiddlewareChecksTokenFirst(t *testing.T) {
+	resolver, _ := newContractResolver(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/filmoteka/movies?page=first", nil)
+	rec := httptest.NewRecorder()
+
+	resolver.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", rec.Code)
+	}
+}
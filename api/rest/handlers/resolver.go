@@ -2,11 +2,19 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
+	gql "vk-test-task/api/graphql"
 	"vk-test-task/internal/service/auth"
 	"vk-test-task/internal/service/filmoteka"
-	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/locale"
+	"vk-test-task/pkg/ratelimit"
+	"vk-test-task/pkg/tlscert"
+
+	"github.com/getkin/kin-openapi/routers"
 )
 
 const (
@@ -14,64 +22,273 @@ const (
 	APIVersion      = "v1"
 	pathPrefix      = APIPrefix + "/" + APIVersion
 	filmotekaPrefix = pathPrefix + "/filmoteka"
+	imagesPrefix    = pathPrefix + "/images"
 )
 
 type Resolver struct {
 	serverHost       string
+	certs            *tlscert.Reloader
+	lockout          *ratelimit.Lockout
+	limiters         map[routeGroup]*ratelimit.Limiter
 	server           *http.Server
+	mux              *http.ServeMux
+	openAPI          routers.Router
+	imagesURL        string
+	maxImageBytes    int64
+	locales          *locale.Negotiator
 	filmotekaService filmoteka.Service
 	authService      auth.Service
 }
 
-func NewResolver(serverHost string, filmotekaService filmoteka.Service, authService auth.Service) *Resolver {
+type route struct {
+	pattern string
+	handler http.HandlerFunc
+	// public routes are served without a JWT
+	public bool
+	group  routeGroup
+	// uploads routes take images on their /images paths, see bodyLimitMiddleware
+	uploads bool
+}
+
+// routeGroup selects the query deadline and the rate limit of a route.
+type routeGroup int
+
+const (
+	defaultGroup routeGroup = iota
+	authGroup
+	batchGroup
+	exportGroup
+)
+
+// QueryTimeouts are the deadlines of the queries made for a request by kind of route.
+// Zero leaves the queries bounded by the client only.
+type QueryTimeouts struct {
+	Default time.Duration
+	Batch   time.Duration
+	Export  time.Duration
+}
+
+func (t QueryTimeouts) of(group routeGroup) time.Duration {
+	switch group {
+	case batchGroup:
+		return t.Batch
+	case exportGroup:
+		return t.Export
+	default:
+		return t.Default
+	}
+}
+
+// RateLimits are the request rates allowed to a client by kind of route. Clients are
+// told apart by username behind the JWT and by IP on public routes.
+type RateLimits struct {
+	Default ratelimit.Rate
+	Auth    ratelimit.Rate
+	Batch   ratelimit.Rate
+	Export  ratelimit.Rate
+}
+
+func (l RateLimits) of(group routeGroup) ratelimit.Rate {
+	switch group {
+	case authGroup:
+		return l.Auth
+	case batchGroup:
+		return l.Batch
+	case exportGroup:
+		return l.Export
+	default:
+		return l.Default
+	}
+}
+
+// Options tune the HTTP server of the API. Zero timeouts and limits are not applied.
+type Options struct {
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	// MaxBodyBytes limits request bodies, larger ones are answered with 413
+	MaxBodyBytes int64
+	// MaxImageBytes limits uploaded images instead, the multipart bodies they come in
+	// are allowed to be a bit larger
+	MaxImageBytes int64
+
+	// ImagesURL is prepended to the keys of images in the answers, images are served by
+	// the API by default
+	ImagesURL string
+
+	// Locales negotiates the locales movies are read in and the ones they are translated
+	// to, nil turns localisation off
+	Locales *locale.Negotiator
+
+	QueryTimeouts QueryTimeouts
+	RateLimits    RateLimits
+	// Lockout locks accounts out after failed logins
+	Lockout *ratelimit.Lockout
+
+	CORS CORS
+
+	// TLSCertFile and TLSKeyFile turn on HTTPS, the pair is reloaded by ReloadCertificate
+	TLSCertFile string
+	TLSKeyFile  string
+}
+
+func NewResolver(
+	serverHost string,
+	opts Options,
+	filmotekaService filmoteka.Service,
+	authService auth.Service,
+) (*Resolver, error) {
+	openAPI, err := newOpenAPIRouter()
+	if err != nil {
+		return nil, err
+	}
+
+	imagesURL := opts.ImagesURL
+	if imagesURL == "" {
+		imagesURL = imagesPrefix
+	}
+
 	resolver := &Resolver{
 		serverHost:       serverHost,
+		lockout:          opts.Lockout,
+		imagesURL:        strings.TrimSuffix(imagesURL, "/"),
+		maxImageBytes:    opts.MaxImageBytes,
+		locales:          opts.Locales,
 		filmotekaService: filmotekaService,
 		authService:      authService,
+		openAPI:          openAPI,
+		// routes of a group share the buckets of the clients
+		limiters: make(map[routeGroup]*ratelimit.Limiter),
 	}
 
 	mux := http.NewServeMux()
-	mux.HandleFunc(pathPrefix+"/auth/login", resolver.login)
-	mux.HandleFunc(pathPrefix+"/auth/signup", resolver.signup)
-
-	mux.HandleFunc(filmotekaPrefix+"/stars", resolver.jwtMiddleware(resolver.handleStars))
-	mux.HandleFunc(filmotekaPrefix+"/star/", resolver.jwtMiddleware(resolver.handleStar))
-	mux.HandleFunc(filmotekaPrefix+"/movies", resolver.jwtMiddleware(resolver.handleMovies))
-	mux.HandleFunc(filmotekaPrefix+"/movie/", resolver.jwtMiddleware(resolver.handleMovie))
+	for _, route := range resolver.routes() {
+		limiter, ok := resolver.limiters[route.group]
+		if !ok {
+			limiter = ratelimit.NewLimiter(opts.RateLimits.of(route.group))
+			resolver.limiters[route.group] = limiter
+		}
 
-	loggedRouter := loggingMiddleware(mux)
+		handler := resolver.openAPIMiddleware(route.handler)
+		// responses rejected by the validation vary by Accept-Language as well
+		handler = localeMiddleware(opts.Locales, handler)
+		handler = rateLimitMiddleware(limiter, route.public, handler)
+		if !route.public {
+			handler = resolver.jwtMiddleware(handler)
+		}
+		handler = deadlineMiddleware(opts.QueryTimeouts.of(route.group), handler)
+		var imageLimit int64
+		if route.uploads {
+			imageLimit = uploadLimit(opts.MaxImageBytes)
+		}
+		handler = bodyLimitMiddleware(opts.MaxBodyBytes, imageLimit, handler)
+		handler = resolver.metricsMiddleware(route.pattern, handler)
+		mux.HandleFunc(route.pattern, resolver.tracingMiddleware(route.pattern, handler))
+	}
+	resolver.mux = mux
 
 	server := &http.Server{
-		Addr:    serverHost,
-		Handler: loggedRouter,
+		Addr:              serverHost,
+		Handler:           requestIDMiddleware(accessLogMiddleware(corsMiddleware(opts.CORS, mux))),
+		ReadHeaderTimeout: opts.ReadHeaderTimeout,
+		ReadTimeout:       opts.ReadTimeout,
+		WriteTimeout:      opts.WriteTimeout,
+		IdleTimeout:       opts.IdleTimeout,
+	}
+
+	if opts.TLSCertFile != "" || opts.TLSKeyFile != "" {
+		resolver.certs, err = tlscert.New(opts.TLSCertFile, opts.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		server.TLSConfig = resolver.certs.Config()
 	}
 
 	resolver.server = server
 
-	return resolver
+	return resolver, nil
 }
 
-func loggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		logger.Log.Info("New request", "method", r.Method, "url", r.URL, "user-agent", r.UserAgent())
-		next.ServeHTTP(w, r)
-	})
+// routes lists every endpoint of the API. Each one must be described in api/doc/swagger.json,
+// requests are validated against it before reaching the handler.
+func (r *Resolver) routes() []route {
+	return []route{
+		{pathPrefix + "/auth/login", r.login, true, authGroup, false},
+		{pathPrefix + "/auth/signup", r.signup, true, authGroup, false},
+		{pathPrefix + "/openapi.json", r.getOpenAPI, true, defaultGroup, false},
+		{pathPrefix + "/docs", r.getDocs, true, defaultGroup, false},
+		{imagesPrefix + "/", r.handleImage, true, defaultGroup, false},
+
+		{filmotekaPrefix + "/stars", r.handleStars, false, defaultGroup, false},
+		{filmotekaPrefix + "/star/", r.handleStar, false, defaultGroup, true},
+		{filmotekaPrefix + "/movies", r.handleMovies, false, defaultGroup, false},
+		{filmotekaPrefix + "/movie/", r.handleMovie, false, defaultGroup, true},
+		{filmotekaPrefix + "/stars:batch", r.handleStarsBatch, false, batchGroup, false},
+		{filmotekaPrefix + "/movies:batch", r.handleMoviesBatch, false, batchGroup, false},
+		{filmotekaPrefix + "/export/stars", r.handleStarsExport, false, exportGroup, false},
+		{filmotekaPrefix + "/export/movies", r.handleMoviesExport, false, exportGroup, false},
+		{pathPrefix + "/graphql", gql.New(r.filmotekaService).ServeHTTP, false, defaultGroup, false},
+	}
 }
 
-func (r *Resolver) jwtMiddleware(nextFunc func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
+func (r *Resolver) jwtMiddleware(nextFunc http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		userData, ok := r.authService.Verify(w, req)
 		if !ok {
 			return
 		}
 
+		setAccessLogUsername(req.Context(), userData.Username)
+
 		ctx := context.WithValue(req.Context(), "user_role", userData.Role) //nolint
+		ctx = context.WithValue(ctx, "username", userData.Username)         //nolint
 		nextFunc(w, req.WithContext(ctx))
 	}
 }
 
+// Run serves the API until Shutdown is called, over HTTPS when a certificate is set.
 func (r *Resolver) Run() error {
-	return r.server.ListenAndServe()
+	var err error
+	if r.certs != nil {
+		// the certificate comes from TLSConfig
+		err = r.server.ListenAndServeTLS("", "")
+	} else {
+		err = r.server.ListenAndServe()
+	}
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// ReloadCertificate reads the TLS key pair again, new connections get the new certificate.
+func (r *Resolver) ReloadCertificate() error {
+	if r.certs == nil {
+		return nil
+	}
+
+	return r.certs.Reload()
+}
+
+// SetRateLimits changes the rates of the route groups, e.g. on a config reload. Clients
+// start over with full buckets.
+func (r *Resolver) SetRateLimits(limits RateLimits) {
+	for group, limiter := range r.limiters {
+		limiter.SetRate(limits.of(group))
+	}
+}
+
+// Shutdown stops accepting connections and waits for the requests in flight until ctx is done.
+func (r *Resolver) Shutdown(ctx context.Context) error {
+	return r.server.Shutdown(ctx)
+}
+
+// Handler returns the router of the API, e.g. to serve it from httptest.
+func (r *Resolver) Handler() http.Handler {
+	return r.server.Handler
 }
 
 func (r *Resolver) GetAddr() string {
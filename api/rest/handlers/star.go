@@ -1,14 +1,11 @@
 package handlers
 
 import (
-	"context"
 	"net/http"
-	"strconv"
 
 	"vk-test-task/api/rest/presenters/star"
 	"vk-test-task/internal/core"
 	"vk-test-task/internal/service/filmoteka"
-	"vk-test-task/pkg/logger"
 	"vk-test-task/pkg/web"
 	"vk-test-task/pkg/webutil"
 
@@ -31,6 +28,16 @@ func (r *Resolver) handleStars(w http.ResponseWriter, req *http.Request) {
 }
 
 func (r *Resolver) handleStar(w http.ResponseWriter, req *http.Request) {
+	if isAliasPath(req) {
+		r.handleStarAliases(w, req)
+		return
+	}
+
+	if isImagesPath(req) {
+		r.handleStarImages(w, req)
+		return
+	}
+
 	switch req.Method {
 	case http.MethodGet:
 		if webutil.AllowedRoleChecker(w, req, core.AdminRole, core.UserRole) {
@@ -51,58 +58,62 @@ func (r *Resolver) handleStar(w http.ResponseWriter, req *http.Request) {
 
 // @Title Get Stars Paginated
 // @Resource Stars
-// @Description Get stars list paginated
+// @Description Get stars list paginated (with search term matching names and aliases) as JSON, XML (Accept: application/xml) or CSV (Accept: text/csv)
+// @Param q query string false "Search term"
 // @Param page query int false "Page number"
 // @Param limit query int false "Items per page"
 // @Success 200 array model.GetStarsResponse "Successful get stars"
 // @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
 // @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 406 object model.NotAcceptableResponse "Not acceptable error"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
 // @Failure 500 object model.InternalResponse "Internal server error"
 // @Route /api/v1/filmoteka/stars [get]
 func (r *Resolver) getStars(w http.ResponseWriter, req *http.Request) {
+	encoder, ok := webutil.AcceptChecker(w, req)
+	if !ok {
+		return
+	}
+
 	var model filmoteka.GetStarsModel
 
-	queries := webutil.QueryParser(req)
-	if _, ok := queries["page"]; ok {
-		num, err := strconv.Atoi(queries["page"])
-		if err != nil {
-			logger.Log.Error("error parse page", "error", err.Error())
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
-			return
-		}
-		model.Page = num
+	if !webutil.QueryCheck(w, req, &model) {
+		return
 	}
 
-	if _, ok := queries["limit"]; ok {
-		num, err := strconv.Atoi(queries["limit"])
-		if err != nil {
-			logger.Log.Error("error parse limit", "error", err.Error())
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
-			return
-		}
-		model.Limit = num
+	data, total, err := r.filmotekaService.GetStars(req.Context(), model)
+	if err != nil {
+		webutil.SendServiceError(w, req, err)
+		return
 	}
-	webutil.QueryValidator(w, req, &model)
 
-	data, total, err := r.filmotekaService.GetStars(context.Background(), model)
+	starsID := make([]int, len(data))
+	for i, s := range data {
+		starsID[i] = s.ID
+	}
+	images, err := r.starsImages(req, starsID...)
 	if err != nil {
-		webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+		webutil.SendServiceError(w, req, err)
 		return
 	}
 
-	pres := star.PresentList(data, model.PaginationQuery, total)
+	pres := star.PresentList(data, images, model.PaginationQuery, total)
 
-	webutil.SendJSONResponse(w, http.StatusOK, pres.Response())
+	webutil.SendResponse(w, encoder, http.StatusOK, pres.Response())
 }
 
 // @Title Get Star By ID
 // @Resource Stars
 // @Param id path int true "Star ID"
+// @Param Accept-Language header string false "Preferred locales"
 // @Success 200 object model.GetStarByIDResponse "Successful get star"
 // @Failure 400 object model.BadRequestInvalidIDResponse "Bad request error"
 // @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
 // @Failure 403 object model.ForbiddenResponse "Forbidden error"
 // @Failure 404 object model.StarNotFoundResponse "Not found error"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
 // @Failure 500 object model.InternalResponse "Internal server error"
 // @Route /api/v1/filmoteka/star/{id} [get]
 func (r *Resolver) getStarByID(w http.ResponseWriter, req *http.Request) {
@@ -111,19 +122,25 @@ func (r *Resolver) getStarByID(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	starData, moviesData, err := r.filmotekaService.GetStarByID(context.Background(), id)
+	starData, moviesData, err := r.filmotekaService.GetStarByID(req.Context(), id)
 	if err != nil {
 		switch err {
 		case pgx.ErrNoRows:
 			webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(core.StarNotFoundCode, nil, nil))
 			return
 		default:
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+			webutil.SendServiceError(w, req, err)
 			return
 		}
 	}
 
-	pres := star.PresentStar(starData, moviesData)
+	images, err := r.starsImages(req, id)
+	if err != nil {
+		webutil.SendServiceError(w, req, err)
+		return
+	}
+
+	pres := star.PresentStar(starData, moviesData, images[id])
 
 	webutil.SendJSONResponse(w, http.StatusOK, pres.Response(core.StarReceivedCode))
 }
@@ -135,7 +152,9 @@ func (r *Resolver) getStarByID(w http.ResponseWriter, req *http.Request) {
 // @Failure 400 object model.BadRequestInvalidBodyResponse "Bad request error"
 // @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
 // @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 413 object model.PayloadTooLargeResponse "Request body too large"
 // @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
 // @Failure 500 object model.InternalResponse "Internal server error"
 // @Route /api/v1/filmoteka/stars [post]
 func (r *Resolver) createStar(w http.ResponseWriter, req *http.Request) {
@@ -145,19 +164,19 @@ func (r *Resolver) createStar(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	data, err := r.filmotekaService.CreateStar(context.Background(), model)
+	data, err := r.filmotekaService.CreateStar(req.Context(), model)
 	if err != nil {
 		switch err {
 		case pgx.ErrNoRows:
 			webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(core.StarNotFoundCode, nil, nil))
 			return
 		default:
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+			webutil.SendServiceError(w, req, err)
 			return
 		}
 	}
 
-	pres := star.PresentStar(data, nil)
+	pres := star.PresentStar(data, nil, nil)
 
 	webutil.SendJSONResponse(w, http.StatusCreated, pres.Response(core.StarCreatedCode))
 }
@@ -167,11 +186,13 @@ func (r *Resolver) createStar(w http.ResponseWriter, req *http.Request) {
 // @Param id path int true "Star ID"
 // @Param star body model.UpdateStarRequest true "Star to update"
 // @Success 200 object model.UpdateStarResponse "Successful update star"
-// @Failure 400 object model.BadRequestInvalidBodyResponse "Bad request error"
+// @Failure 400 object model.BadRequestResponse "Bad request error"
 // @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
 // @Failure 403 object model.ForbiddenResponse "Forbidden error"
 // @Failure 404 object model.StarNotFoundResponse "Not found error"
+// @Failure 413 object model.PayloadTooLargeResponse "Request body too large"
 // @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
 // @Failure 500 object model.InternalResponse "Internal server error"
 // @Route /api/v1/filmoteka/star/{id} [patch]
 func (r *Resolver) updateStar(w http.ResponseWriter, req *http.Request) {
@@ -186,19 +207,25 @@ func (r *Resolver) updateStar(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	starData, moviesData, err := r.filmotekaService.UpdateStar(context.Background(), id, model)
+	starData, moviesData, err := r.filmotekaService.UpdateStar(req.Context(), id, model)
 	if err != nil {
 		switch err {
 		case pgx.ErrNoRows:
 			webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(core.StarNotFoundCode, nil, nil))
 			return
 		default:
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+			webutil.SendServiceError(w, req, err)
 			return
 		}
 	}
 
-	pres := star.PresentStar(starData, moviesData)
+	images, err := r.starsImages(req, id)
+	if err != nil {
+		webutil.SendServiceError(w, req, err)
+		return
+	}
+
+	pres := star.PresentStar(starData, moviesData, images[id])
 
 	webutil.SendJSONResponse(w, http.StatusOK, pres.Response(core.StarUpdatedCode))
 }
@@ -211,6 +238,8 @@ func (r *Resolver) updateStar(w http.ResponseWriter, req *http.Request) {
 // @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
 // @Failure 403 object model.ForbiddenResponse "Forbidden error"
 // @Failure 404 object model.StarNotFoundResponse "Not found error"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
 // @Failure 500 object model.InternalResponse "Internal server error"
 // @Route /api/v1/filmoteka/star/{id} [delete]
 func (r *Resolver) deleteStar(w http.ResponseWriter, req *http.Request) {
@@ -219,14 +248,14 @@ func (r *Resolver) deleteStar(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	err := r.filmotekaService.DeleteStar(context.Background(), id)
+	err := r.filmotekaService.DeleteStar(req.Context(), id)
 	if err != nil {
 		switch err {
 		case pgx.ErrNoRows:
 			webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(core.StarNotFoundCode, nil, nil))
 			return
 		default:
-			webutil.SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+			webutil.SendServiceError(w, req, err)
 			return
 		}
 	}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"vk-test-task/pkg/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingMiddleware starts the server span of the request, continuing the trace of the
+// W3C traceparent header when the client sends one. The span is named after the
+// route template like the metrics.
+func (r *Resolver) tracingMiddleware(pattern string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+		template := r.routeTemplate(req, pattern)
+		ctx, span := tracing.Start(ctx, req.Method+" "+template,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(req.Method),
+				semconv.HTTPRoute(template),
+				semconv.URLPath(req.URL.Path),
+			),
+		)
+		defer span.End()
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		next(recorder, req.WithContext(ctx))
+
+		status := recorder.code()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("status %d", status))
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	contractResolver, tokens := newContractResolver(t)
+	resolver, err := NewResolver("", Options{}, filmoteka.WithTracing(fakeFilmoteka{}), contractResolver.authService)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/filmoteka/movie/404", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens[core.AdminRole])
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentSpanID+"-01")
+	resolver.Handler().ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	server, ok := spans["GET /api/v1/filmoteka/movie/{id}"]
+	if !ok {
+		t.Fatalf("no server span in %v", spans)
+	}
+	if server.SpanKind() != trace.SpanKindServer {
+		t.Errorf("wrong span kind. Expected %s but got %s", trace.SpanKindServer, server.SpanKind())
+	}
+	if got := server.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("trace is not continued. Expected %s but got %s", traceID, got)
+	}
+	if got := server.Parent().SpanID().String(); got != parentSpanID {
+		t.Errorf("wrong parent of the server span. Expected %s but got %s", parentSpanID, got)
+	}
+
+	service, ok := spans["filmoteka.GetMovieByID"]
+	if !ok {
+		t.Fatalf("no service span in %v", spans)
+	}
+	if service.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Errorf("service span is not a child of the server span")
+	}
+	if len(service.Events()) == 0 || service.Events()[0].Name != "exception" {
+		t.Errorf("error of the service is not recorded")
+	}
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"vk-test-task/api/rest/presenters/translation"
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/pkg/web"
+	"vk-test-task/pkg/webutil"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *Resolver) handleMovieTranslation(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPut:
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole) {
+			r.upsertMovieTranslation(w, req)
+		}
+	case http.MethodDelete:
+		if webutil.AllowedRoleChecker(w, req, core.AdminRole) {
+			r.deleteMovieTranslation(w, req)
+		}
+	default:
+		webutil.SendJSONResponse(w, http.StatusMethodNotAllowed, web.ErrorResponse(core.UnsupportedMethodCode, nil, nil))
+	}
+}
+
+// isTranslationPath reports whether the request is to a translation of a movie.
+func isTranslationPath(req *http.Request) bool {
+	return strings.Contains(req.URL.Path, "/translations/")
+}
+
+// @Title Upsert Movie Translation
+// @Resource Movies
+// @Description Create or replace the title and the description of the movie in a locale. The locale must be one of the supported ones other than the default, the movie itself is written in the default locale.
+// @Param id path int true "Movie ID"
+// @Param locale path string true "Locale"
+// @Param translation body model.UpsertMovieTranslationRequest true "Translation"
+// @Success 200 object model.UpsertMovieTranslationResponse "Successful upsert translation"
+// @Failure 400 object model.BadRequestResponse "Bad request error"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 404 object model.MovieOrLocaleNotFoundResponse "Not found error"
+// @Failure 413 object model.PayloadTooLargeResponse "Request body too large"
+// @Failure 422 object model.ValidationResponse "Validation error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/movie/{id}/translations/{locale} [put]
+func (r *Resolver) upsertMovieTranslation(w http.ResponseWriter, req *http.Request) {
+	id, locale, ok := r.parseTranslationPath(w, req)
+	if !ok {
+		return
+	}
+
+	var model filmoteka.UpsertMovieTranslationModel
+
+	if !webutil.BodyCheck(w, req, &model) {
+		return
+	}
+
+	data, err := r.filmotekaService.UpsertMovieTranslation(req.Context(), id, locale, model)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(core.MovieNotFoundCode, nil, nil))
+			return
+		default:
+			webutil.SendServiceError(w, req, err)
+			return
+		}
+	}
+
+	pres := translation.PresentTranslation(data)
+
+	webutil.SendJSONResponse(w, http.StatusOK, pres.Response(core.TranslationUpsertedCode))
+}
+
+// @Title Delete Movie Translation
+// @Resource Movies
+// @Param id path int true "Movie ID"
+// @Param locale path string true "Locale"
+// @Success 200 object model.DeleteMovieTranslationResponse "Successful delete translation"
+// @Failure 400 object model.BadRequestInvalidIDResponse "Bad request error"
+// @Failure 401 object model.UnauthorizedResponse "Unauthorized error"
+// @Failure 403 object model.ForbiddenResponse "Forbidden error"
+// @Failure 404 object model.TranslationNotFoundResponse "Not found error"
+// @Failure 429 object model.TooManyRequestsResponse "Too many requests"
+// @Failure 500 object model.InternalResponse "Internal server error"
+// @Route /api/v1/filmoteka/movie/{id}/translations/{locale} [delete]
+func (r *Resolver) deleteMovieTranslation(w http.ResponseWriter, req *http.Request) {
+	id, locale, ok := r.parseTranslationPath(w, req)
+	if !ok {
+		return
+	}
+
+	err := r.filmotekaService.DeleteMovieTranslation(req.Context(), id, locale)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(core.TranslationNotFoundCode, nil, nil))
+			return
+		default:
+			webutil.SendServiceError(w, req, err)
+			return
+		}
+	}
+
+	webutil.SendJSONResponse(w, http.StatusOK, web.OKResponse(core.TranslationDeletedCode, nil, nil))
+}
+
+// parseTranslationPath parses /movie/{id}/translations/{locale}. Movies are translated to
+// the supported locales only, and not to the default one they are written in.
+func (r *Resolver) parseTranslationPath(w http.ResponseWriter, req *http.Request) (int, string, bool) {
+	id := webutil.ParseIDAt(w, req, 2)
+	if id == 0 {
+		return 0, "", false
+	}
+
+	locale := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+	if r.locales == nil || !r.locales.Supports(locale) || locale == r.locales.Default() {
+		webutil.SendJSONResponse(w, http.StatusNotFound, web.ErrorResponse(core.UnsupportedLocaleCode, nil, nil))
+		return 0, "", false
+	}
+
+	return id, locale, true
+}
@@ -0,0 +1,64 @@
+package alias
+
+import (
+	"time"
+
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/internal/store/star"
+	"vk-test-task/pkg/web"
+)
+
+type Presenter struct {
+	ID        int       `json:"id"`
+	Alias     string    `json:"alias"`
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func PresentMovieAlias(entity movie.AliasEntity) Presenter {
+	return Presenter{
+		ID:        entity.ID,
+		Alias:     entity.Alias,
+		Kind:      entity.Kind,
+		CreatedAt: entity.CreatedAt,
+	}
+}
+
+func PresentStarAlias(entity star.AliasEntity) Presenter {
+	return Presenter{
+		ID:        entity.ID,
+		Alias:     entity.Alias,
+		Kind:      entity.Kind,
+		CreatedAt: entity.CreatedAt,
+	}
+}
+
+func (p *Presenter) Response(msg string) web.Response {
+	return web.OKResponse(msg, *p, nil)
+}
+
+type ListPresenter struct {
+	aliases []Presenter
+}
+
+func PresentMovieAliases(entities []movie.AliasEntity) ListPresenter {
+	pres := ListPresenter{aliases: make([]Presenter, len(entities))}
+	for i, entity := range entities {
+		pres.aliases[i] = PresentMovieAlias(entity)
+	}
+
+	return pres
+}
+
+func PresentStarAliases(entities []star.AliasEntity) ListPresenter {
+	pres := ListPresenter{aliases: make([]Presenter, len(entities))}
+	for i, entity := range entities {
+		pres.aliases[i] = PresentStarAlias(entity)
+	}
+
+	return pres
+}
+
+func (p *ListPresenter) Response(msg string) web.Response {
+	return web.OKResponse(msg, p.aliases, nil)
+}
@@ -0,0 +1,73 @@
+package batch
+
+import (
+	"vk-test-task/internal/core"
+	"vk-test-task/pkg/web"
+)
+
+type (
+	ItemPresenter struct {
+		Index   int                   `json:"index"`
+		Op      string                `json:"op"`
+		Status  string                `json:"status"`
+		MsgCode string                `json:"msg_code"`
+		Data    any                   `json:"data,omitempty"`
+		Errors  []web.ValidationError `json:"errors,omitempty"`
+	}
+
+	Meta struct {
+		Total     int `json:"total"`
+		Succeeded int `json:"succeeded"`
+		Failed    int `json:"failed"`
+	}
+
+	ListPresenter struct {
+		items []ItemPresenter
+		meta  Meta
+	}
+)
+
+func Succeeded(index int, op, msgCode string, data any) ItemPresenter {
+	return ItemPresenter{
+		Index:   index,
+		Op:      op,
+		Status:  string(web.OK),
+		MsgCode: msgCode,
+		Data:    data,
+	}
+}
+
+func Failed(index int, op, msgCode string, errors []web.ValidationError) ItemPresenter {
+	return ItemPresenter{
+		Index:   index,
+		Op:      op,
+		Status:  string(web.ERROR),
+		MsgCode: msgCode,
+		Errors:  errors,
+	}
+}
+
+func PresentList(items []ItemPresenter) ListPresenter {
+	pres := ListPresenter{
+		items: items,
+		meta:  Meta{Total: len(items)},
+	}
+
+	for _, item := range items {
+		if item.Status == string(web.OK) {
+			pres.meta.Succeeded++
+		} else {
+			pres.meta.Failed++
+		}
+	}
+
+	return pres
+}
+
+func (p *ListPresenter) Response(msg string) web.Response {
+	return web.OKResponse(msg, p.items, p.meta)
+}
+
+func (p *ListPresenter) AbortedResponse() web.Response {
+	return web.ErrorResponse(core.BatchAbortedCode, p.items, p.meta)
+}
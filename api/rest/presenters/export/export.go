@@ -0,0 +1,76 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	CSVFormat   = "csv"
+	JSONLFormat = "jsonl"
+)
+
+type (
+	// Row is a presenter that can be written as a CSV record. JSON Lines rows
+	// are encoded with the presenter json tags.
+	Row interface {
+		CSVRecord() []string
+	}
+
+	Writer interface {
+		Write(Row) error
+		Flush() error
+	}
+
+	csvWriter struct {
+		writer *csv.Writer
+	}
+
+	jsonlWriter struct {
+		encoder *json.Encoder
+	}
+)
+
+// NewWriter returns a writer of rows in format. CSV output starts with header.
+func NewWriter(w io.Writer, format string, header []string) (Writer, error) {
+	switch format {
+	case CSVFormat:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return nil, err
+		}
+		return &csvWriter{writer: writer}, nil
+	case JSONLFormat:
+		return &jsonlWriter{encoder: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func ContentType(format string) string {
+	switch format {
+	case CSVFormat:
+		return "text/csv; charset=utf-8"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+func (w *csvWriter) Write(row Row) error {
+	return w.writer.Write(row.CSVRecord())
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *jsonlWriter) Write(row Row) error {
+	return w.encoder.Encode(row)
+}
+
+func (w *jsonlWriter) Flush() error {
+	return nil
+}
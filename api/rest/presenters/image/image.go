@@ -0,0 +1,56 @@
+package image
+
+import (
+	"time"
+
+	"vk-test-task/internal/store/image"
+	"vk-test-task/pkg/web"
+)
+
+type Presenter struct {
+	ID          int    `json:"id"`
+	Kind        string `json:"kind"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	// Thumbnails are the URLs of the thumbnails by size: small, medium and large
+	Thumbnails map[string]string `json:"thumbnails"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// PresentImage makes the URLs of the image of its keys, baseURL is the URL images are
+// served from.
+func PresentImage(entity image.Entity, baseURL string) Presenter {
+	thumbnails := make(map[string]string, len(entity.Thumbnails))
+	for size, key := range entity.Thumbnails {
+		thumbnails[size] = baseURL + "/" + key
+	}
+
+	return Presenter{
+		ID:          entity.ID,
+		Kind:        entity.Kind,
+		URL:         baseURL + "/" + entity.Key,
+		ContentType: entity.ContentType,
+		Width:       entity.Width,
+		Height:      entity.Height,
+		Thumbnails:  thumbnails,
+		CreatedAt:   entity.CreatedAt,
+	}
+}
+
+// PresentByOwner presents the images of movies or stars by their id.
+func PresentByOwner(entities map[int][]image.Entity, baseURL string) map[int][]Presenter {
+	images := make(map[int][]Presenter, len(entities))
+	for ownerID, ownerImages := range entities {
+		for _, entity := range ownerImages {
+			images[ownerID] = append(images[ownerID], PresentImage(entity, baseURL))
+		}
+	}
+
+	return images
+}
+
+func (p *Presenter) Response(msg string) web.Response {
+	return web.OKResponse(msg, *p, nil)
+}
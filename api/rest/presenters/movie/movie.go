@@ -1,8 +1,11 @@
 package movie
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
+	"vk-test-task/api/rest/presenters/image"
 	"vk-test-task/internal/core"
 	"vk-test-task/internal/store/movie"
 	"vk-test-task/pkg/web"
@@ -17,9 +20,15 @@ type Presenter struct {
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 	DeletedAt   *time.Time `json:"deleted_at"`
+	// Locale of the title and the description, left out when localisation is off
+	Locale string `json:"locale,omitempty"`
+	// MatchedAlias is the alias the search term matched, only lists searched by q have it
+	MatchedAlias string `json:"matched_alias,omitempty"`
+	// Images are left out of batch results and of the movies of a star
+	Images []image.Presenter `json:"images,omitempty"`
 }
 
-func PresentMovie(entity movie.Entity) Presenter {
+func PresentMovie(entity movie.Entity, images []image.Presenter) Presenter {
 	return Presenter{
 		ID:          entity.ID,
 		Title:       entity.Title,
@@ -29,6 +38,8 @@ func PresentMovie(entity movie.Entity) Presenter {
 		CreatedAt:   entity.CreatedAt,
 		UpdatedAt:   entity.UpdatedAt,
 		DeletedAt:   entity.DeletedAt,
+		Locale:      entity.Locale,
+		Images:      images,
 	}
 }
 
@@ -41,19 +52,22 @@ type ListPresenter struct {
 	pagination web.PaginationBody
 }
 
-func PresentList(entities []movie.Entity, pq web.PaginationQuery, total int) ListPresenter {
+func PresentList(entities []movie.Entity, images map[int][]image.Presenter, pq web.PaginationQuery, total int) ListPresenter {
 	pres := ListPresenter{}
 
 	for _, entity := range entities {
 		moviePresenter := Presenter{
-			ID:          entity.ID,
-			Title:       entity.Title,
-			Description: entity.Description,
-			ReleaseDate: entity.ReleaseDate,
-			Rating:      entity.Rating,
-			CreatedAt:   entity.CreatedAt,
-			UpdatedAt:   entity.UpdatedAt,
-			DeletedAt:   entity.DeletedAt,
+			ID:           entity.ID,
+			Title:        entity.Title,
+			Description:  entity.Description,
+			ReleaseDate:  entity.ReleaseDate,
+			Rating:       entity.Rating,
+			CreatedAt:    entity.CreatedAt,
+			UpdatedAt:    entity.UpdatedAt,
+			DeletedAt:    entity.DeletedAt,
+			Locale:       entity.Locale,
+			MatchedAlias: entity.MatchedAlias,
+			Images:       images[entity.ID],
 		}
 		pres.movies = append(pres.movies, moviePresenter)
 	}
@@ -71,3 +85,57 @@ func PresentList(entities []movie.Entity, pq web.PaginationQuery, total int) Lis
 func (p *ListPresenter) Response() web.Response {
 	return web.OKResponse(core.MoviesReceivedCode, p.movies, p.pagination)
 }
+
+// ExportHeader is the CSV header of exported movies.
+var ExportHeader = []string{"id", "external_id", "title", "description", "release_date", "rating", "stars_id", "created_at", "updated_at"}
+
+type ExportPresenter struct {
+	ID          int       `json:"id"`
+	ExternalID  *string   `json:"external_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	ReleaseDate time.Time `json:"release_date"`
+	Rating      int       `json:"rating"`
+	StarsID     []int     `json:"stars_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func PresentExport(entity movie.ExportEntity) ExportPresenter {
+	return ExportPresenter{
+		ID:          entity.ID,
+		ExternalID:  entity.ExternalID,
+		Title:       entity.Title,
+		Description: entity.Description,
+		ReleaseDate: entity.ReleaseDate,
+		Rating:      entity.Rating,
+		StarsID:     entity.StarsID,
+		CreatedAt:   entity.CreatedAt,
+		UpdatedAt:   entity.UpdatedAt,
+	}
+}
+
+// CSVRecord follows ExportHeader, stars ids are separated by semicolons.
+func (p ExportPresenter) CSVRecord() []string {
+	var externalID string
+	if p.ExternalID != nil {
+		externalID = *p.ExternalID
+	}
+
+	starsID := make([]string, len(p.StarsID))
+	for i, id := range p.StarsID {
+		starsID[i] = strconv.Itoa(id)
+	}
+
+	return []string{
+		strconv.Itoa(p.ID),
+		externalID,
+		p.Title,
+		p.Description,
+		p.ReleaseDate.Format(time.RFC3339),
+		strconv.Itoa(p.Rating),
+		strings.Join(starsID, ";"),
+		p.CreatedAt.Format(time.RFC3339),
+		p.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -1,8 +1,10 @@
 package star
 
 import (
+	"strconv"
 	"time"
 
+	"vk-test-task/api/rest/presenters/image"
 	"vk-test-task/api/rest/presenters/movie"
 	"vk-test-task/internal/core"
 	moviestore "vk-test-task/internal/store/movie"
@@ -18,6 +20,10 @@ type Presenter struct {
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 	DeletedAt *time.Time `json:"deleted_at"`
+	// MatchedAlias is the alias the search term matched, only lists searched by q have it
+	MatchedAlias string `json:"matched_alias,omitempty"`
+	// Images are left out of batch results
+	Images []image.Presenter `json:"images,omitempty"`
 }
 
 type PresenterWithMovies struct {
@@ -25,7 +31,7 @@ type PresenterWithMovies struct {
 	Movies []movie.Presenter `json:"movies"`
 }
 
-func PresentStar(entity star.Entity, movies []moviestore.Entity) PresenterWithMovies {
+func PresentStar(entity star.Entity, movies []moviestore.Entity, images []image.Presenter) PresenterWithMovies {
 	moviesList := make([]movie.Presenter, len(movies))
 	for i, m := range movies {
 		moviesList[i] = movie.Presenter{
@@ -37,6 +43,7 @@ func PresentStar(entity star.Entity, movies []moviestore.Entity) PresenterWithMo
 			CreatedAt:   m.CreatedAt,
 			UpdatedAt:   m.UpdatedAt,
 			DeletedAt:   m.DeletedAt,
+			Locale:      m.Locale,
 		}
 	}
 
@@ -49,6 +56,7 @@ func PresentStar(entity star.Entity, movies []moviestore.Entity) PresenterWithMo
 			CreatedAt: entity.CreatedAt,
 			UpdatedAt: entity.UpdatedAt,
 			DeletedAt: entity.DeletedAt,
+			Images:    images,
 		},
 		Movies: moviesList,
 	}
@@ -63,18 +71,20 @@ type ListPresenter struct {
 	pagination web.PaginationBody
 }
 
-func PresentList(entities []star.Entity, pq web.PaginationQuery, total int) ListPresenter {
+func PresentList(entities []star.Entity, images map[int][]image.Presenter, pq web.PaginationQuery, total int) ListPresenter {
 	pres := ListPresenter{}
 
 	for _, entity := range entities {
 		starPresenter := Presenter{
-			ID:        entity.ID,
-			Name:      entity.Name,
-			Sex:       entity.Sex,
-			BirthDate: entity.BirthDate,
-			CreatedAt: entity.CreatedAt,
-			UpdatedAt: entity.UpdatedAt,
-			DeletedAt: entity.DeletedAt,
+			ID:           entity.ID,
+			Name:         entity.Name,
+			Sex:          entity.Sex,
+			BirthDate:    entity.BirthDate,
+			CreatedAt:    entity.CreatedAt,
+			UpdatedAt:    entity.UpdatedAt,
+			DeletedAt:    entity.DeletedAt,
+			MatchedAlias: entity.MatchedAlias,
+			Images:       images[entity.ID],
 		}
 		pres.stars = append(pres.stars, starPresenter)
 	}
@@ -92,3 +102,46 @@ func PresentList(entities []star.Entity, pq web.PaginationQuery, total int) List
 func (p *ListPresenter) Response() web.Response {
 	return web.OKResponse(core.StarsReceivedCode, p.stars, p.pagination)
 }
+
+// ExportHeader is the CSV header of exported stars.
+var ExportHeader = []string{"id", "external_id", "name", "sex", "birth_date", "created_at", "updated_at"}
+
+type ExportPresenter struct {
+	ID         int       `json:"id"`
+	ExternalID *string   `json:"external_id"`
+	Name       string    `json:"name"`
+	Sex        string    `json:"sex"`
+	BirthDate  time.Time `json:"birth_date"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func PresentExport(entity star.ExportEntity) ExportPresenter {
+	return ExportPresenter{
+		ID:         entity.ID,
+		ExternalID: entity.ExternalID,
+		Name:       entity.Name,
+		Sex:        entity.Sex,
+		BirthDate:  entity.BirthDate,
+		CreatedAt:  entity.CreatedAt,
+		UpdatedAt:  entity.UpdatedAt,
+	}
+}
+
+// CSVRecord follows ExportHeader.
+func (p ExportPresenter) CSVRecord() []string {
+	var externalID string
+	if p.ExternalID != nil {
+		externalID = *p.ExternalID
+	}
+
+	return []string{
+		strconv.Itoa(p.ID),
+		externalID,
+		p.Name,
+		p.Sex,
+		p.BirthDate.Format(time.RFC3339),
+		p.CreatedAt.Format(time.RFC3339),
+		p.UpdatedAt.Format(time.RFC3339),
+	}
+}
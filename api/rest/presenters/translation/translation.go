@@ -0,0 +1,32 @@
+package translation
+
+import (
+	"time"
+
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/pkg/web"
+)
+
+type Presenter struct {
+	MovieID     int       `json:"movie_id"`
+	Locale      string    `json:"locale"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func PresentTranslation(entity movie.TranslationEntity) Presenter {
+	return Presenter{
+		MovieID:     entity.MovieID,
+		Locale:      entity.Locale,
+		Title:       entity.Title,
+		Description: entity.Description,
+		CreatedAt:   entity.CreatedAt,
+		UpdatedAt:   entity.UpdatedAt,
+	}
+}
+
+func (p *Presenter) Response(msg string) web.Response {
+	return web.OKResponse(msg, *p, nil)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"vk-test-task/pkg/client"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// config is saved by login, so that the following commands reuse the token.
+type config struct {
+	Server   string `yaml:"server"`
+	Username string `yaml:"username"`
+	Token    string `yaml:"token"`
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ".filmoteka.yaml"
+	}
+
+	return filepath.Join(dir, "filmoteka", "config.yaml")
+}
+
+func loadConfig(path string) (config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config{}, nil
+	}
+	if err != nil {
+		return config{}, err
+	}
+
+	var cfg config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return config{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+func saveConfig(path string, cfg config) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// The file holds the token, keep it private
+	return os.WriteFile(path, data, 0o600)
+}
+
+// serverAddr returns the --server flag, the server saved by login or the default one.
+func serverAddr(c *cli.Context, cfg config) string {
+	switch {
+	case c.String("server") != "":
+		return c.String("server")
+	case cfg.Server != "":
+		return cfg.Server
+	default:
+		return defaultServer
+	}
+}
+
+// newClient returns a client authorized with the saved token.
+func newClient(c *cli.Context) (*client.Client, error) {
+	cfg, err := loadConfig(c.String("config"))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Token == "" {
+		return nil, errors.New("not logged in, run filmoteka-cli login")
+	}
+
+	return client.New(serverAddr(c, cfg), client.WithToken(cfg.Token)), nil
+}
+
+// apiError adds a hint to errors the user can fix.
+func apiError(err error) error {
+	switch {
+	case errors.Is(err, client.ErrUnauthorized):
+		return fmt.Errorf("%w, the token may have expired, run filmoteka-cli login", err)
+	default:
+		return err
+	}
+}
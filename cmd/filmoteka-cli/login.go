@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"vk-test-task/pkg/client"
+
+	"github.com/urfave/cli/v2"
+)
+
+var loginCmd = cli.Command{
+	Name:  "login",
+	Usage: "Log in and save the token to the config file",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "username",
+			Aliases:  []string{"u"},
+			Usage:    "username",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:    "password",
+			Aliases: []string{"p"},
+			Usage:   "password, read from stdin when not set",
+			EnvVars: []string{"FILMOTEKA_PASSWORD"},
+		},
+	},
+	Action: runLogin,
+}
+
+var logoutCmd = cli.Command{
+	Name:  "logout",
+	Usage: "Remove the saved token",
+	Action: func(c *cli.Context) error {
+		cfg, err := loadConfig(c.String("config"))
+		if err != nil {
+			return err
+		}
+
+		cfg.Token = ""
+		return saveConfig(c.String("config"), cfg)
+	},
+}
+
+func runLogin(c *cli.Context) error {
+	cfg, err := loadConfig(c.String("config"))
+	if err != nil {
+		return err
+	}
+
+	password := c.String("password")
+	if password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("read password: %w", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	cfg.Server = serverAddr(c, cfg)
+	cfg.Username = c.String("username")
+
+	token, err := client.New(cfg.Server).Login(c.Context, cfg.Username, password)
+	if err != nil {
+		return err
+	}
+	cfg.Token = token.AccessToken
+
+	if err := saveConfig(c.String("config"), cfg); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Logged in to %s as %s\n", cfg.Server, cfg.Username)
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+)
+
+var globalFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "server",
+		Usage:   "filmoteka API address, the address saved by login is used by default",
+		EnvVars: []string{"FILMOTEKA_SERVER"},
+	},
+	&cli.StringFlag{
+		Name:    "config",
+		Usage:   "config file with the server address and the token",
+		EnvVars: []string{"FILMOTEKA_CONFIG"},
+		Value:   defaultConfigPath(),
+	},
+	&cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "output format: table, json or yaml",
+		Value:   tableOutput,
+	},
+}
+
+func main() {
+	app := &cli.App{
+		Name:  "filmoteka-cli",
+		Usage: "Filmoteka API client",
+		Commands: []*cli.Command{
+			&loginCmd,
+			&logoutCmd,
+			&moviesCmd,
+			&movieCmd,
+			&starsCmd,
+			&starCmd,
+		},
+		Flags: globalFlags,
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"vk-test-task/pkg/client"
+
+	"github.com/urfave/cli/v2"
+)
+
+var fileFlag = &cli.StringFlag{
+	Name:    "file",
+	Aliases: []string{"f"},
+	Usage:   "JSON file with the request body, - for stdin",
+}
+
+var moviesCmd = cli.Command{
+	Name:  "movies",
+	Usage: "List movies",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "list",
+			Usage: "List movies with search and sorting",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "q", Usage: "search by title, alias or star name"},
+				&cli.StringFlag{Name: "sort", Usage: "sort by title, rating or release_date, e.g. rating,desc"},
+				&cli.IntFlag{Name: "page", Usage: "page number"},
+				&cli.IntFlag{Name: "limit", Usage: "movies per page"},
+			},
+			Action: listMovies,
+		},
+	},
+}
+
+var movieCmd = cli.Command{
+	Name:  "movie",
+	Usage: "Show, create, update or delete a movie",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "show",
+			Usage:     "Show a movie",
+			ArgsUsage: "<id>",
+			Action:    showMovie,
+		},
+		{
+			Name:   "create",
+			Usage:  "Create a movie from a JSON file with title, description, release_date, rating and stars_id",
+			Flags:  []cli.Flag{fileFlag},
+			Action: createMovie,
+		},
+		{
+			Name:      "update",
+			Usage:     "Update fields of a movie from a JSON file",
+			ArgsUsage: "<id>",
+			Flags:     []cli.Flag{fileFlag},
+			Action:    updateMovie,
+		},
+		{
+			Name:      "delete",
+			Usage:     "Delete a movie",
+			ArgsUsage: "<id>",
+			Action:    deleteMovie,
+		},
+	},
+}
+
+func listMovies(c *cli.Context) error {
+	api, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	list, err := api.ListMovies(c.Context, client.ListMoviesParams{
+		Q:     c.String("q"),
+		Sort:  c.String("sort"),
+		Page:  c.Int("page"),
+		Limit: c.Int("limit"),
+	})
+	if err != nil {
+		return apiError(err)
+	}
+
+	return render(c, list, func(w io.Writer) {
+		writeMovies(w, list.Movies)
+		writePagination(w, list.Pagination)
+	})
+}
+
+func showMovie(c *cli.Context) error {
+	id, err := idArg(c, "movie")
+	if err != nil {
+		return err
+	}
+
+	api, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := api.GetMovie(c.Context, id)
+	if err != nil {
+		return apiError(err)
+	}
+
+	return render(c, data, func(w io.Writer) { writeMovie(w, data) })
+}
+
+func createMovie(c *cli.Context) error {
+	var req client.CreateMovieRequest
+	if err := readJSON(c.String("file"), &req); err != nil {
+		return err
+	}
+
+	api, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := api.CreateMovie(c.Context, req)
+	if err != nil {
+		return apiError(err)
+	}
+
+	return render(c, data, func(w io.Writer) { writeMovie(w, data) })
+}
+
+func updateMovie(c *cli.Context) error {
+	id, err := idArg(c, "movie")
+	if err != nil {
+		return err
+	}
+
+	var req client.UpdateMovieRequest
+	if err := readJSON(c.String("file"), &req); err != nil {
+		return err
+	}
+
+	api, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := api.UpdateMovie(c.Context, id, req)
+	if err != nil {
+		return apiError(err)
+	}
+
+	return render(c, data, func(w io.Writer) { writeMovie(w, data) })
+}
+
+func deleteMovie(c *cli.Context) error {
+	id, err := idArg(c, "movie")
+	if err != nil {
+		return err
+	}
+
+	api, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	if err := api.DeleteMovie(c.Context, id); err != nil {
+		return apiError(err)
+	}
+
+	fmt.Fprintf(c.App.ErrWriter, "Movie %d deleted\n", id)
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"vk-test-task/pkg/client"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	tableOutput = "table"
+	jsonOutput  = "json"
+	yamlOutput  = "yaml"
+
+	dateLayout = "2006-01-02"
+)
+
+// render prints value in the --output format, table writes the table one.
+func render(c *cli.Context, value any, table func(w io.Writer)) error {
+	w := c.App.Writer
+
+	switch c.String("output") {
+	case tableOutput:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		table(tw)
+		return tw.Flush()
+	case jsonOutput:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case yamlOutput:
+		return writeYAML(w, value)
+	default:
+		return fmt.Errorf("unsupported output %q, use table, json or yaml", c.String("output"))
+	}
+}
+
+// writeYAML writes value with its JSON field names and order. JSON is decoded
+// as a YAML document and the flow style and quotes it gets from JSON are dropped,
+// the encoder quotes strings where YAML needs it.
+func writeYAML(w io.Writer, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	plainStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func plainStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		plainStyle(child)
+	}
+}
+
+func writeMovies(w io.Writer, movies []client.Movie) {
+	fmt.Fprintln(w, "ID\tTITLE\tRELEASED\tRATING")
+	for _, m := range movies {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\n", m.ID, m.Title, m.ReleaseDate.Format(dateLayout), m.Rating)
+	}
+}
+
+func writeMovie(w io.Writer, m client.Movie) {
+	fmt.Fprintf(w, "ID:\t%d\n", m.ID)
+	fmt.Fprintf(w, "Title:\t%s\n", m.Title)
+	fmt.Fprintf(w, "Description:\t%s\n", m.Description)
+	fmt.Fprintf(w, "Released:\t%s\n", m.ReleaseDate.Format(dateLayout))
+	fmt.Fprintf(w, "Rating:\t%d\n", m.Rating)
+}
+
+func writeStars(w io.Writer, stars []client.Star) {
+	fmt.Fprintln(w, "ID\tNAME\tSEX\tBORN")
+	for _, s := range stars {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.ID, s.Name, s.Sex, s.BirthDate.Format(dateLayout))
+	}
+}
+
+func writeStar(w io.Writer, s client.Star) {
+	fmt.Fprintf(w, "ID:\t%d\n", s.ID)
+	fmt.Fprintf(w, "Name:\t%s\n", s.Name)
+	fmt.Fprintf(w, "Sex:\t%s\n", s.Sex)
+	fmt.Fprintf(w, "Born:\t%s\n", s.BirthDate.Format(dateLayout))
+}
+
+func writePagination(w io.Writer, p client.Pagination) {
+	fmt.Fprintf(w, "\nPage %d of %d, %d total\n", p.CurrentPage, p.PageCount, p.TotalCount)
+}
+
+// readJSON decodes a request file, "-" reads stdin.
+func readJSON(path string, v any) error {
+	if path == "" {
+		return fmt.Errorf("a JSON file is required, set it with -f")
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func idArg(c *cli.Context, name string) (int, error) {
+	id, err := strconv.Atoi(c.Args().First())
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("expected a %s id, e.g. %s 42", name, c.Command.HelpName)
+	}
+
+	return id, nil
+}
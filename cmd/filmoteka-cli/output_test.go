@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"vk-test-task/pkg/client"
+)
+
+func TestWriteYAML(t *testing.T) {
+	list := client.MovieList{
+		Movies: []client.Movie{{
+			ID:          1,
+			Title:       "The Matrix",
+			ReleaseDate: time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC),
+			Rating:      9,
+		}},
+		Pagination: client.Pagination{TotalCount: 1, PageCount: 1, CurrentPage: 1, PerPage: 20},
+	}
+
+	var buf bytes.Buffer
+	if err := writeYAML(&buf, list); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	want := `movies:
+  - id: 1
+    title: The Matrix
+    description: ""
+    release_date: "1999-03-31T00:00:00Z"
+    rating: 9
+    created_at: "0001-01-01T00:00:00Z"
+    updated_at: "0001-01-01T00:00:00Z"
+    deleted_at: null
+pagination:
+  total_count: 1
+  page_count: 1
+  current_page: 1
+  per_page: 20
+`
+	if buf.String() != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, buf.String())
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"vk-test-task/pkg/client"
+
+	"github.com/urfave/cli/v2"
+)
+
+var starsCmd = cli.Command{
+	Name:  "stars",
+	Usage: "List stars",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "list",
+			Usage: "List stars with search",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "q", Usage: "search by name or alias"},
+				&cli.IntFlag{Name: "page", Usage: "page number"},
+				&cli.IntFlag{Name: "limit", Usage: "stars per page"},
+			},
+			Action: listStars,
+		},
+	},
+}
+
+var starCmd = cli.Command{
+	Name:  "star",
+	Usage: "Show, create, update or delete a star",
+	Subcommands: []*cli.Command{
+		{
+			Name:      "show",
+			Usage:     "Show a star with the movies they starred in",
+			ArgsUsage: "<id>",
+			Action:    showStar,
+		},
+		{
+			Name:   "create",
+			Usage:  "Create a star from a JSON file with name, sex and birth_date",
+			Flags:  []cli.Flag{fileFlag},
+			Action: createStar,
+		},
+		{
+			Name:      "update",
+			Usage:     "Update fields of a star from a JSON file",
+			ArgsUsage: "<id>",
+			Flags:     []cli.Flag{fileFlag},
+			Action:    updateStar,
+		},
+		{
+			Name:      "delete",
+			Usage:     "Delete a star",
+			ArgsUsage: "<id>",
+			Action:    deleteStar,
+		},
+	},
+}
+
+func listStars(c *cli.Context) error {
+	api, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	list, err := api.ListStars(c.Context, client.ListStarsParams{
+		Q:     c.String("q"),
+		Page:  c.Int("page"),
+		Limit: c.Int("limit"),
+	})
+	if err != nil {
+		return apiError(err)
+	}
+
+	return render(c, list, func(w io.Writer) {
+		writeStars(w, list.Stars)
+		writePagination(w, list.Pagination)
+	})
+}
+
+func showStar(c *cli.Context) error {
+	id, err := idArg(c, "star")
+	if err != nil {
+		return err
+	}
+
+	api, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := api.GetStar(c.Context, id)
+	if err != nil {
+		return apiError(err)
+	}
+
+	return render(c, data, func(w io.Writer) { writeStarWithMovies(w, data) })
+}
+
+func createStar(c *cli.Context) error {
+	var req client.CreateStarRequest
+	if err := readJSON(c.String("file"), &req); err != nil {
+		return err
+	}
+
+	api, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := api.CreateStar(c.Context, req)
+	if err != nil {
+		return apiError(err)
+	}
+
+	return render(c, data, func(w io.Writer) { writeStar(w, data) })
+}
+
+func updateStar(c *cli.Context) error {
+	id, err := idArg(c, "star")
+	if err != nil {
+		return err
+	}
+
+	var req client.UpdateStarRequest
+	if err := readJSON(c.String("file"), &req); err != nil {
+		return err
+	}
+
+	api, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := api.UpdateStar(c.Context, id, req)
+	if err != nil {
+		return apiError(err)
+	}
+
+	return render(c, data, func(w io.Writer) { writeStarWithMovies(w, data) })
+}
+
+func deleteStar(c *cli.Context) error {
+	id, err := idArg(c, "star")
+	if err != nil {
+		return err
+	}
+
+	api, err := newClient(c)
+	if err != nil {
+		return err
+	}
+
+	if err := api.DeleteStar(c.Context, id); err != nil {
+		return apiError(err)
+	}
+
+	fmt.Fprintf(c.App.ErrWriter, "Star %d deleted\n", id)
+	return nil
+}
+
+func writeStarWithMovies(w io.Writer, data client.StarWithMovies) {
+	writeStar(w, data.Star)
+	if len(data.Movies) != 0 {
+		fmt.Fprintln(w)
+		writeMovies(w, data.Movies)
+	}
+}
@@ -0,0 +1,124 @@
+package filmoteka
+
+import (
+	"fmt"
+	"reflect"
+
+	"vk-test-task/api/inject"
+	"vk-test-task/api/rest/handlers"
+	"vk-test-task/internal/config"
+	"vk-test-task/pkg/logger"
+
+	"github.com/urfave/cli/v2"
+)
+
+// configKey keeps the loaded config in the metadata of the app.
+const configKey = "config"
+
+var configCmd = cli.Command{
+	Name:  "config",
+	Usage: "Inspect the configuration",
+	Subcommands: []*cli.Command{
+		&configPrintCmd,
+	},
+}
+
+var configPrintCmd = cli.Command{
+	Name:   "print",
+	Usage:  "Print the effective configuration with secrets redacted and check it",
+	Action: printConfig,
+}
+
+// LoadConfig reads the config of the commands and sets up the logger with it. It runs
+// before any command, the commands take the config with configOf.
+func LoadConfig(c *cli.Context) error {
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+
+	if err := logger.Setup(cfg.Log); err != nil {
+		return fmt.Errorf("log: %w", err)
+	}
+	c.App.Metadata[configKey] = cfg
+
+	return nil
+}
+
+// loadConfig reads the file of --config and the environment, --log-level, --log-format
+// and the flags of the filmoteka command override both.
+func loadConfig(c *cli.Context) (*config.Config, error) {
+	cfg, err := config.Load(c.String("config"))
+	if err != nil {
+		return nil, err
+	}
+	if c.IsSet("log-level") {
+		cfg.Log.Level = c.String("log-level")
+	}
+	if c.IsSet("log-format") {
+		cfg.Log.Format = c.String("log-format")
+	}
+	overrideWithFlags(c, cfg)
+
+	return cfg, nil
+}
+
+// applyFlags puts the flags of the filmoteka command into the config loaded before
+// them, as they are parsed after the global flags.
+func applyFlags(c *cli.Context) error {
+	overrideWithFlags(c, configOf(c))
+	return nil
+}
+
+func configOf(c *cli.Context) *config.Config {
+	return c.App.Metadata[configKey].(*config.Config)
+}
+
+// printConfig prints the config as YAML, which may be used as a config file. An invalid
+// config is printed as well, the errors are returned after it.
+func printConfig(c *cli.Context) error {
+	cfg := configOf(c)
+	if err := cfg.Redacted().WriteYAML(c.App.Writer); err != nil {
+		return err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config:\n%w", err)
+	}
+
+	return nil
+}
+
+// reloadConfig reads the config again and applies the settings that are safe to change
+// while serving: the log level and the rate limits. Other changes wait for a restart.
+func reloadConfig(c *cli.Context, resolver *handlers.Resolver) error {
+	running := configOf(c)
+
+	cfg, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+	if err := cfg.ValidateReloadable(); err != nil {
+		return err
+	}
+
+	rateLimits, err := inject.RateLimits(cfg.RateLimits)
+	if err != nil {
+		return err
+	}
+	if err := logger.SetLevel(cfg.Log.Level); err != nil {
+		return err
+	}
+	resolver.SetRateLimits(rateLimits)
+
+	restartOnly := *cfg
+	restartOnly.Log.Level, restartOnly.RateLimits = running.Log.Level, running.RateLimits
+	if !reflect.DeepEqual(&restartOnly, running) {
+		logger.Log.Warn("config has changes that apply after a restart only")
+	}
+	running.Log.Level, running.RateLimits = cfg.Log.Level, cfg.RateLimits
+
+	logger.Log.Info("config reloaded", "log_level", cfg.Log.Level, "rate_limits", cfg.RateLimits)
+
+	return nil
+}
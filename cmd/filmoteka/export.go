@@ -0,0 +1,123 @@
+package filmoteka
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"vk-test-task/api/inject"
+	"vk-test-task/api/rest/presenters/export"
+	"vk-test-task/api/rest/presenters/movie"
+	"vk-test-task/api/rest/presenters/star"
+	"vk-test-task/internal/service/filmoteka"
+	moviestore "vk-test-task/internal/store/movie"
+	starstore "vk-test-task/internal/store/star"
+	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/webutil"
+
+	"github.com/urfave/cli/v2"
+)
+
+var exportCmd = cli.Command{
+	Name:   "export",
+	Usage:  "Dump movies and stars to CSV or JSON Lines files",
+	Flags:  exportFlags,
+	Action: runExport,
+}
+
+func runExport(c *cli.Context) error {
+	model := filmoteka.ExportMoviesModel{
+		SearchTerm: c.String("q"),
+		Sort:       c.String("sort"),
+		Format:     c.String("format"),
+	}
+	if model.Format != export.CSVFormat && model.Format != export.JSONLFormat {
+		return fmt.Errorf("unsupported format %q, use csv or jsonl", model.Format)
+	}
+	if !webutil.IsSortParam(model.Sort) {
+		return fmt.Errorf("unsupported sort %q", model.Sort)
+	}
+
+	cfg := configOf(c)
+	if err := cfg.DB.Validate(); err != nil {
+		return fmt.Errorf("invalid config:\n%w", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	service, err := inject.InitializeExporter(ctx, cfg)
+	if err != nil {
+		logger.Log.Error("export: cannot initialize exporter", "error", err.Error())
+		return err
+	}
+
+	dir := c.String("dir")
+
+	stars, err := exportFile(filepath.Join(dir, "stars."+model.Format), model.Format, star.ExportHeader, func(writer export.Writer) error {
+		return service.ExportStars(ctx, func(entity starstore.ExportEntity) error {
+			return writer.Write(star.PresentExport(entity))
+		})
+	})
+	if err != nil {
+		logger.Log.Error("export: cannot export stars", "error", err.Error())
+		return err
+	}
+
+	movies, err := exportFile(filepath.Join(dir, "movies."+model.Format), model.Format, movie.ExportHeader, func(writer export.Writer) error {
+		return service.ExportMovies(ctx, model, func(entity moviestore.ExportEntity) error {
+			return writer.Write(movie.PresentExport(entity))
+		})
+	})
+	if err != nil {
+		logger.Log.Error("export: cannot export movies", "error", err.Error())
+		return err
+	}
+
+	fmt.Fprintf(c.App.Writer, "exported %d stars and %d movies to %s\n", stars, movies, dir)
+
+	return nil
+}
+
+// exportFile writes rows produced by exportFn to path and returns their number.
+func exportFile(path, format string, header []string, exportFn func(export.Writer) error) (int, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	buffered := bufio.NewWriter(file)
+
+	writer, err := export.NewWriter(buffered, format, header)
+	if err != nil {
+		return 0, err
+	}
+
+	counter := &countingWriter{Writer: writer}
+	if err := exportFn(counter); err != nil {
+		return counter.rows, err
+	}
+	if err := writer.Flush(); err != nil {
+		return counter.rows, err
+	}
+	if err := buffered.Flush(); err != nil {
+		return counter.rows, err
+	}
+
+	return counter.rows, file.Close()
+}
+
+type countingWriter struct {
+	export.Writer
+	rows int
+}
+
+func (w *countingWriter) Write(row export.Row) error {
+	w.rows++
+	return w.Writer.Write(row)
+}
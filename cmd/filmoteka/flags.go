@@ -1,42 +1,278 @@
 package filmoteka
 
-import "github.com/urfave/cli/v2"
+import (
+	"vk-test-task/internal/config"
 
+	"github.com/urfave/cli/v2"
+)
+
+// fromConfig stands for the defaults of cmdFlags in the help, they come from the config.
+const fromConfig = "from the config"
+
+// cmdFlags override the settings of the config file and the environment, see
+// overrideWithFlags. Their defaults and environment variables live in the config.
+// overrideWithFlags. Their defaults and environment variables live in the config.
 var cmdFlags = []cli.Flag{
 	&cli.StringFlag{
-		Name:    "server-host",
-		Usage:   "server host",
-		EnvVars: []string{"SERVER_HOST"},
-		Value:   "localhost:8080",
+		Name:  "server-host",
+		Usage: "server host",
+	},
+	&cli.StringFlag{
+		Name:  "grpc-host",
+		Usage: "gRPC server host",
+	},
+	&cli.StringFlag{
+		Name:  "admin-host",
+		Usage: "admin server host with /metrics",
+	},
+	&cli.DurationFlag{
+		Name:        "shutdown-timeout",
+		Usage:       "time given to requests in flight to complete on shutdown",
+		DefaultText: fromConfig,
+	},
+	&cli.DurationFlag{
+		Name:        "read-header-timeout",
+		Usage:       "time to read the request headers",
+		DefaultText: fromConfig,
+	},
+	&cli.DurationFlag{
+		Name:        "read-timeout",
+		Usage:       "time to read the whole request",
+		DefaultText: fromConfig,
+	},
+	&cli.DurationFlag{
+		Name:        "write-timeout",
+		Usage:       "time to write the response, exports are bounded by export-query-timeout instead",
+		DefaultText: fromConfig,
+	},
+	&cli.DurationFlag{
+		Name:        "idle-timeout",
+		Usage:       "time a keep-alive connection waits for the next request",
+		DefaultText: fromConfig,
+	},
+	&cli.Int64Flag{
+		Name:        "max-body-bytes",
+		Usage:       "maximum size of a request body",
+		DefaultText: fromConfig,
+	},
+	&cli.StringFlag{
+		Name:  "tls-cert-file",
+		Usage: "PEM certificate to serve the API over HTTPS, reloaded on SIGHUP",
+	},
+	&cli.StringFlag{
+		Name:  "tls-key-file",
+		Usage: "PEM key of tls-cert-file",
+	},
+	&cli.StringSliceFlag{
+		Name:  "cors-allowed-origins",
+		Usage: "origins of browser clients allowed to call the API, * for any; none turns CORS off",
+	},
+	&cli.StringSliceFlag{
+		Name:  "cors-allowed-methods",
+		Usage: "methods allowed to browser clients",
+	},
+	&cli.StringSliceFlag{
+		Name:  "cors-allowed-headers",
+		Usage: "request headers allowed to browser clients, * for any",
+	},
+	&cli.BoolFlag{
+		Name:  "cors-allow-credentials",
+		Usage: "allow browser clients of the listed origins to send cookies and credentials",
+	},
+	&cli.DurationFlag{
+		Name:        "cors-max-age",
+		Usage:       "how long browsers may cache a preflight response",
+		DefaultText: fromConfig,
+	},
+	&cli.StringFlag{
+		Name:  "rate-limit",
+		Usage: "requests allowed to a user, or an IP on public routes, like 20/s, 10/m or 100/h; 0 turns the limit off",
+	},
+	&cli.StringFlag{
+		Name:  "rate-limit-auth",
+		Usage: "requests to login and sign-up allowed to an IP",
 	},
 	&cli.StringFlag{
-		Name:    "filmoteka-db-host",
-		Usage:   "filmoteka db host",
-		EnvVars: []string{"FILMOTEKA_DB_HOST"},
-		Value:   "localhost:5432",
+		Name:  "rate-limit-batch",
+		Usage: "batch requests allowed to a user",
 	},
 	&cli.StringFlag{
-		Name:    "filmoteka-db-user",
-		Usage:   "filmoteka db user",
-		EnvVars: []string{"FILMOTEKA_DB_USER"},
-		Value:   "user",
+		Name:  "rate-limit-export",
+		Usage: "export requests allowed to a user",
+	},
+	&cli.IntFlag{
+		Name:        "lockout-threshold",
+		Usage:       "failed logins in a row that lock an account out, 0 turns the lockout off",
+		DefaultText: fromConfig,
+	},
+	&cli.DurationFlag{
+		Name:        "lockout-duration",
+		Usage:       "first lock of an account, doubled on every further failure",
+		DefaultText: fromConfig,
+	},
+	&cli.DurationFlag{
+		Name:        "lockout-max-duration",
+		Usage:       "longest lock of an account",
+		DefaultText: fromConfig,
+	},
+	&cli.DurationFlag{
+		Name:        "query-timeout",
+		Usage:       "deadline of the queries made for a request, 0 to wait for the client only",
+		DefaultText: fromConfig,
+	},
+	&cli.DurationFlag{
+		Name:        "batch-query-timeout",
+		Usage:       "deadline of the queries made for a batch request",
+		DefaultText: fromConfig,
+	},
+	&cli.DurationFlag{
+		Name:        "export-query-timeout",
+		Usage:       "deadline of the queries made for an export request",
+		DefaultText: fromConfig,
+	},
+	&cli.StringFlag{
+		Name:  "tracing-exporter",
+		Usage: "where to export spans: none, stdout or otlp",
+	},
+	&cli.StringFlag{
+		Name:  "tracing-otlp-endpoint",
+		Usage: "OTLP gRPC collector host",
+	},
+	&cli.StringFlag{
+		Name:  "filmoteka-db-host",
+		Usage: "filmoteka db host",
+	},
+	&cli.StringFlag{
+		Name:  "filmoteka-db-user",
+		Usage: "filmoteka db user",
+	},
+	&cli.StringFlag{
+		Name:  "filmoteka-db-pass",
+		Usage: "filmoteka db password",
+	},
+	&cli.StringFlag{
+		Name:  "filmoteka-db-name",
+		Usage: "filmoteka db name",
+	},
+	&cli.StringFlag{
+		Name:  "filmoteka-db-sslmode",
+		Usage: "filmoteka db sslmode",
+	},
+}
+
+// overrideWithFlags puts the cmdFlags given on the command line into cfg.
+func overrideWithFlags(c *cli.Context, cfg *config.Config) {
+	override(c, "server-host", c.String, &cfg.Server.Host)
+	override(c, "grpc-host", c.String, &cfg.GRPC.Host)
+	override(c, "admin-host", c.String, &cfg.Admin.Host)
+	override(c, "shutdown-timeout", c.Duration, &cfg.Server.ShutdownTimeout)
+	override(c, "read-header-timeout", c.Duration, &cfg.Server.ReadHeaderTimeout)
+	override(c, "read-timeout", c.Duration, &cfg.Server.ReadTimeout)
+	override(c, "write-timeout", c.Duration, &cfg.Server.WriteTimeout)
+	override(c, "idle-timeout", c.Duration, &cfg.Server.IdleTimeout)
+	override(c, "max-body-bytes", c.Int64, &cfg.Server.MaxBodyBytes)
+	override(c, "tls-cert-file", c.String, &cfg.Server.TLSCertFile)
+	override(c, "tls-key-file", c.String, &cfg.Server.TLSKeyFile)
+	override(c, "cors-allowed-origins", c.StringSlice, &cfg.CORS.AllowedOrigins)
+	override(c, "cors-allowed-methods", c.StringSlice, &cfg.CORS.AllowedMethods)
+	override(c, "cors-allowed-headers", c.StringSlice, &cfg.CORS.AllowedHeaders)
+	override(c, "cors-allow-credentials", c.Bool, &cfg.CORS.AllowCredentials)
+	override(c, "cors-max-age", c.Duration, &cfg.CORS.MaxAge)
+	override(c, "rate-limit", c.String, &cfg.RateLimits.Default)
+	override(c, "rate-limit-auth", c.String, &cfg.RateLimits.Auth)
+	override(c, "rate-limit-batch", c.String, &cfg.RateLimits.Batch)
+	override(c, "rate-limit-export", c.String, &cfg.RateLimits.Export)
+	override(c, "lockout-threshold", c.Int, &cfg.Lockout.Threshold)
+	override(c, "lockout-duration", c.Duration, &cfg.Lockout.Duration)
+	override(c, "lockout-max-duration", c.Duration, &cfg.Lockout.MaxDuration)
+	override(c, "query-timeout", c.Duration, &cfg.QueryTimeouts.Default)
+	override(c, "batch-query-timeout", c.Duration, &cfg.QueryTimeouts.Batch)
+	override(c, "export-query-timeout", c.Duration, &cfg.QueryTimeouts.Export)
+	override(c, "tracing-exporter", c.String, &cfg.Tracing.Exporter)
+	override(c, "tracing-otlp-endpoint", c.String, &cfg.Tracing.OTLPEndpoint)
+	override(c, "filmoteka-db-host", c.String, &cfg.DB.Host)
+	override(c, "filmoteka-db-user", c.String, &cfg.DB.User)
+	override(c, "filmoteka-db-pass", c.String, &cfg.DB.Password)
+	override(c, "filmoteka-db-name", c.String, &cfg.DB.Name)
+	override(c, "filmoteka-db-sslmode", c.String, &cfg.DB.SSLMode)
+}
+
+// override sets *setting to the value of the flag name if it was given.
+func override[T any](c *cli.Context, name string, value func(string) T, setting *T) {
+	if c.IsSet(name) {
+		*setting = value(name)
+	}
+}
+
+var importFlags = append([]cli.Flag{
+	&cli.StringFlag{
+		Name:  "movies",
+		Usage: "movies file (.csv or .jsonl) with external_id, title, description, release_date, rating",
+	},
+	&cli.StringFlag{
+		Name:  "stars",
+		Usage: "stars file (.csv or .jsonl) with external_id, name, sex, birth_date",
+	},
+	&cli.StringFlag{
+		Name:  "credits",
+		Usage: "credits file (.csv or .jsonl) with movie_external_id, star_external_id",
+	},
+}, importRunFlags()...)
+
+var importIMDbFlags = append([]cli.Flag{
+	&cli.StringFlag{
+		Name:     "dir",
+		Usage:    "directory with title.basics, title.ratings, title.principals and name.basics .tsv.gz files",
+		Required: true,
+	},
+	&cli.StringSliceFlag{
+		Name:  "title-types",
+		Usage: "imported title types",
+		Value: cli.NewStringSlice("movie"),
+	},
+	&cli.IntFlag{
+		Name:        "min-votes",
+		Usage:       "skip titles rated by fewer users",
+		DefaultText: fromConfig,
+		Value:       1000,
+	},
+}, importRunFlags()...)
+
+func importRunFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "validate files and report rejected rows without writing to the database",
+		},
+		&cli.IntFlag{
+			Name:  "chunk-size",
+			Usage: "number of rows written in one transaction",
+			Value: 500,
+		},
+		&cli.StringFlag{
+			Name:  "report",
+			Usage: "write rejected rows to this CSV file",
+		},
+	}
+}
+
+var exportFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "dir",
+		Usage: "directory to write movies and stars files to",
+		Value: ".",
 	},
 	&cli.StringFlag{
-		Name:    "filmoteka-db-pass",
-		Usage:   "filmoteka db password",
-		EnvVars: []string{"FILMOTEKA_DB_PASS"},
-		Value:   "pass",
+		Name:  "format",
+		Usage: "csv or jsonl",
+		Value: "csv",
 	},
 	&cli.StringFlag{
-		Name:    "filmoteka-db-name",
-		Usage:   "filmoteka db name",
-		EnvVars: []string{"FILMOTEKA_DB_NAME"},
-		Value:   "vk",
+		Name:  "q",
+		Usage: "export only movies matching the search term",
 	},
 	&cli.StringFlag{
-		Name:    "filmoteka-db-sslmode",
-		Usage:   "filmoteka db sslmode",
-		EnvVars: []string{"FILMOTEKA_DB_SSLMODE"},
-		Value:   "disable",
+		Name:  "sort",
+		Usage: "movies sort order, e.g. title,asc",
 	},
 }
@@ -0,0 +1,171 @@
+package filmoteka
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"vk-test-task/api/inject"
+	"vk-test-task/internal/service/importer"
+	"vk-test-task/pkg/logger"
+
+	"github.com/urfave/cli/v2"
+)
+
+var importCmd = cli.Command{
+	Name:   "import",
+	Usage:  "Import movies, stars and credits from CSV or JSON Lines files",
+	Flags:  importFlags,
+	Action: runImport,
+	Subcommands: []*cli.Command{
+		&importIMDbCmd,
+	},
+}
+
+var importIMDbCmd = cli.Command{
+	Name:   "imdb",
+	Usage:  "Import rated titles and their cast from the IMDb non-commercial datasets",
+	Flags:  importIMDbFlags,
+	Action: runImportIMDb,
+}
+
+// maxPrintedRejected limits the rejected rows printed to the terminal, the full list goes to --report.
+const maxPrintedRejected = 20
+
+func runImport(c *cli.Context) error {
+	files := importer.Files{
+		Movies:  c.String("movies"),
+		Stars:   c.String("stars"),
+		Credits: c.String("credits"),
+	}
+	if files.Movies == "" && files.Stars == "" && files.Credits == "" {
+		return fmt.Errorf("at least one of --movies, --stars or --credits is required")
+	}
+
+	return importWith(c, func(ctx context.Context, service importer.Service, opts importer.Options) (importer.Report, error) {
+		return service.Import(ctx, files, opts)
+	})
+}
+
+func runImportIMDb(c *cli.Context) error {
+	files := importer.IMDbDir(c.String("dir"))
+
+	return importWith(c, func(ctx context.Context, service importer.Service, opts importer.Options) (importer.Report, error) {
+		return service.ImportIMDb(ctx, files, importer.IMDbOptions{
+			Options:    opts,
+			TitleTypes: c.StringSlice("title-types"),
+			MinVotes:   c.Int("min-votes"),
+		})
+	})
+}
+
+func importWith(c *cli.Context, importFn func(context.Context, importer.Service, importer.Options) (importer.Report, error)) error {
+	cfg := configOf(c)
+	if err := cfg.DB.Validate(); err != nil {
+		return fmt.Errorf("invalid config:\n%w", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	service, closePool, err := inject.InitializeImporter(ctx, cfg)
+	if err != nil {
+		logger.Log.Error("import: cannot initialize importer", "error", err.Error())
+		return err
+	}
+	defer closePool()
+
+	report, err := importFn(ctx, service, importer.Options{
+		DryRun:    c.Bool("dry-run"),
+		ChunkSize: c.Int("chunk-size"),
+	})
+	printImportReport(c.App.Writer, report, c.Bool("dry-run"))
+
+	if path := c.String("report"); path != "" {
+		if reportErr := writeRejectedRows(path, report.Rejected); reportErr != nil {
+			logger.Log.Error("import: cannot write report", "error", reportErr.Error())
+		}
+	}
+
+	if err != nil {
+		logger.Log.Error("import: interrupted, run it again to resume", "error", err.Error())
+	}
+
+	return err
+}
+
+func printImportReport(w io.Writer, report importer.Report, dryRun bool) {
+	if dryRun {
+		fmt.Fprintln(w, "dry run, nothing was written")
+	}
+
+	fmt.Fprintf(w, "%-8s %8s %8s %8s %8s\n", "file", "read", "imported", "skipped", "rejected")
+	for _, row := range []struct {
+		name   string
+		counts importer.Counts
+	}{
+		{importer.StarsFile, report.Stars},
+		{importer.CreditsFile, report.Credits},
+		{importer.MoviesFile, report.Movies},
+	} {
+		fmt.Fprintf(w, "%-8s %8d %8d %8d %8d\n", row.name, row.counts.Read, row.counts.Imported, row.counts.Skipped, row.counts.Rejected)
+	}
+
+	for i, row := range report.Rejected {
+		if i == maxPrintedRejected {
+			fmt.Fprintf(w, "... and %d more rejected rows, use --report to save all of them\n", len(report.Rejected)-i)
+			break
+		}
+		fmt.Fprintf(w, "%s:%d %s %s %s\n", row.File, row.Line, row.ExternalID, row.MsgCode, formatValidationErrors(row))
+	}
+}
+
+func writeRejectedRows(path string, rows []importer.RejectedRow) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// the rows may only reach the disk on close, so its error is not ignored
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write([]string{"file", "line", "external_id", "msg_code", "errors"}); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write([]string{
+			row.File,
+			strconv.Itoa(row.Line),
+			row.ExternalID,
+			row.MsgCode,
+			formatValidationErrors(row),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func formatValidationErrors(row importer.RejectedRow) string {
+	errors := make([]string, len(row.Errors))
+	for i, err := range row.Errors {
+		errors[i] = err.Field + ":" + err.Tag
+		if err.Param != "" {
+			errors[i] += "=" + err.Param
+		}
+	}
+
+	return strings.Join(errors, ";")
+}
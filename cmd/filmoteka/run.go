@@ -2,12 +2,15 @@ package filmoteka
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"vk-test-task/api/inject"
+	"vk-test-task/api/rest/handlers"
 	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/tracing"
 
 	"github.com/urfave/cli/v2"
 )
@@ -16,10 +19,21 @@ var Cmd = cli.Command{
 	Name:   "filmoteka",
 	Usage:  "Run filmoteka API",
 	Flags:  cmdFlags,
+	Before: applyFlags,
 	Action: run,
+	Subcommands: []*cli.Command{
+		&importCmd,
+		&exportCmd,
+		&configCmd,
+	},
 }
 
 func run(c *cli.Context) error {
+	cfg := configOf(c)
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config:\n%w", err)
+	}
+
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, os.Interrupt)
 	signal.Notify(sig, syscall.SIGTERM)
@@ -37,17 +51,54 @@ func run(c *cli.Context) error {
 		}
 	}()
 
-	app, err := inject.InitializeApplication(c, ctx)
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Options{
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+	})
+	if err != nil {
+		logger.Log.Error("main: cannot setup tracing", "error", err.Error())
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Log.Error("cannot flush spans", "error", err.Error())
+		}
+	}()
+
+	app, err := inject.InitializeApplication(ctx, cfg)
 	if err != nil {
 		logger.Log.Error("main: cannot initialize server", "error", err.Error())
-		os.Exit(1)
+		return err
 	}
-	logger.Log.Info("server started", "address", app.Resolver.GetAddr())
 
-	app.Resolver.Run() // nolint
+	go reloadOnHUP(ctx, c, app.Resolver)
 
-	<-ctx.Done()
-	logger.Log.Debug("ctx end received")
+	// a server that fails to start is returned as an error, so the process exits non-zero
+	return app.Run(ctx, cfg.Server.ShutdownTimeout)
+}
 
-	return nil
+// reloadOnHUP reads the TLS key pair of the API again on SIGHUP, e.g. after it was
+// renewed, without dropping connections, and applies the reloadable settings of the config.
+func reloadOnHUP(ctx context.Context, c *cli.Context, resolver *handlers.Resolver) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if err := resolver.ReloadCertificate(); err != nil {
+				logger.Log.Error("cannot reload tls certificate", "error", err.Error())
+			} else {
+				logger.Log.Info("tls certificate reloaded")
+			}
+
+			// an invalid config leaves the running settings as they are
+			if err := reloadConfig(c, resolver); err != nil {
+				logger.Log.Error("cannot reload config", "error", err.Error())
+			}
+		}
+	}
 }
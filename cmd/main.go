@@ -5,17 +5,23 @@ import (
 	"os"
 
 	"vk-test-task/cmd/filmoteka"
-	"vk-test-task/pkg/logger"
 
 	"github.com/urfave/cli/v2"
 )
 
 var globalFlags = []cli.Flag{
 	&cli.StringFlag{
-		Name:    "log-level",
-		Usage:   "log level",
-		EnvVars: []string{"LOG_LEVEL"},
-		Value:   "local",
+		Name:    "config",
+		Usage:   "YAML or TOML config file, the environment overrides its settings",
+		EnvVars: []string{"CONFIG_FILE"},
+	},
+	&cli.StringFlag{
+		Name:  "log-level",
+		Usage: "log level: debug, info, warn or error, overrides the config",
+	},
+	&cli.StringFlag{
+		Name:  "log-format",
+		Usage: "log format: text or json, overrides the config",
 	},
 }
 
@@ -31,10 +37,8 @@ func main() {
 		Commands: []*cli.Command{
 			&filmoteka.Cmd,
 		},
-		Flags: globalFlags,
-		Before: cli.BeforeFunc(func(c *cli.Context) error {
-			return logger.SetupLogger(c.String("log-level"))
-		}),
+		Flags:  globalFlags,
+		Before: filmoteka.LoadConfig,
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -0,0 +1,287 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"vk-test-task/pkg/jwt"
+	"vk-test-task/pkg/logger"
+
+	"github.com/BurntSushi/toml"
+	"github.com/caarlos0/env/v8"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secrets in Redacted.
+const redacted = "[redacted]"
+
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+type (
+	// Config is the configuration of the service. It is read from the defaults, then a
+	// YAML or TOML file, then the environment, each one overriding the previous.
+	// Locales are the locales movies are translated to, the first one is the locale of the
+	// movies themselves.
+	Config struct {
+		Log           logger.Options `yaml:"log" toml:"log"`
+		Server        Server         `yaml:"server" toml:"server"`
+		GRPC          GRPC           `yaml:"grpc" toml:"grpc"`
+		Admin         Admin          `yaml:"admin" toml:"admin"`
+		CORS          CORS           `yaml:"cors" toml:"cors"`
+		RateLimits    RateLimits     `yaml:"rate_limits" toml:"rate_limits"`
+		Lockout       Lockout        `yaml:"lockout" toml:"lockout"`
+		QueryTimeouts QueryTimeouts  `yaml:"query_timeouts" toml:"query_timeouts"`
+		Tracing       Tracing        `yaml:"tracing" toml:"tracing"`
+		DB            DB             `yaml:"db" toml:"db"`
+		Images        Images         `yaml:"images" toml:"images"`
+		Locales       []string       `yaml:"locales" toml:"locales" env:"LOCALES"`
+		JWT           jwt.Config     `yaml:"jwt" toml:"jwt"`
+	}
+
+	Server struct {
+		Host              string        `yaml:"host" toml:"host" env:"SERVER_HOST"`
+		ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+		ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" toml:"read_header_timeout" env:"READ_HEADER_TIMEOUT"`
+		ReadTimeout       time.Duration `yaml:"read_timeout" toml:"read_timeout" env:"READ_TIMEOUT"`
+		WriteTimeout      time.Duration `yaml:"write_timeout" toml:"write_timeout" env:"WRITE_TIMEOUT"`
+		IdleTimeout       time.Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"IDLE_TIMEOUT"`
+		MaxBodyBytes      int64         `yaml:"max_body_bytes" toml:"max_body_bytes" env:"MAX_BODY_BYTES"`
+		TLSCertFile       string        `yaml:"tls_cert_file" toml:"tls_cert_file" env:"TLS_CERT_FILE"`
+		TLSKeyFile        string        `yaml:"tls_key_file" toml:"tls_key_file" env:"TLS_KEY_FILE"`
+	}
+
+	GRPC struct {
+		Host string `yaml:"host" toml:"host" env:"GRPC_HOST"`
+	}
+
+	Admin struct {
+		Host string `yaml:"host" toml:"host" env:"ADMIN_HOST"`
+	}
+
+	CORS struct {
+		AllowedOrigins   []string      `yaml:"allowed_origins" toml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+		AllowedMethods   []string      `yaml:"allowed_methods" toml:"allowed_methods" env:"CORS_ALLOWED_METHODS"`
+		AllowedHeaders   []string      `yaml:"allowed_headers" toml:"allowed_headers" env:"CORS_ALLOWED_HEADERS"`
+		AllowCredentials bool          `yaml:"allow_credentials" toml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"`
+		MaxAge           time.Duration `yaml:"max_age" toml:"max_age" env:"CORS_MAX_AGE"`
+	}
+
+	// RateLimits are rates like "20/s", "10/m" or "100/h", "0" turns a limit off.
+	RateLimits struct {
+		Default string `yaml:"default" toml:"default" env:"RATE_LIMIT"`
+		Auth    string `yaml:"auth" toml:"auth" env:"RATE_LIMIT_AUTH"`
+		Batch   string `yaml:"batch" toml:"batch" env:"RATE_LIMIT_BATCH"`
+		Export  string `yaml:"export" toml:"export" env:"RATE_LIMIT_EXPORT"`
+	}
+
+	Lockout struct {
+		Threshold   int           `yaml:"threshold" toml:"threshold" env:"LOCKOUT_THRESHOLD"`
+		Duration    time.Duration `yaml:"duration" toml:"duration" env:"LOCKOUT_DURATION"`
+		MaxDuration time.Duration `yaml:"max_duration" toml:"max_duration" env:"LOCKOUT_MAX_DURATION"`
+	}
+
+	QueryTimeouts struct {
+		Default time.Duration `yaml:"default" toml:"default" env:"QUERY_TIMEOUT"`
+		Batch   time.Duration `yaml:"batch" toml:"batch" env:"BATCH_QUERY_TIMEOUT"`
+		Export  time.Duration `yaml:"export" toml:"export" env:"EXPORT_QUERY_TIMEOUT"`
+	}
+
+	Tracing struct {
+		Exporter     string `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER"`
+		OTLPEndpoint string `yaml:"otlp_endpoint" toml:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	}
+
+	DB struct {
+		Host     string `yaml:"host" toml:"host" env:"FILMOTEKA_DB_HOST"`
+		User     string `yaml:"user" toml:"user" env:"FILMOTEKA_DB_USER"`
+		Password string `yaml:"password" toml:"password" env:"FILMOTEKA_DB_PASSWORD"`
+		Name     string `yaml:"name" toml:"name" env:"FILMOTEKA_DB_NAME"`
+		SSLMode  string `yaml:"sslmode" toml:"sslmode" env:"FILMOTEKA_DB_SSLMODE"`
+	}
+
+	// Images are kept in a directory or an S3 bucket, see Storage.
+	Images struct {
+		// Storage is local or s3
+		Storage string `yaml:"storage" toml:"storage" env:"IMAGES_STORAGE"`
+		Dir     string `yaml:"dir" toml:"dir" env:"IMAGES_DIR"`
+		S3      S3     `yaml:"s3" toml:"s3"`
+		// MaxBytes limits the size of an uploaded image
+		MaxBytes int64 `yaml:"max_bytes" toml:"max_bytes" env:"IMAGES_MAX_BYTES"`
+		// MaxPixels limits width by height of an uploaded image, small files may claim
+		// huge images that would take gigabytes to decode
+		MaxPixels int `yaml:"max_pixels" toml:"max_pixels" env:"IMAGES_MAX_PIXELS"`
+		// PublicURL is prepended to the keys of images in the answers, e.g. the URL of a
+		// CDN in front of the bucket. By default images are served by the API.
+		PublicURL string `yaml:"public_url" toml:"public_url" env:"IMAGES_PUBLIC_URL"`
+	}
+
+	S3 struct {
+		Endpoint  string `yaml:"endpoint" toml:"endpoint" env:"IMAGES_S3_ENDPOINT"`
+		Bucket    string `yaml:"bucket" toml:"bucket" env:"IMAGES_S3_BUCKET"`
+		AccessKey string `yaml:"access_key" toml:"access_key" env:"IMAGES_S3_ACCESS_KEY"`
+		SecretKey string `yaml:"secret_key" toml:"secret_key" env:"IMAGES_S3_SECRET_KEY"`
+		Region    string `yaml:"region" toml:"region" env:"IMAGES_S3_REGION"`
+		Secure    bool   `yaml:"secure" toml:"secure" env:"IMAGES_S3_SECURE"`
+	}
+)
+
+// Default returns the config used when neither a file nor the environment set a value.
+func Default() *Config {
+	return &Config{
+		Log: logger.Options{
+			Level:      "debug",
+			Format:     logger.FormatText,
+			MaxSizeMB:  100,
+			MaxBackups: 5,
+			MaxAgeDays: 30,
+		},
+		Server: Server{
+			Host:              "localhost:8080",
+			ShutdownTimeout:   15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      time.Minute,
+			IdleTimeout:       2 * time.Minute,
+			MaxBodyBytes:      4 << 20,
+		},
+		GRPC:  GRPC{Host: "localhost:9090"},
+		Admin: Admin{Host: "localhost:9100"},
+		CORS: CORS{
+			AllowedMethods: []string{"GET", "POST", "PATCH", "DELETE"},
+			AllowedHeaders: []string{"Authorization", "Content-Type", "Accept", "X-Request-ID", "traceparent"},
+			MaxAge:         10 * time.Minute,
+		},
+		RateLimits: RateLimits{
+			Default: "20/s",
+			Auth:    "10/m",
+			Batch:   "10/m",
+			Export:  "6/m",
+		},
+		Lockout: Lockout{
+			Threshold:   5,
+			Duration:    time.Minute,
+			MaxDuration: time.Hour,
+		},
+		QueryTimeouts: QueryTimeouts{
+			Default: 5 * time.Second,
+			Batch:   30 * time.Second,
+			Export:  10 * time.Minute,
+		},
+		Tracing: Tracing{
+			Exporter:     "none",
+			OTLPEndpoint: "localhost:4317",
+		},
+		DB: DB{
+			Host:     "localhost:5432",
+			User:     "user",
+			Password: "pass",
+			Name:     "vk",
+			SSLMode:  "disable",
+		},
+		Images: Images{
+			Storage:   StorageLocal,
+			Dir:       "images",
+			S3:        S3{Region: "us-east-1", Secure: true},
+			MaxBytes:  10 << 20,
+			MaxPixels: 25_000_000,
+		},
+		Locales: []string{"ru", "en"},
+		JWT: jwt.Config{
+			AccessTokenExpiration: 15 * time.Minute,
+		},
+	}
+}
+
+// Load reads the config file at path, if any, over the defaults and applies the
+// environment over it. Keys unknown to Config are an error, so typos don't go unnoticed.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := decodeFile(path, cfg); err != nil {
+			return nil, fmt.Errorf("config %s: %w", path, err)
+		}
+	}
+
+	// FILMOTEKA_DB_PASS is the name the password was read from before
+	if pass, ok := os.LookupEnv("FILMOTEKA_DB_PASS"); ok {
+		cfg.DB.Password = pass
+	}
+
+	if err := env.Parse(cfg); err != nil {
+		return nil, fmt.Errorf("config environment: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func decodeFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		// an empty file is a file without settings
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return err
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, 0, len(undecoded))
+			for _, key := range undecoded {
+				keys = append(keys, key.String())
+			}
+			sort.Strings(keys)
+			return fmt.Errorf("unknown keys: %s", strings.Join(keys, ", "))
+		}
+	default:
+		return fmt.Errorf("unsupported format %q, expected .yaml, .yml or .toml", ext)
+	}
+
+	return nil
+}
+
+// Redacted returns a copy of the config safe to print, with the secrets hidden.
+func (c *Config) Redacted() *Config {
+	redactedCfg := *c
+	if redactedCfg.DB.Password != "" {
+		redactedCfg.DB.Password = redacted
+	}
+	if redactedCfg.Images.S3.SecretKey != "" {
+		redactedCfg.Images.S3.SecretKey = redacted
+	}
+	if redactedCfg.JWT.Secret != "" {
+		redactedCfg.JWT.Secret = redacted
+	}
+
+	return &redactedCfg
+}
+
+// WriteYAML writes the config in the format Load reads.
+func (c *Config) WriteYAML(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	yamlFile := writeFile(t, "config.yaml", `
+log:
+  level: info
+  format: json
+server:
+  host: ":8080"
+  read_timeout: 10s
+rate_limits:
+  auth: 5/m
+cors:
+  allowed_origins: [https://example.com]
+db:
+  password: from-file
+`)
+	tomlFile := writeFile(t, "config.toml", `
+[log]
+level = "info"
+format = "json"
+
+[server]
+host = ":8080"
+read_timeout = "10s"
+
+[rate_limits]
+auth = "5/m"
+
+[cors]
+allowed_origins = ["https://example.com"]
+
+[db]
+password = "from-file"
+`)
+
+	for _, path := range []string{yamlFile, tomlFile} {
+		t.Run(filepath.Ext(path), func(t *testing.T) {
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			want := Default()
+			want.Log.Level = "info"
+			want.Log.Format = "json"
+			want.Server.Host = ":8080"
+			want.Server.ReadTimeout = 10 * time.Second
+			want.RateLimits.Auth = "5/m"
+			want.CORS.AllowedOrigins = []string{"https://example.com"}
+			want.DB.Password = "from-file"
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("wrong config. Expected %+v but got %+v", want, cfg)
+			}
+		})
+	}
+}
+
+func TestLoadEnvOverridesFile(t *testing.T) {
+	path := writeFile(t, "config.yml", "server:\n  host: \":8080\"\n  read_timeout: 10s\n")
+	t.Setenv("SERVER_HOST", ":9000")
+	t.Setenv("FILMOTEKA_DB_PASSWORD", "from-env")
+	t.Setenv("JWT_ACCESS_TOKEN_EXPIRATION", "1h")
+	t.Setenv("CORS_ALLOWED_METHODS", "GET,POST")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if cfg.Server.Host != ":9000" {
+		t.Errorf("wrong server host. Expected :9000 but got %s", cfg.Server.Host)
+	}
+	if cfg.Server.ReadTimeout != 10*time.Second {
+		t.Errorf("setting of the file is lost. Expected 10s but got %s", cfg.Server.ReadTimeout)
+	}
+	if cfg.DB.Password != "from-env" {
+		t.Errorf("wrong db password. Expected from-env but got %s", cfg.DB.Password)
+	}
+	if cfg.JWT.AccessTokenExpiration != time.Hour {
+		t.Errorf("wrong token expiration. Expected 1h but got %s", cfg.JWT.AccessTokenExpiration)
+	}
+	if want := []string{"GET", "POST"}; !reflect.DeepEqual(cfg.CORS.AllowedMethods, want) {
+		t.Errorf("wrong cors methods. Expected %v but got %v", want, cfg.CORS.AllowedMethods)
+	}
+}
+
+func TestLoadLegacyDBPassword(t *testing.T) {
+	t.Setenv("FILMOTEKA_DB_PASS", "legacy")
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if cfg.DB.Password != "legacy" {
+		t.Errorf("wrong db password. Expected legacy but got %s", cfg.DB.Password)
+	}
+
+	t.Setenv("FILMOTEKA_DB_PASSWORD", "current")
+	if cfg, err = Load(""); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if cfg.DB.Password != "current" {
+		t.Errorf("FILMOTEKA_DB_PASSWORD does not win. Expected current but got %s", cfg.DB.Password)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	for name, test := range map[string]struct {
+		file, content, wantErr string
+	}{
+		"unknown yaml key": {"config.yaml", "server:\n  hots: x\n", "field hots not found"},
+		"unknown toml key": {"config.toml", "[server]\nhots = \"x\"\n", "unknown keys: server.hots"},
+		"bad duration":     {"config.yaml", "server:\n  read_timeout: soon\n", "soon"},
+		"unknown format":   {"config.json", "{}", "unsupported format"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(writeFile(t, test.file, test.content))
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("wrong error. Expected %q but got %v", test.wantErr, err)
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("wrong error of a missing file: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Default()
+	cfg.JWT.Secret = "secret"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	cfg.Log.Level = "verbose"
+	cfg.Log.SampleFirst = -1
+	cfg.Server.Host = "8080"
+	cfg.Server.TLSCertFile = "cert.pem"
+	cfg.CORS.AllowedOrigins = []string{"*"}
+	cfg.CORS.AllowCredentials = true
+	cfg.RateLimits.Batch = "10/d"
+	cfg.Lockout.MaxDuration = time.Second
+	cfg.DB.SSLMode = "on"
+	cfg.Images.Storage = StorageS3
+	cfg.Locales = []string{"ru", "english"}
+	cfg.JWT.Secret = ""
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatalf("invalid config is accepted")
+	}
+	for _, want := range []string{
+		"log.level (LOG_LEVEL)",
+		"log.sample_first (LOG_SAMPLE_FIRST)",
+		"server.host (SERVER_HOST)",
+		"server.tls_key_file (TLS_KEY_FILE)",
+		"cors.allowed_origins (CORS_ALLOWED_ORIGINS)",
+		"rate_limits.batch (RATE_LIMIT_BATCH)",
+		"lockout.max_duration (LOCKOUT_MAX_DURATION)",
+		"db.sslmode (FILMOTEKA_DB_SSLMODE)",
+		"images.s3.bucket (IMAGES_S3_BUCKET)",
+		"locales (LOCALES)",
+		"jwt.secret (JWT_SECRET)",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%s is not reported in %q", want, err.Error())
+		}
+	}
+
+	if err := cfg.ValidateReloadable(); err == nil || strings.Contains(err.Error(), "db.sslmode") {
+		t.Errorf("wrong reloadable settings error: %v", err)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.JWT.Secret = "jwt-secret"
+	cfg.DB.Password = "db-password"
+	cfg.Images.S3.SecretKey = "s3-secret"
+
+	var buf bytes.Buffer
+	if err := cfg.Redacted().WriteYAML(&buf); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	for _, secret := range []string{"jwt-secret", "db-password", "s3-secret"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("%s is printed", secret)
+		}
+	}
+	if cfg.JWT.Secret != "jwt-secret" {
+		t.Errorf("config is changed by Redacted")
+	}
+
+	// the printed config reads back as a config file
+	path := writeFile(t, "printed.yaml", buf.String())
+	printed, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if printed.Server != cfg.Server {
+		t.Errorf("wrong server settings. Expected %+v but got %+v", cfg.Server, printed.Server)
+	}
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	"vk-test-task/pkg/locale"
+	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/ratelimit"
+	"vk-test-task/pkg/tracing"
+)
+
+var (
+	logFormats     = []string{logger.FormatText, logger.FormatJSON}
+	exporters      = []string{tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP}
+	sslModes       = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	storages       = []string{StorageLocal, StorageS3}
+	errNotSet      = errors.New("is not set")
+	errNotPositive = errors.New("must be positive")
+	errNegative    = errors.New("must not be negative")
+)
+
+// Validate checks every setting the API server needs and reports all the invalid ones at
+// once, each named by its key in the file and its environment variable.
+func (c *Config) Validate() error {
+	errs := []error{
+		c.validateLogLevel(),
+		oneOf("log.format", "LOG_FORMAT", c.Log.Format, logFormats),
+		nonNegativeInt("log.max_size_mb", "LOG_MAX_SIZE_MB", c.Log.MaxSizeMB),
+		nonNegativeInt("log.max_backups", "LOG_MAX_BACKUPS", c.Log.MaxBackups),
+		nonNegativeInt("log.max_age_days", "LOG_MAX_AGE_DAYS", c.Log.MaxAgeDays),
+		nonNegativeInt("log.sample_first", "LOG_SAMPLE_FIRST", c.Log.SampleFirst),
+		nonNegativeInt("log.sample_thereafter", "LOG_SAMPLE_THEREAFTER", c.Log.SampleThereafter),
+		c.Server.validate(),
+		validateHost("grpc.host", "GRPC_HOST", c.GRPC.Host),
+		validateHost("admin.host", "ADMIN_HOST", c.Admin.Host),
+		c.CORS.validate(),
+		c.RateLimits.validate(),
+		c.Lockout.validate(),
+		nonNegative("query_timeouts.default", "QUERY_TIMEOUT", c.QueryTimeouts.Default),
+		nonNegative("query_timeouts.batch", "BATCH_QUERY_TIMEOUT", c.QueryTimeouts.Batch),
+		nonNegative("query_timeouts.export", "EXPORT_QUERY_TIMEOUT", c.QueryTimeouts.Export),
+		oneOf("tracing.exporter", "TRACING_EXPORTER", c.Tracing.Exporter, exporters),
+		c.DB.Validate(),
+		c.Images.validate(),
+	}
+	if _, err := locale.NewNegotiator(c.Locales); err != nil {
+		errs = append(errs, settingError("locales", "LOCALES", err))
+	}
+	if c.JWT.Secret == "" {
+		errs = append(errs, settingError("jwt.secret", "JWT_SECRET", errNotSet))
+	}
+	if c.JWT.AccessTokenExpiration <= 0 {
+		errs = append(errs, settingError("jwt.access_token_expiration", "JWT_ACCESS_TOKEN_EXPIRATION", errNotPositive))
+	}
+
+	return errors.Join(errs...)
+}
+
+// ValidateReloadable checks the settings applied on reload.
+func (c *Config) ValidateReloadable() error {
+	return errors.Join(c.validateLogLevel(), c.RateLimits.validate())
+}
+
+func (c *Config) validateLogLevel() error {
+	if _, err := logger.ParseLevel(c.Log.Level); err != nil {
+		return settingError("log.level", "LOG_LEVEL", err)
+	}
+
+	return nil
+}
+
+// Validate checks the settings of the database, the only ones import and export need.
+func (d DB) Validate() error {
+	var errs []error
+	for _, field := range []struct{ key, env, value string }{
+		{"db.host", "FILMOTEKA_DB_HOST", d.Host},
+		{"db.user", "FILMOTEKA_DB_USER", d.User},
+		{"db.name", "FILMOTEKA_DB_NAME", d.Name},
+	} {
+		if field.value == "" {
+			errs = append(errs, settingError(field.key, field.env, errNotSet))
+		}
+	}
+	errs = append(errs, oneOf("db.sslmode", "FILMOTEKA_DB_SSLMODE", d.SSLMode, sslModes))
+
+	return errors.Join(errs...)
+}
+
+func (s Server) validate() error {
+	errs := []error{
+		validateHost("server.host", "SERVER_HOST", s.Host),
+		nonNegative("server.shutdown_timeout", "SHUTDOWN_TIMEOUT", s.ShutdownTimeout),
+		nonNegative("server.read_header_timeout", "READ_HEADER_TIMEOUT", s.ReadHeaderTimeout),
+		nonNegative("server.read_timeout", "READ_TIMEOUT", s.ReadTimeout),
+		nonNegative("server.write_timeout", "WRITE_TIMEOUT", s.WriteTimeout),
+		nonNegative("server.idle_timeout", "IDLE_TIMEOUT", s.IdleTimeout),
+	}
+	if s.MaxBodyBytes < 0 {
+		errs = append(errs, settingError("server.max_body_bytes", "MAX_BODY_BYTES", errNegative))
+	}
+	if (s.TLSCertFile == "") != (s.TLSKeyFile == "") {
+		errs = append(errs, errors.New("server.tls_cert_file (TLS_CERT_FILE) and server.tls_key_file (TLS_KEY_FILE): must be set together"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c CORS) validate() error {
+	errs := []error{nonNegative("cors.max_age", "CORS_MAX_AGE", c.MaxAge)}
+	if c.AllowCredentials && slices.Contains(c.AllowedOrigins, "*") {
+		errs = append(errs, errors.New("cors.allowed_origins (CORS_ALLOWED_ORIGINS): \"*\" can not be used with cors.allow_credentials (CORS_ALLOW_CREDENTIALS)"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (i Images) validate() error {
+	errs := []error{oneOf("images.storage", "IMAGES_STORAGE", i.Storage, storages)}
+	if i.MaxBytes <= 0 {
+		errs = append(errs, settingError("images.max_bytes", "IMAGES_MAX_BYTES", errNotPositive))
+	}
+	if i.MaxPixels <= 0 {
+		errs = append(errs, settingError("images.max_pixels", "IMAGES_MAX_PIXELS", errNotPositive))
+	}
+
+	switch i.Storage {
+	case StorageLocal:
+		if i.Dir == "" {
+			errs = append(errs, settingError("images.dir", "IMAGES_DIR", errNotSet))
+		}
+	case StorageS3:
+		if i.S3.Endpoint == "" {
+			errs = append(errs, settingError("images.s3.endpoint", "IMAGES_S3_ENDPOINT", errNotSet))
+		}
+		if i.S3.Bucket == "" {
+			errs = append(errs, settingError("images.s3.bucket", "IMAGES_S3_BUCKET", errNotSet))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (l RateLimits) validate() error {
+	var errs []error
+	for _, field := range []struct{ key, env, value string }{
+		{"rate_limits.default", "RATE_LIMIT", l.Default},
+		{"rate_limits.auth", "RATE_LIMIT_AUTH", l.Auth},
+		{"rate_limits.batch", "RATE_LIMIT_BATCH", l.Batch},
+		{"rate_limits.export", "RATE_LIMIT_EXPORT", l.Export},
+	} {
+		if _, err := ratelimit.ParseRate(field.value); err != nil {
+			errs = append(errs, settingError(field.key, field.env, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (l Lockout) validate() error {
+	if l.Threshold < 0 {
+		return settingError("lockout.threshold", "LOCKOUT_THRESHOLD", errNegative)
+	}
+	if l.Threshold == 0 {
+		return nil
+	}
+
+	if l.Duration <= 0 {
+		return settingError("lockout.duration", "LOCKOUT_DURATION", errNotPositive)
+	}
+	if l.MaxDuration < l.Duration {
+		return settingError("lockout.max_duration", "LOCKOUT_MAX_DURATION", fmt.Errorf("must not be less than lockout.duration %s", l.Duration))
+	}
+
+	return nil
+}
+
+func validateHost(key, env, host string) error {
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		return settingError(key, env, fmt.Errorf("must be host:port, got %q", host))
+	}
+
+	return nil
+}
+
+func nonNegative(key, env string, d time.Duration) error {
+	if d < 0 {
+		return settingError(key, env, errNegative)
+	}
+
+	return nil
+}
+
+func nonNegativeInt(key, env string, n int) error {
+	if n < 0 {
+		return settingError(key, env, errNegative)
+	}
+
+	return nil
+}
+
+func oneOf(key, env, value string, allowed []string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+
+	return settingError(key, env, fmt.Errorf("must be one of %v, got %q", allowed, value))
+}
+
+func settingError(key, env string, err error) error {
+	return fmt.Errorf("%s (%s): %w", key, env, err)
+}
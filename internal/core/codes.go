@@ -9,10 +9,12 @@ const (
 	UsernameIsTaken      = "username_is_taken"
 	WrongCredentialsCode = "wrong_credentials"
 	InvalidJWTCode       = "invalid_jwt"
+	AccountLockedCode    = "account_locked"
 
 	// parsing resps
 	InvalidIDCode          = "invalid_id"
 	InvalidBodyCode        = "invalid_request_body"
+	BodyTooLargeCode       = "request_body_too_large"
 	InvalidHeaderCode      = "invalid_header"
 	InvalidQueryParamsCode = "invalid_query_params"
 
@@ -21,6 +23,7 @@ const (
 	BodyRequiredCode       = "request_body_is_required"
 	HeaderRequiredCode     = "header_is_required"
 	AuthHeaderRequiredCode = "auth_header_is_required"
+	ValidationCode         = "validation"
 
 	// stars resps
 	StarReceivedCode  = "star_received"
@@ -40,11 +43,47 @@ const (
 
 	MovieNotFoundCode = "movie_not_found"
 
+	// batch resps
+	MoviesBatchProcessedCode = "movies_batch_processed"
+	StarsBatchProcessedCode  = "stars_batch_processed"
+
+	BatchAbortedCode = "batch_aborted"
+
+	// image resps
+	ImageUploadedCode = "image_uploaded"
+
+	UnsupportedImageCode = "unsupported_image_type"
+	ImageNotFoundCode    = "image_not_found"
+	FileRequiredCode     = "file_is_required"
+
+	// translation resps
+	TranslationUpsertedCode = "translation_upserted"
+	TranslationDeletedCode  = "translation_deleted"
+
+	UnsupportedLocaleCode   = "unsupported_locale"
+	TranslationNotFoundCode = "translation_not_found"
+
+	// alias resps
+	AliasesReceivedCode = "aliases_received"
+	AliasCreatedCode    = "alias_created"
+	AliasDeletedCode    = "alias_deleted"
+
+	AliasExistsCode   = "alias_exists"
+	AliasNotFoundCode = "alias_not_found"
+
+	// import resps
+	ExternalIDRequiredCode  = "external_id_is_required"
+	DuplicateExternalIDCode = "duplicate_external_id"
+
 	// general
 	UnauthorizedCode      = "general_unauthorized"
 	AccessDeniedCode      = "general_access_denied"
 	InternalErrorCode     = "general_internal"
 	BadRequestErrorCode   = "general_bad_request_error"
 	UnsupportedMethodCode = "general_unsupported_method"
+	NotAcceptableCode     = "general_not_acceptable"
 	ForbiddenErrorCode    = "general_forbidden"
+	RequestCanceledCode   = "general_request_canceled"
+	QueryTimeoutCode      = "general_query_timeout"
+	TooManyRequestsCode   = "general_too_many_requests"
 )
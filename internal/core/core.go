@@ -7,6 +7,13 @@ const (
 
 	UserRole  = "user"
 	AdminRole = "admin"
+
+	BatchCreateOp = "create"
+	BatchUpdateOp = "update"
+	BatchDeleteOp = "delete"
+
+	// StatusClientClosedRequest answers requests whose client went away before the queries completed.
+	StatusClientClosedRequest = 499
 )
 
 var AllowedSorts = map[string]struct{}{
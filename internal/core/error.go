@@ -5,4 +5,8 @@ import "errors"
 var (
 	ErrUsernameExists  = errors.New("username_exists")
 	ErrStarIDNotExists = errors.New("star_id_not_exists")
+	ErrBatchAborted    = errors.New("batch_aborted")
+
+	ErrUnsupportedImage = errors.New("unsupported_image")
+	ErrAliasExists      = errors.New("alias_exists")
 )
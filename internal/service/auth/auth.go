@@ -16,6 +16,7 @@ type (
 		GetPassHashAndRoleByUsername(context.Context, string) (string, string, error)
 		CreateToken(context.Context, string, string) (jwt.Token, error)
 		Verify(http.ResponseWriter, *http.Request) (*jwt.UserData, bool)
+		VerifyToken(string) (*jwt.UserData, error)
 	}
 
 	SignUpModel struct {
@@ -35,9 +36,8 @@ type (
 	}
 )
 
-func New(users user.Store) (Service, error) {
-	cfg, err := jwt.ParseConfig()
-	if err != nil {
+func New(users user.Store, cfg *jwt.Config) (Service, error) {
+	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
 
@@ -67,7 +67,7 @@ func (s *serviceImpl) SignUp(ctx context.Context, signUpModel SignUpModel) (user
 }
 
 func (s *serviceImpl) GetPassHashAndRoleByUsername(ctx context.Context, username string) (string, string, error) {
-	return s.usersStore.GetPassHashAndRoleByUsername(context.Background(), username)
+	return s.usersStore.GetPassHashAndRoleByUsername(ctx, username)
 }
 
 func (s *serviceImpl) CreateToken(ctx context.Context, username, role string) (jwt.Token, error) {
@@ -78,6 +78,11 @@ func (s *serviceImpl) Verify(w http.ResponseWriter, r *http.Request) (*jwt.UserD
 	return s.jwtService.Verify(w, r)
 }
 
+// VerifyToken checks a token passed outside of an HTTP request, e.g. in gRPC metadata.
+func (s *serviceImpl) VerifyToken(token string) (*jwt.UserData, error) {
+	return s.jwtService.ValidateToken(token)
+}
+
 func (m SignUpModel) toCreateUserEntity() user.CreateEntity {
 	passHash := hash.CalculateHash(m.Password)
 
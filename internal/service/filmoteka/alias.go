@@ -0,0 +1,77 @@
+package filmoteka
+
+import (
+	"context"
+
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/internal/store/star"
+)
+
+type (
+	aliasesService interface {
+		GetMovieAliases(context.Context, int) ([]movie.AliasEntity, error)
+		CreateMovieAlias(context.Context, int, CreateMovieAliasModel) (movie.AliasEntity, error)
+		DeleteMovieAlias(context.Context, int, int) error
+		GetStarAliases(context.Context, int) ([]star.AliasEntity, error)
+		CreateStarAlias(context.Context, int, CreateStarAliasModel) (star.AliasEntity, error)
+		DeleteStarAlias(context.Context, int, int) error
+	}
+
+	CreateMovieAliasModel struct {
+		Alias string `json:"alias" validate:"required,min=1,max=150"`
+		Kind  string `json:"kind" validate:"required,oneof=original working regional"`
+	}
+
+	CreateStarAliasModel struct {
+		Alias string `json:"alias" validate:"required,min=1,max=100"`
+		Kind  string `json:"kind" validate:"required,oneof=stage transliteration"`
+	}
+)
+
+func (s *serviceImpl) GetMovieAliases(ctx context.Context, movieID int) ([]movie.AliasEntity, error) {
+	if _, err := s.moviesStore.GetByID(ctx, movieID); err != nil {
+		return nil, err
+	}
+
+	return s.moviesStore.GetAliases(ctx, movieID)
+}
+
+func (s *serviceImpl) CreateMovieAlias(ctx context.Context, movieID int, model CreateMovieAliasModel) (movie.AliasEntity, error) {
+	if _, err := s.moviesStore.GetByID(ctx, movieID); err != nil {
+		return movie.AliasEntity{}, err
+	}
+
+	return s.moviesStore.CreateAlias(ctx, movie.AliasEntity{
+		MovieID: movieID,
+		Alias:   model.Alias,
+		Kind:    model.Kind,
+	})
+}
+
+func (s *serviceImpl) DeleteMovieAlias(ctx context.Context, movieID, aliasID int) error {
+	return s.moviesStore.DeleteAlias(ctx, movieID, aliasID)
+}
+
+func (s *serviceImpl) GetStarAliases(ctx context.Context, starID int) ([]star.AliasEntity, error) {
+	if _, err := s.starsStore.GetByID(ctx, starID); err != nil {
+		return nil, err
+	}
+
+	return s.starsStore.GetAliases(ctx, starID)
+}
+
+func (s *serviceImpl) CreateStarAlias(ctx context.Context, starID int, model CreateStarAliasModel) (star.AliasEntity, error) {
+	if _, err := s.starsStore.GetByID(ctx, starID); err != nil {
+		return star.AliasEntity{}, err
+	}
+
+	return s.starsStore.CreateAlias(ctx, star.AliasEntity{
+		StarID: starID,
+		Alias:  model.Alias,
+		Kind:   model.Kind,
+	})
+}
+
+func (s *serviceImpl) DeleteStarAlias(ctx context.Context, starID, aliasID int) error {
+	return s.starsStore.DeleteAlias(ctx, starID, aliasID)
+}
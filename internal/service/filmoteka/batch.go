@@ -0,0 +1,185 @@
+package filmoteka
+
+import (
+	"context"
+	"encoding/json"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/internal/store/star"
+)
+
+type (
+	batchService interface {
+		BatchMovies(context.Context, BatchMoviesModel) ([]movie.BatchResult, error)
+		BatchStars(context.Context, BatchStarsModel) ([]star.BatchResult, error)
+	}
+
+	BatchRequestModel struct {
+		Operations []BatchOperationModel `json:"operations" validate:"required,min=1,max=1000"`
+	}
+
+	BatchOperationModel struct {
+		Op   string          `json:"op" validate:"required,oneof=create update delete"`
+		ID   int             `json:"id" validate:"required_unless=Op create"`
+		Data json.RawMessage `json:"data"`
+	}
+
+	BatchMoviesModel struct {
+		Atomic     bool
+		Operations []BatchMovieOperation
+	}
+
+	BatchMovieOperation struct {
+		Op     string
+		ID     int
+		Create *CreateMovieModel
+		Update *UpdateMovieModel
+	}
+
+	BatchStarsModel struct {
+		Atomic     bool
+		Operations []BatchStarOperation
+	}
+
+	BatchStarOperation struct {
+		Op     string
+		ID     int
+		Create *CreateStarModel
+		Update *UpdateStarModel
+	}
+)
+
+func (s *serviceImpl) BatchMovies(ctx context.Context, model BatchMoviesModel) ([]movie.BatchResult, error) {
+	var starsID []int
+	for _, op := range model.Operations {
+		switch {
+		case op.Create != nil:
+			starsID = append(starsID, op.Create.StarsID...)
+		case op.Update != nil:
+			starsID = append(starsID, op.Update.StarsID...)
+		}
+	}
+
+	existing := map[int]struct{}{}
+	if len(starsID) != 0 {
+		var err error
+		existing, err = s.starsStore.GetExistingIDs(ctx, starsID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]movie.BatchResult, len(model.Operations))
+
+	var (
+		ops     []movie.BatchOperation
+		indexes []int
+	)
+	for i, op := range model.Operations {
+		entity, err := op.toBatchOperation(existing)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		ops = append(ops, entity)
+		indexes = append(indexes, i)
+	}
+
+	if model.Atomic && len(ops) != len(model.Operations) {
+		return results, core.ErrBatchAborted
+	}
+	if len(ops) == 0 {
+		return results, nil
+	}
+
+	data, err := s.moviesStore.Batch(ctx, ops, model.Atomic)
+	for i, result := range data {
+		results[indexes[i]] = result
+	}
+
+	return results, err
+}
+
+func (s *serviceImpl) BatchStars(ctx context.Context, model BatchStarsModel) ([]star.BatchResult, error) {
+	results := make([]star.BatchResult, len(model.Operations))
+
+	var (
+		ops     []star.BatchOperation
+		indexes []int
+	)
+	for i, op := range model.Operations {
+		entity, err := op.toBatchOperation()
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		ops = append(ops, entity)
+		indexes = append(indexes, i)
+	}
+
+	if model.Atomic && len(ops) != len(model.Operations) {
+		return results, core.ErrBatchAborted
+	}
+	if len(ops) == 0 {
+		return results, nil
+	}
+
+	data, err := s.starsStore.Batch(ctx, ops, model.Atomic)
+	for i, result := range data {
+		results[indexes[i]] = result
+	}
+
+	return results, err
+}
+
+func (m BatchMovieOperation) toBatchOperation(existingStars map[int]struct{}) (movie.BatchOperation, error) {
+	op := movie.BatchOperation{Op: m.Op, ID: m.ID}
+
+	var starsID []int
+	switch m.Op {
+	case core.BatchCreateOp:
+		entity, err := m.Create.toCreateMovieEntity()
+		if err != nil {
+			return movie.BatchOperation{}, err
+		}
+		op.Create = entity
+		starsID = entity.StarsID
+	case core.BatchUpdateOp:
+		entity, err := m.Update.toUpdateMovieEntity()
+		if err != nil {
+			return movie.BatchOperation{}, err
+		}
+		op.Update = entity
+		starsID = entity.StarsID
+	}
+
+	for _, starID := range starsID {
+		if _, ok := existingStars[starID]; !ok {
+			return movie.BatchOperation{}, core.ErrStarIDNotExists
+		}
+	}
+
+	return op, nil
+}
+
+func (m BatchStarOperation) toBatchOperation() (star.BatchOperation, error) {
+	op := star.BatchOperation{Op: m.Op, ID: m.ID}
+
+	switch m.Op {
+	case core.BatchCreateOp:
+		entity, err := m.Create.toCreateStarEntity()
+		if err != nil {
+			return star.BatchOperation{}, err
+		}
+		op.Create = entity
+	case core.BatchUpdateOp:
+		entity, err := m.Update.toUpdateStarEntity()
+		if err != nil {
+			return star.BatchOperation{}, err
+		}
+		op.Update = entity
+	}
+
+	return op, nil
+}
@@ -0,0 +1,52 @@
+package filmoteka
+
+import (
+	"context"
+
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/internal/store/star"
+	"vk-test-task/pkg/locale"
+)
+
+type (
+	exportService interface {
+		ExportMovies(context.Context, ExportMoviesModel, func(movie.ExportEntity) error) error
+		ExportStars(context.Context, func(star.ExportEntity) error) error
+	}
+
+	ExportMoviesModel struct {
+		SearchTerm string `query:"q" validate:"omitempty,max=150"`
+		Sort       string `query:"sort" validate:"sort_params"`
+		Format     string `query:"format" validate:"required,oneof=csv jsonl"`
+	}
+
+	ExportStarsModel struct {
+		Format string `query:"format" validate:"required,oneof=csv jsonl"`
+	}
+)
+
+// ExportMovies passes the movies to fn localised like the ones of GetMovies, see
+// localizeMovies. The translations are joined by the store, the rows are not held.
+func (s *serviceImpl) ExportMovies(ctx context.Context, model ExportMoviesModel, fn func(movie.ExportEntity) error) error {
+	getAllParams := GetMoviesModel{
+		SearchTerm: model.SearchTerm,
+		Sort:       model.Sort,
+	}.toGetAllParams()
+
+	locales := locale.FromContext(ctx)
+	if len(locales) == 0 {
+		return s.moviesStore.Export(ctx, getAllParams, fn)
+	}
+
+	getAllParams.Locales = locales.Translated()
+	return s.moviesStore.Export(ctx, getAllParams, func(entity movie.ExportEntity) error {
+		if entity.Locale == "" {
+			entity.Locale = locales.Default()
+		}
+		return fn(entity)
+	})
+}
+
+func (s *serviceImpl) ExportStars(ctx context.Context, fn func(star.ExportEntity) error) error {
+	return s.starsStore.Export(ctx, fn)
+}
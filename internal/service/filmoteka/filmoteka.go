@@ -1,28 +1,46 @@
 package filmoteka
 
 import (
+	image "vk-test-task/internal/store/image"
 	movie "vk-test-task/internal/store/movie"
 	star "vk-test-task/internal/store/star"
+	"vk-test-task/pkg/storage"
 )
 
 type (
 	Service interface {
 		starsService
 		moviesService
+		batchService
+		exportService
+		imagesService
+		translationsService
+		aliasesService
 	}
 
 	serviceImpl struct {
 		starsStore  star.Store
 		moviesStore movie.Store
+		imagesStore image.Store
+		// storage keeps the files of the images and their thumbnails
+		storage storage.Storage
+		// maxImagePixels limits the images decoded for thumbnails
+		maxImagePixels int
 	}
 )
 
 func New(
 	stars star.Store,
 	movies movie.Store,
+	images image.Store,
+	files storage.Storage,
+	maxImagePixels int,
 ) Service {
 	return &serviceImpl{
-		starsStore:  stars,
-		moviesStore: movies,
+		starsStore:     stars,
+		moviesStore:    movies,
+		imagesStore:    images,
+		storage:        files,
+		maxImagePixels: maxImagePixels,
 	}
 }
@@ -0,0 +1,149 @@
+package filmoteka
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/store/image"
+	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/storage"
+	"vk-test-task/pkg/thumbnail"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	imagesService interface {
+		UploadMovieImage(context.Context, int, UploadMovieImageModel) (image.Entity, error)
+		UploadStarImage(context.Context, int, UploadStarImageModel) (image.Entity, error)
+		GetImagesByMovieIDs(context.Context, []int) (map[int][]image.Entity, error)
+		GetImagesByStarIDs(context.Context, []int) (map[int][]image.Entity, error)
+		GetImageFile(context.Context, string) (storage.Object, error)
+	}
+
+	UploadMovieImageModel struct {
+		Kind string `json:"kind" validate:"required,oneof=poster still"`
+		Data []byte `json:"-"`
+	}
+
+	UploadStarImageModel struct {
+		Kind string `json:"kind" validate:"required,oneof=headshot still"`
+		Data []byte `json:"-"`
+	}
+)
+
+func (s *serviceImpl) UploadMovieImage(ctx context.Context, movieID int, model UploadMovieImageModel) (image.Entity, error) {
+	if _, err := s.moviesStore.GetByID(ctx, movieID); err != nil {
+		return image.Entity{}, err
+	}
+
+	return s.uploadImage(ctx, image.CreateEntity{MovieID: &movieID, Kind: model.Kind}, model.Data)
+}
+
+func (s *serviceImpl) UploadStarImage(ctx context.Context, starID int, model UploadStarImageModel) (image.Entity, error) {
+	exists, err := s.starsStore.CheckExistence(ctx, starID)
+	if err != nil {
+		return image.Entity{}, err
+	}
+	if !exists {
+		return image.Entity{}, pgx.ErrNoRows
+	}
+
+	return s.uploadImage(ctx, image.CreateEntity{StarID: &starID, Kind: model.Kind}, model.Data)
+}
+
+func (s *serviceImpl) GetImagesByMovieIDs(ctx context.Context, moviesID []int) (map[int][]image.Entity, error) {
+	return s.imagesStore.GetByMovieIDs(ctx, moviesID)
+}
+
+func (s *serviceImpl) GetImagesByStarIDs(ctx context.Context, starsID []int) (map[int][]image.Entity, error) {
+	return s.imagesStore.GetByStarIDs(ctx, starsID)
+}
+
+func (s *serviceImpl) GetImageFile(ctx context.Context, key string) (storage.Object, error) {
+	return s.storage.Get(ctx, key)
+}
+
+// uploadImage checks data to be an image, stores it along with its thumbnails and
+// records it. Stored files are removed if the image is not recorded.
+func (s *serviceImpl) uploadImage(ctx context.Context, entity image.CreateEntity, data []byte) (image.Entity, error) {
+	img, err := thumbnail.Decode(data, s.maxImagePixels)
+	if err != nil {
+		// broken images are not supported either
+		return image.Entity{}, fmt.Errorf("%w: %s", core.ErrUnsupportedImage, err.Error())
+	}
+
+	name, err := imageName()
+	if err != nil {
+		return image.Entity{}, err
+	}
+
+	entity.Key = name + thumbnail.Extension(img.ContentType)
+	entity.ContentType = img.ContentType
+	entity.Width = img.Bounds().Dx()
+	entity.Height = img.Bounds().Dy()
+	entity.Thumbnails = make(map[string]string, len(thumbnail.Sizes))
+
+	var stored []string
+	defer func() {
+		if err != nil {
+			s.deleteImageFiles(ctx, stored)
+		}
+	}()
+
+	if err = s.storage.Put(ctx, entity.Key, bytes.NewReader(data), int64(len(data)), img.ContentType); err != nil {
+		return image.Entity{}, err
+	}
+	stored = append(stored, entity.Key)
+
+	for _, size := range thumbnail.Sizes {
+		var (
+			thumb       []byte
+			contentType string
+		)
+		thumb, contentType, err = thumbnail.Encode(thumbnail.Make(img, size), img.ContentType)
+		if err != nil {
+			return image.Entity{}, err
+		}
+
+		key := name + "_" + size.Name + thumbnail.Extension(contentType)
+		if err = s.storage.Put(ctx, key, bytes.NewReader(thumb), int64(len(thumb)), contentType); err != nil {
+			return image.Entity{}, err
+		}
+		stored = append(stored, key)
+		entity.Thumbnails[size.Name] = key
+	}
+
+	var created image.Entity
+	created, err = s.imagesStore.Create(ctx, entity)
+	if err != nil {
+		return image.Entity{}, err
+	}
+
+	return created, nil
+}
+
+func (s *serviceImpl) deleteImageFiles(ctx context.Context, keys []string) {
+	// the files are removed even if the request is gone
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			logger.Log.ErrorContext(ctx, "delete image file",
+				"key", key,
+				"error", err.Error())
+		}
+	}
+}
+
+func imageName() (string, error) {
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(name), nil
+}
@@ -17,6 +17,7 @@ type (
 		CreateMovie(context.Context, CreateMovieModel) (movie.Entity, error)
 		UpdateMovie(context.Context, int, UpdateMovieModel) (movie.Entity, error)
 		DeleteMovie(context.Context, int) error
+		GetMoviesByStarIDs(context.Context, []int) (map[int][]movie.Entity, error)
 	}
 
 	GetMoviesModel struct {
@@ -50,6 +51,10 @@ func (s *serviceImpl) GetMovies(ctx context.Context, model GetMoviesModel) ([]mo
 		return nil, 0, err
 	}
 
+	if err := s.localizeMovies(ctx, data.Movies); err != nil {
+		return nil, 0, err
+	}
+
 	return data.Movies, data.TotalCount, nil
 }
 
@@ -59,7 +64,12 @@ func (s *serviceImpl) GetMovieByID(ctx context.Context, id int) (movie.Entity, e
 		return movie.Entity{}, err
 	}
 
-	return data, nil
+	movies := []movie.Entity{data}
+	if err := s.localizeMovies(ctx, movies); err != nil {
+		return movie.Entity{}, err
+	}
+
+	return movies[0], nil
 }
 
 func (s *serviceImpl) CreateMovie(ctx context.Context, model CreateMovieModel) (movie.Entity, error) {
@@ -103,6 +113,23 @@ func (s *serviceImpl) DeleteMovie(ctx context.Context, id int) error {
 	return s.moviesStore.Delete(ctx, id)
 }
 
+func (s *serviceImpl) GetMoviesByStarIDs(ctx context.Context, starsID []int) (map[int][]movie.Entity, error) {
+	movies, err := s.moviesStore.GetByStarIDs(ctx, starsID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([][]movie.Entity, 0, len(movies))
+	for _, starMovies := range movies {
+		groups = append(groups, starMovies)
+	}
+	if err := s.localizeMovies(ctx, groups...); err != nil {
+		return nil, err
+	}
+
+	return movies, nil
+}
+
 func (m GetMoviesModel) toGetAllParams() movie.GetAllParams {
 	sortBy, sortOrder := "", ""
 	if m.Sort != "" {
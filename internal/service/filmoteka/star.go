@@ -16,10 +16,12 @@ type (
 		CreateStar(context.Context, CreateStarModel) (star.Entity, error)
 		UpdateStar(context.Context, int, UpdateStarModel) (star.Entity, []movie.Entity, error)
 		DeleteStar(context.Context, int) error
+		GetStarsByMovieIDs(context.Context, []int) (map[int][]star.Entity, error)
 	}
 
 	GetStarsModel struct {
 		web.PaginationQuery
+		SearchTerm string `query:"q" validate:"omitempty,max=100"`
 	}
 
 	CreateStarModel struct {
@@ -57,6 +59,10 @@ func (s *serviceImpl) GetStarByID(ctx context.Context, id int) (star.Entity, []m
 		return star.Entity{}, []movie.Entity{}, err
 	}
 
+	if err := s.localizeMovies(ctx, moviesData); err != nil {
+		return star.Entity{}, []movie.Entity{}, err
+	}
+
 	return starData, moviesData, nil
 }
 
@@ -90,6 +96,10 @@ func (s *serviceImpl) UpdateStar(ctx context.Context, id int, model UpdateStarMo
 		return star.Entity{}, []movie.Entity{}, err
 	}
 
+	if err := s.localizeMovies(ctx, moviesData); err != nil {
+		return star.Entity{}, []movie.Entity{}, err
+	}
+
 	return data, moviesData, nil
 }
 
@@ -97,10 +107,15 @@ func (s *serviceImpl) DeleteStar(ctx context.Context, id int) error {
 	return s.starsStore.Delete(ctx, id)
 }
 
+func (s *serviceImpl) GetStarsByMovieIDs(ctx context.Context, moviesID []int) (map[int][]star.Entity, error) {
+	return s.starsStore.GetByMovieIDs(ctx, moviesID)
+}
+
 func (m GetStarsModel) toGetAllParams() star.GetAllParams {
 	return star.GetAllParams{
-		Limit:  m.PaginationQuery.GetLimit(),
-		Offset: m.PaginationQuery.GetOffset(),
+		SearchTerm: m.SearchTerm,
+		Limit:      m.PaginationQuery.GetLimit(),
+		Offset:     m.PaginationQuery.GetOffset(),
 	}
 }
 
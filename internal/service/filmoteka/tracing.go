@@ -0,0 +1,225 @@
+package filmoteka
+
+import (
+	"context"
+
+	"vk-test-task/internal/store/image"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/internal/store/star"
+	"vk-test-task/pkg/storage"
+	"vk-test-task/pkg/tracing"
+)
+
+// tracedService starts a span for every call to the wrapped service, the store
+// queries made by the call become its children.
+type tracedService struct {
+	service Service
+}
+
+// WithTracing wraps the service to trace its methods.
+func WithTracing(service Service) Service {
+	return &tracedService{service: service}
+}
+
+func (s *tracedService) GetStars(ctx context.Context, model GetStarsModel) ([]star.Entity, int, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.GetStars")
+	stars, total, err := s.service.GetStars(ctx, model)
+	tracing.End(span, err)
+	return stars, total, err
+}
+
+func (s *tracedService) GetStarByID(ctx context.Context, id int) (star.Entity, []movie.Entity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.GetStarByID")
+	entity, movies, err := s.service.GetStarByID(ctx, id)
+	tracing.End(span, err)
+	return entity, movies, err
+}
+
+func (s *tracedService) CreateStar(ctx context.Context, model CreateStarModel) (star.Entity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.CreateStar")
+	entity, err := s.service.CreateStar(ctx, model)
+	tracing.End(span, err)
+	return entity, err
+}
+
+func (s *tracedService) UpdateStar(ctx context.Context, id int, model UpdateStarModel) (star.Entity, []movie.Entity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.UpdateStar")
+	entity, movies, err := s.service.UpdateStar(ctx, id, model)
+	tracing.End(span, err)
+	return entity, movies, err
+}
+
+func (s *tracedService) DeleteStar(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "filmoteka.DeleteStar")
+	err := s.service.DeleteStar(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) GetStarsByMovieIDs(ctx context.Context, moviesID []int) (map[int][]star.Entity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.GetStarsByMovieIDs")
+	stars, err := s.service.GetStarsByMovieIDs(ctx, moviesID)
+	tracing.End(span, err)
+	return stars, err
+}
+
+func (s *tracedService) GetMovies(ctx context.Context, model GetMoviesModel) ([]movie.Entity, int, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.GetMovies")
+	movies, total, err := s.service.GetMovies(ctx, model)
+	tracing.End(span, err)
+	return movies, total, err
+}
+
+func (s *tracedService) GetMovieByID(ctx context.Context, id int) (movie.Entity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.GetMovieByID")
+	entity, err := s.service.GetMovieByID(ctx, id)
+	tracing.End(span, err)
+	return entity, err
+}
+
+func (s *tracedService) CreateMovie(ctx context.Context, model CreateMovieModel) (movie.Entity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.CreateMovie")
+	entity, err := s.service.CreateMovie(ctx, model)
+	tracing.End(span, err)
+	return entity, err
+}
+
+func (s *tracedService) UpdateMovie(ctx context.Context, id int, model UpdateMovieModel) (movie.Entity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.UpdateMovie")
+	entity, err := s.service.UpdateMovie(ctx, id, model)
+	tracing.End(span, err)
+	return entity, err
+}
+
+func (s *tracedService) DeleteMovie(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "filmoteka.DeleteMovie")
+	err := s.service.DeleteMovie(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) GetMoviesByStarIDs(ctx context.Context, starsID []int) (map[int][]movie.Entity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.GetMoviesByStarIDs")
+	movies, err := s.service.GetMoviesByStarIDs(ctx, starsID)
+	tracing.End(span, err)
+	return movies, err
+}
+
+func (s *tracedService) BatchMovies(ctx context.Context, model BatchMoviesModel) ([]movie.BatchResult, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.BatchMovies")
+	results, err := s.service.BatchMovies(ctx, model)
+	tracing.End(span, err)
+	return results, err
+}
+
+func (s *tracedService) BatchStars(ctx context.Context, model BatchStarsModel) ([]star.BatchResult, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.BatchStars")
+	results, err := s.service.BatchStars(ctx, model)
+	tracing.End(span, err)
+	return results, err
+}
+
+func (s *tracedService) ExportMovies(ctx context.Context, model ExportMoviesModel, fn func(movie.ExportEntity) error) error {
+	ctx, span := tracing.Start(ctx, "filmoteka.ExportMovies")
+	err := s.service.ExportMovies(ctx, model, fn)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) ExportStars(ctx context.Context, fn func(star.ExportEntity) error) error {
+	ctx, span := tracing.Start(ctx, "filmoteka.ExportStars")
+	err := s.service.ExportStars(ctx, fn)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) UploadMovieImage(ctx context.Context, movieID int, model UploadMovieImageModel) (image.Entity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.UploadMovieImage")
+	entity, err := s.service.UploadMovieImage(ctx, movieID, model)
+	tracing.End(span, err)
+	return entity, err
+}
+
+func (s *tracedService) UploadStarImage(ctx context.Context, starID int, model UploadStarImageModel) (image.Entity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.UploadStarImage")
+	entity, err := s.service.UploadStarImage(ctx, starID, model)
+	tracing.End(span, err)
+	return entity, err
+}
+
+func (s *tracedService) GetImagesByMovieIDs(ctx context.Context, moviesID []int) (map[int][]image.Entity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.GetImagesByMovieIDs")
+	images, err := s.service.GetImagesByMovieIDs(ctx, moviesID)
+	tracing.End(span, err)
+	return images, err
+}
+
+func (s *tracedService) GetImagesByStarIDs(ctx context.Context, starsID []int) (map[int][]image.Entity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.GetImagesByStarIDs")
+	images, err := s.service.GetImagesByStarIDs(ctx, starsID)
+	tracing.End(span, err)
+	return images, err
+}
+
+func (s *tracedService) GetImageFile(ctx context.Context, key string) (storage.Object, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.GetImageFile")
+	object, err := s.service.GetImageFile(ctx, key)
+	tracing.End(span, err)
+	return object, err
+}
+
+func (s *tracedService) UpsertMovieTranslation(ctx context.Context, movieID int, locale string, model UpsertMovieTranslationModel) (movie.TranslationEntity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.UpsertMovieTranslation")
+	translation, err := s.service.UpsertMovieTranslation(ctx, movieID, locale, model)
+	tracing.End(span, err)
+	return translation, err
+}
+
+func (s *tracedService) DeleteMovieTranslation(ctx context.Context, movieID int, locale string) error {
+	ctx, span := tracing.Start(ctx, "filmoteka.DeleteMovieTranslation")
+	err := s.service.DeleteMovieTranslation(ctx, movieID, locale)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) GetMovieAliases(ctx context.Context, movieID int) ([]movie.AliasEntity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.GetMovieAliases")
+	aliases, err := s.service.GetMovieAliases(ctx, movieID)
+	tracing.End(span, err)
+	return aliases, err
+}
+
+func (s *tracedService) CreateMovieAlias(ctx context.Context, movieID int, model CreateMovieAliasModel) (movie.AliasEntity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.CreateMovieAlias")
+	alias, err := s.service.CreateMovieAlias(ctx, movieID, model)
+	tracing.End(span, err)
+	return alias, err
+}
+
+func (s *tracedService) DeleteMovieAlias(ctx context.Context, movieID, aliasID int) error {
+	ctx, span := tracing.Start(ctx, "filmoteka.DeleteMovieAlias")
+	err := s.service.DeleteMovieAlias(ctx, movieID, aliasID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedService) GetStarAliases(ctx context.Context, starID int) ([]star.AliasEntity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.GetStarAliases")
+	aliases, err := s.service.GetStarAliases(ctx, starID)
+	tracing.End(span, err)
+	return aliases, err
+}
+
+func (s *tracedService) CreateStarAlias(ctx context.Context, starID int, model CreateStarAliasModel) (star.AliasEntity, error) {
+	ctx, span := tracing.Start(ctx, "filmoteka.CreateStarAlias")
+	alias, err := s.service.CreateStarAlias(ctx, starID, model)
+	tracing.End(span, err)
+	return alias, err
+}
+
+func (s *tracedService) DeleteStarAlias(ctx context.Context, starID, aliasID int) error {
+	ctx, span := tracing.Start(ctx, "filmoteka.DeleteStarAlias")
+	err := s.service.DeleteStarAlias(ctx, starID, aliasID)
+	tracing.End(span, err)
+	return err
+}
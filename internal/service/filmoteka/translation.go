@@ -0,0 +1,79 @@
+package filmoteka
+
+import (
+	"context"
+
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/pkg/locale"
+)
+
+type (
+	translationsService interface {
+		UpsertMovieTranslation(context.Context, int, string, UpsertMovieTranslationModel) (movie.TranslationEntity, error)
+		DeleteMovieTranslation(context.Context, int, string) error
+	}
+
+	UpsertMovieTranslationModel struct {
+		Title       string `json:"title" validate:"required,min=1,max=150"`
+		Description string `json:"description" validate:"required,min=1,max=1000"`
+	}
+)
+
+func (s *serviceImpl) UpsertMovieTranslation(ctx context.Context, movieID int, locale string, model UpsertMovieTranslationModel) (movie.TranslationEntity, error) {
+	if _, err := s.moviesStore.GetByID(ctx, movieID); err != nil {
+		return movie.TranslationEntity{}, err
+	}
+
+	return s.moviesStore.UpsertTranslation(ctx, movie.TranslationEntity{
+		MovieID:     movieID,
+		Locale:      locale,
+		Title:       model.Title,
+		Description: model.Description,
+	})
+}
+
+func (s *serviceImpl) DeleteMovieTranslation(ctx context.Context, movieID int, locale string) error {
+	return s.moviesStore.DeleteTranslation(ctx, movieID, locale)
+}
+
+// localizeMovies puts the titles and the descriptions of the movies of every group into
+// the locales of ctx with a single query, movies not translated to them stay in the
+// default locale. Movies are left as they are if no locales were negotiated.
+func (s *serviceImpl) localizeMovies(ctx context.Context, groups ...[]movie.Entity) error {
+	locales := locale.FromContext(ctx)
+	if len(locales) == 0 {
+		return nil
+	}
+
+	var moviesID []int
+	seen := make(map[int]bool)
+	for _, movies := range groups {
+		for i := range movies {
+			movies[i].Locale = locales.Default()
+			if !seen[movies[i].ID] {
+				seen[movies[i].ID] = true
+				moviesID = append(moviesID, movies[i].ID)
+			}
+		}
+	}
+	if len(moviesID) == 0 || len(locales.Translated()) == 0 {
+		return nil
+	}
+
+	translations, err := s.moviesStore.GetTranslations(ctx, moviesID, locales.Translated())
+	if err != nil {
+		return err
+	}
+
+	for _, movies := range groups {
+		for i := range movies {
+			if translation, ok := translations[movies[i].ID]; ok {
+				movies[i].Title = translation.Title
+				movies[i].Description = translation.Description
+				movies[i].Locale = translation.Locale
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,388 @@
+package importer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// File names of the IMDb non-commercial datasets, see https://developer.imdb.com/non-commercial-datasets/
+const (
+	IMDbTitleBasics     = "title.basics.tsv.gz"
+	IMDbTitleRatings    = "title.ratings.tsv.gz"
+	IMDbTitlePrincipals = "title.principals.tsv.gz"
+	IMDbNameBasics      = "name.basics.tsv.gz"
+
+	imdbNull = `\N`
+
+	// ctxCheckInterval is the number of dataset rows read between context checks.
+	ctxCheckInterval = 4096
+)
+
+type (
+	IMDbFiles struct {
+		TitleBasics     string
+		TitleRatings    string
+		TitlePrincipals string
+		NameBasics      string
+	}
+
+	IMDbOptions struct {
+		Options
+		// TitleTypes are the imported values of titleType, e.g. movie or tvMovie.
+		TitleTypes []string
+		// MinVotes skips titles rated by fewer users.
+		MinVotes int
+	}
+
+	// imdbDataset holds the titles and credits selected from the IMDb files
+	// and the sex of every credited person, which IMDb only reports as the
+	// actor or actress category of a credit.
+	imdbDataset struct {
+		movies  []record
+		credits []record
+		sex     map[string]string
+	}
+
+	// tsvReader reads the IMDb flavour of TSV: a header row, tab separated
+	// values without quoting and \N for missing values.
+	tsvReader struct {
+		scanner *bufio.Scanner
+		closers []io.Closer
+		header  []string
+		line    int
+	}
+
+	// imdbStarsReader reads name.basics as a stars file, keeping only credited people.
+	imdbStarsReader struct {
+		names *tsvReader
+		sex   map[string]string
+	}
+
+	sliceReader struct {
+		records []record
+	}
+)
+
+var imdbTitleTypes = map[string]string{
+	"movie":        "Movie",
+	"tvMovie":      "TV movie",
+	"short":        "Short",
+	"video":        "Video",
+	"tvSeries":     "TV series",
+	"tvMiniSeries": "TV mini series",
+	"tvSpecial":    "TV special",
+}
+
+// IMDbDir returns the dataset files with their original names in dir.
+func IMDbDir(dir string) IMDbFiles {
+	return IMDbFiles{
+		TitleBasics:     filepath.Join(dir, IMDbTitleBasics),
+		TitleRatings:    filepath.Join(dir, IMDbTitleRatings),
+		TitlePrincipals: filepath.Join(dir, IMDbTitlePrincipals),
+		NameBasics:      filepath.Join(dir, IMDbNameBasics),
+	}
+}
+
+// ImportIMDb imports rated titles of the selected types with their actors and actresses.
+// IMDb tconst and nconst identifiers become external ids, so the import can be rerun
+// on a newer dataset to add titles and people that were not imported before.
+func (s *serviceImpl) ImportIMDb(ctx context.Context, files IMDbFiles, opts IMDbOptions) (Report, error) {
+	dataset, err := loadIMDb(ctx, files, opts)
+	if err != nil {
+		return Report{}, err
+	}
+
+	names, err := openTSV(files.NameBasics)
+	if err != nil {
+		return Report{}, err
+	}
+	defer names.Close()
+
+	return s.run(ctx, sources{
+		stars:   &imdbStarsReader{names: names, sex: dataset.sex},
+		credits: &sliceReader{records: dataset.credits},
+		movies:  &sliceReader{records: dataset.movies},
+	}, opts.Options)
+}
+
+// loadIMDb selects titles by ratings and basics and reads their credits.
+// Only the selected rows are kept in memory, the rest of the files is streamed.
+func loadIMDb(ctx context.Context, files IMDbFiles, opts IMDbOptions) (imdbDataset, error) {
+	ratings := map[string]int{}
+	err := forEachTSV(ctx, files.TitleRatings, func(rec record) error {
+		votes, err := strconv.Atoi(rec.fields["numVotes"])
+		if err != nil || votes < opts.MinVotes {
+			return nil
+		}
+
+		average, err := strconv.ParseFloat(rec.fields["averageRating"], 64)
+		if err != nil {
+			return nil
+		}
+
+		// IMDb ratings are 1.0-10.0, movies are rated with integers from 1 to 10
+		ratings[rec.fields["tconst"]] = int(math.Max(1, math.Min(10, math.Round(average))))
+		return nil
+	})
+	if err != nil {
+		return imdbDataset{}, fmt.Errorf("read %s: %w", files.TitleRatings, err)
+	}
+
+	titleTypes := map[string]struct{}{}
+	for _, titleType := range opts.TitleTypes {
+		titleTypes[titleType] = struct{}{}
+	}
+
+	var dataset imdbDataset
+	selected := map[string]struct{}{}
+	err = forEachTSV(ctx, files.TitleBasics, func(rec record) error {
+		tconst := rec.fields["tconst"]
+
+		if _, ok := titleTypes[rec.fields["titleType"]]; !ok || rec.fields["isAdult"] == "1" {
+			return nil
+		}
+		rating, ok := ratings[tconst]
+		if !ok {
+			return nil
+		}
+
+		var releaseDate string
+		if year := rec.fields["startYear"]; year != "" {
+			releaseDate = year + "-01-01T00:00:00Z"
+		}
+
+		selected[tconst] = struct{}{}
+		dataset.movies = append(dataset.movies, record{
+			line: rec.line,
+			fields: map[string]string{
+				"external_id":  tconst,
+				"title":        rec.fields["primaryTitle"],
+				"description":  describeTitle(rec.fields),
+				"release_date": releaseDate,
+				"rating":       strconv.Itoa(rating),
+			},
+		})
+		return nil
+	})
+	if err != nil {
+		return imdbDataset{}, fmt.Errorf("read %s: %w", files.TitleBasics, err)
+	}
+
+	dataset.sex = map[string]string{}
+	err = forEachTSV(ctx, files.TitlePrincipals, func(rec record) error {
+		var sex string
+		switch rec.fields["category"] {
+		case "actor":
+			sex = "male"
+		case "actress":
+			sex = "female"
+		default:
+			return nil
+		}
+
+		tconst := rec.fields["tconst"]
+		if _, ok := selected[tconst]; !ok {
+			return nil
+		}
+
+		nconst := rec.fields["nconst"]
+		if _, ok := dataset.sex[nconst]; !ok {
+			dataset.sex[nconst] = sex
+		}
+
+		dataset.credits = append(dataset.credits, record{
+			line: rec.line,
+			fields: map[string]string{
+				"movie_external_id": tconst,
+				"star_external_id":  nconst,
+			},
+		})
+		return nil
+	})
+	if err != nil {
+		return imdbDataset{}, fmt.Errorf("read %s: %w", files.TitlePrincipals, err)
+	}
+
+	return dataset, nil
+}
+
+// describeTitle builds a description from basics, which have no plot summary,
+// e.g. "Movie, 1994. Crime, Drama. 142 min."
+func describeTitle(fields map[string]string) string {
+	titleType, ok := imdbTitleTypes[fields["titleType"]]
+	if !ok {
+		titleType = fields["titleType"]
+	}
+
+	parts := []string{titleType}
+	if year := fields["startYear"]; year != "" {
+		parts[0] += ", " + year
+	}
+	if genres := fields["genres"]; genres != "" {
+		parts = append(parts, strings.ReplaceAll(genres, ",", ", "))
+	}
+	if runtime := fields["runtimeMinutes"]; runtime != "" {
+		parts = append(parts, runtime+" min")
+	}
+
+	return strings.Join(parts, ". ") + "."
+}
+
+func forEachTSV(ctx context.Context, path string, handle func(record) error) error {
+	reader, err := openTSV(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for {
+		if reader.line%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		rec, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		// Rows that cannot be parsed are left out of the selection
+		var malformed *malformedError
+		if errors.As(err, &malformed) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := handle(rec); err != nil {
+			return err
+		}
+	}
+}
+
+// openTSV opens a TSV file, decompressing it when the extension is .gz.
+func openTSV(path string) (*tsvReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := &tsvReader{closers: []io.Closer{file}}
+
+	var src io.Reader = file
+	if strings.EqualFold(filepath.Ext(path), ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("open %s: %w", path, err)
+		}
+		reader.closers = append(reader.closers, gz)
+		src = gz
+	}
+
+	reader.scanner = bufio.NewScanner(src)
+	reader.scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	if !reader.scanner.Scan() {
+		reader.Close()
+		if err := reader.scanner.Err(); err != nil {
+			return nil, fmt.Errorf("read header of %s: %w", path, err)
+		}
+		return nil, fmt.Errorf("read header of %s: %w", path, io.ErrUnexpectedEOF)
+	}
+	reader.line++
+
+	reader.header = strings.Split(reader.scanner.Text(), "\t")
+
+	return reader, nil
+}
+
+func (r *tsvReader) Read() (record, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return record{}, err
+		}
+		return record{}, io.EOF
+	}
+	r.line++
+
+	values := strings.Split(r.scanner.Text(), "\t")
+	if len(values) != len(r.header) {
+		return record{}, &malformedError{
+			line: r.line,
+			err:  fmt.Errorf("expected %d columns, got %d", len(r.header), len(values)),
+		}
+	}
+
+	fields := make(map[string]string, len(r.header))
+	for i, name := range r.header {
+		if values[i] != imdbNull {
+			fields[name] = values[i]
+		}
+	}
+
+	return record{line: r.line, fields: fields}, nil
+}
+
+func (r *tsvReader) Close() error {
+	var err error
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		if closeErr := r.closers[i].Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+func (r *imdbStarsReader) Read() (record, error) {
+	for {
+		rec, err := r.names.Read()
+		if err != nil {
+			return record{}, err
+		}
+
+		nconst := rec.fields["nconst"]
+		sex, ok := r.sex[nconst]
+		if !ok {
+			continue
+		}
+
+		var birthDate string
+		if year := rec.fields["birthYear"]; year != "" {
+			birthDate = year + "-01-01T00:00:00Z"
+		}
+
+		return record{
+			line: rec.line,
+			fields: map[string]string{
+				"external_id": nconst,
+				"name":        rec.fields["primaryName"],
+				"sex":         sex,
+				"birth_date":  birthDate,
+			},
+		}, nil
+	}
+}
+
+func (r *sliceReader) Read() (record, error) {
+	if len(r.records) == 0 {
+		return record{}, io.EOF
+	}
+
+	rec := r.records[0]
+	r.records = r.records[1:]
+
+	return rec, nil
+}
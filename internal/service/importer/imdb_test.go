@@ -0,0 +1,190 @@
+package importer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/internal/store/star"
+)
+
+type (
+	// memoryStars and memoryMovies keep created entities by external id,
+	// which is all the importer needs from the stores.
+	memoryStars struct {
+		star.Store
+		ids map[string]int
+	}
+
+	memoryMovies struct {
+		movie.Store
+		ids   map[string]int
+		stars map[string][]int
+	}
+)
+
+func (s *memoryStars) GetIDsByExternalIDs(_ context.Context, externalIDs []string) (map[string]int, error) {
+	return lookupExternalIDs(s.ids, externalIDs), nil
+}
+
+func (s *memoryStars) Batch(_ context.Context, ops []star.BatchOperation, _ bool) ([]star.BatchResult, error) {
+	results := make([]star.BatchResult, len(ops))
+	for i, op := range ops {
+		id := len(s.ids) + 1
+		s.ids[*op.Create.ExternalID] = id
+		results[i].Entity = star.Entity{ID: id, Name: op.Create.Name, Sex: op.Create.Sex, BirthDate: op.Create.BirthDate}
+	}
+
+	return results, nil
+}
+
+func (s *memoryMovies) GetIDsByExternalIDs(_ context.Context, externalIDs []string) (map[string]int, error) {
+	return lookupExternalIDs(s.ids, externalIDs), nil
+}
+
+func (s *memoryMovies) Batch(_ context.Context, ops []movie.BatchOperation, _ bool) ([]movie.BatchResult, error) {
+	results := make([]movie.BatchResult, len(ops))
+	for i, op := range ops {
+		id := len(s.ids) + 1
+		s.ids[*op.Create.ExternalID] = id
+		s.stars[*op.Create.ExternalID] = op.Create.StarsID
+		results[i].Entity = movie.Entity{ID: id, Title: op.Create.Title}
+	}
+
+	return results, nil
+}
+
+func lookupExternalIDs(ids map[string]int, externalIDs []string) map[string]int {
+	found := map[string]int{}
+	for _, externalID := range externalIDs {
+		if id, ok := ids[externalID]; ok {
+			found[externalID] = id
+		}
+	}
+
+	return found
+}
+
+func TestLoadIMDb(t *testing.T) {
+	dataset, err := loadIMDb(context.Background(), IMDbDir("testdata/imdb"), IMDbOptions{
+		TitleTypes: []string{"movie"},
+		MinVotes:   1000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	wantMovies := []map[string]string{
+		{
+			"external_id":  "tt0000001",
+			"title":        "The Shawshank Redemption",
+			"description":  "Movie, 1994. Drama. 142 min.",
+			"release_date": "1994-01-01T00:00:00Z",
+			"rating":       "9",
+		},
+		{
+			"external_id":  "tt0000002",
+			"title":        "Pulp Fiction",
+			"description":  "Movie, 1994. Crime, Drama. 154 min.",
+			"release_date": "1994-01-01T00:00:00Z",
+			"rating":       "9",
+		},
+		{
+			"external_id":  "tt0000006",
+			"title":        "Undated Movie",
+			"description":  "Movie. 90 min.",
+			"release_date": "",
+			"rating":       "7",
+		},
+	}
+	if got := fieldsOf(dataset.movies); !reflect.DeepEqual(got, wantMovies) {
+		t.Errorf("expected movies %v, got %v", wantMovies, got)
+	}
+
+	wantSex := map[string]string{
+		"nm0000001": "male",
+		"nm0000002": "male",
+		"nm0000003": "male",
+		"nm0000004": "female",
+	}
+	if !reflect.DeepEqual(dataset.sex, wantSex) {
+		t.Errorf("expected sex %v, got %v", wantSex, dataset.sex)
+	}
+
+	if len(dataset.credits) != 6 {
+		t.Errorf("expected 6 credits, got %d", len(dataset.credits))
+	}
+}
+
+func TestImportIMDb(t *testing.T) {
+	stars := &memoryStars{ids: map[string]int{}}
+	movies := &memoryMovies{ids: map[string]int{}, stars: map[string][]int{}}
+	service := New(stars, movies)
+
+	opts := IMDbOptions{
+		Options:    Options{ChunkSize: 2},
+		TitleTypes: []string{"movie"},
+		MinVotes:   1000,
+	}
+
+	report, err := service.ImportIMDb(context.Background(), IMDbDir("testdata/imdb"), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	want := Report{
+		Stars:   Counts{Read: 4, Imported: 3, Rejected: 1},
+		Movies:  Counts{Read: 3, Imported: 2, Rejected: 1},
+		Credits: Counts{Read: 6, Imported: 4, Skipped: 1, Rejected: 1},
+	}
+	if !reflect.DeepEqual(countsOf(report), want) {
+		t.Errorf("expected counts %+v, got %+v", want, countsOf(report))
+	}
+
+	wantRejected := map[string]string{
+		"nm0000004": core.ValidationCode,
+		"tt0000006": core.ValidationCode,
+		"tt0000002": core.StarNotFoundCode,
+	}
+	for _, row := range report.Rejected {
+		if wantRejected[row.ExternalID] != row.MsgCode {
+			t.Errorf("unexpected rejected row %+v", row)
+		}
+	}
+
+	wantStars := []int{stars.ids["nm0000003"], stars.ids["nm0000002"]}
+	if !reflect.DeepEqual(movies.stars["tt0000002"], wantStars) {
+		t.Errorf("expected stars %v, got %v", wantStars, movies.stars["tt0000002"])
+	}
+
+	// A rerun skips everything imported before
+	report, err = service.ImportIMDb(context.Background(), IMDbDir("testdata/imdb"), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	want = Report{
+		Stars:   Counts{Read: 4, Skipped: 3, Rejected: 1},
+		Movies:  Counts{Read: 3, Skipped: 2, Rejected: 1},
+		Credits: Counts{Read: 6, Skipped: 5, Rejected: 1},
+	}
+	if !reflect.DeepEqual(countsOf(report), want) {
+		t.Errorf("expected counts %+v, got %+v", want, countsOf(report))
+	}
+}
+
+func fieldsOf(records []record) []map[string]string {
+	fields := make([]map[string]string, len(records))
+	for i, rec := range records {
+		fields[i] = rec.fields
+	}
+
+	return fields
+}
+
+func countsOf(report Report) Report {
+	report.Rejected = nil
+	return report
+}
@@ -0,0 +1,594 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/internal/store/star"
+	"vk-test-task/pkg/web"
+	"vk-test-task/pkg/webutil"
+)
+
+const (
+	StarsFile   = "stars"
+	MoviesFile  = "movies"
+	CreditsFile = "credits"
+
+	defaultChunkSize = 500
+)
+
+type (
+	Service interface {
+		Import(context.Context, Files, Options) (Report, error)
+		ImportIMDb(context.Context, IMDbFiles, IMDbOptions) (Report, error)
+	}
+
+	Files struct {
+		Movies  string
+		Stars   string
+		Credits string
+	}
+
+	Options struct {
+		DryRun    bool
+		ChunkSize int
+	}
+
+	Report struct {
+		Stars    Counts
+		Movies   Counts
+		Credits  Counts
+		Rejected []RejectedRow
+	}
+
+	Counts struct {
+		Read     int
+		Imported int
+		Skipped  int
+		Rejected int
+	}
+
+	RejectedRow struct {
+		File       string
+		Line       int
+		ExternalID string
+		MsgCode    string
+		Errors     []web.ValidationError
+	}
+
+	serviceImpl struct {
+		starsStore  star.Store
+		moviesStore movie.Store
+	}
+
+	sources struct {
+		stars   recordReader
+		credits recordReader
+		movies  recordReader
+	}
+
+	// importRun holds the state of a single import: database ids of the stars
+	// known so far and the credits that are not yet attached to a movie.
+	importRun struct {
+		*serviceImpl
+		opts    Options
+		report  Report
+		starsID map[string]int
+		credits map[string][]credit
+	}
+
+	credit struct {
+		line           int
+		starExternalID string
+	}
+
+	pendingStar struct {
+		line       int
+		externalID string
+		entity     star.CreateEntity
+	}
+
+	pendingMovie struct {
+		line       int
+		externalID string
+		entity     movie.CreateEntity
+	}
+)
+
+func New(
+	stars star.Store,
+	movies movie.Store,
+) Service {
+	return &serviceImpl{
+		starsStore:  stars,
+		moviesStore: movies,
+	}
+}
+
+// Import loads stars, then credits, then movies. Rows are written in chunks,
+// each in its own transaction, and rows whose external id is already stored are
+// skipped, so an interrupted import can be resumed by running it again.
+func (s *serviceImpl) Import(ctx context.Context, files Files, opts Options) (Report, error) {
+	var src sources
+
+	for _, file := range []struct {
+		path   string
+		reader *recordReader
+	}{
+		{files.Stars, &src.stars},
+		{files.Credits, &src.credits},
+		{files.Movies, &src.movies},
+	} {
+		if file.path == "" {
+			continue
+		}
+
+		reader, closer, err := openRecords(file.path)
+		if err != nil {
+			return Report{}, err
+		}
+		defer closer.Close()
+
+		*file.reader = reader
+	}
+
+	return s.run(ctx, src, opts)
+}
+
+func (s *serviceImpl) run(ctx context.Context, src sources, opts Options) (Report, error) {
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = defaultChunkSize
+	}
+
+	run := &importRun{
+		serviceImpl: s,
+		opts:        opts,
+		starsID:     map[string]int{},
+		credits:     map[string][]credit{},
+	}
+
+	if src.stars != nil {
+		if err := run.importStars(ctx, src.stars); err != nil {
+			return run.report, fmt.Errorf("import stars: %w", err)
+		}
+	}
+
+	if src.credits != nil {
+		if err := run.readCredits(ctx, src.credits); err != nil {
+			return run.report, fmt.Errorf("read credits: %w", err)
+		}
+	}
+
+	if src.movies != nil {
+		if err := run.importMovies(ctx, src.movies); err != nil {
+			return run.report, fmt.Errorf("import movies: %w", err)
+		}
+	}
+
+	if err := run.finishCredits(ctx); err != nil {
+		return run.report, fmt.Errorf("check credits: %w", err)
+	}
+
+	return run.report, nil
+}
+
+func (r *importRun) importStars(ctx context.Context, reader recordReader) error {
+	counts := &r.report.Stars
+	seen := map[string]struct{}{}
+
+	var chunk []pendingStar
+	err := r.forEachRecord(reader, StarsFile, counts, func(rec record) error {
+		externalID, ok := r.checkExternalID(rec, StarsFile, counts, seen)
+		if !ok {
+			return nil
+		}
+
+		model := filmoteka.CreateStarModel{
+			Name:      rec.fields["name"],
+			Sex:       rec.fields["sex"],
+			BirthDate: rec.fields["birth_date"],
+		}
+
+		verrors, err := webutil.StructErrors(model)
+		if err != nil {
+			return err
+		}
+		if len(verrors) != 0 {
+			r.reject(StarsFile, counts, rec.line, externalID, core.ValidationCode, verrors)
+			return nil
+		}
+
+		birthDate, err := time.Parse(time.RFC3339, model.BirthDate)
+		if err != nil {
+			return err
+		}
+
+		chunk = append(chunk, pendingStar{
+			line:       rec.line,
+			externalID: externalID,
+			entity: star.CreateEntity{
+				ExternalID: &externalID,
+				Name:       model.Name,
+				Sex:        model.Sex,
+				BirthDate:  birthDate,
+			},
+		})
+		if len(chunk) < r.opts.ChunkSize {
+			return nil
+		}
+
+		err = r.flushStars(ctx, chunk)
+		chunk = nil
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.flushStars(ctx, chunk)
+}
+
+func (r *importRun) flushStars(ctx context.Context, chunk []pendingStar) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	counts := &r.report.Stars
+
+	externalIDs := make([]string, len(chunk))
+	for i, p := range chunk {
+		externalIDs[i] = p.externalID
+	}
+
+	existing, err := r.starsStore.GetIDsByExternalIDs(ctx, externalIDs)
+	if err != nil {
+		return err
+	}
+
+	var (
+		ops     []star.BatchOperation
+		pending []pendingStar
+	)
+	for _, p := range chunk {
+		if id, ok := existing[p.externalID]; ok {
+			r.starsID[p.externalID] = id
+			counts.Skipped++
+			continue
+		}
+		if r.opts.DryRun {
+			r.starsID[p.externalID] = 0
+			counts.Imported++
+			continue
+		}
+
+		ops = append(ops, star.BatchOperation{Op: core.BatchCreateOp, Create: p.entity})
+		pending = append(pending, p)
+	}
+
+	if len(ops) == 0 {
+		return nil
+	}
+
+	results, err := r.starsStore.Batch(ctx, ops, false)
+	if err != nil {
+		return err
+	}
+
+	for i, result := range results {
+		p := pending[i]
+		if result.Err != nil {
+			r.reject(StarsFile, counts, p.line, p.externalID, core.InternalErrorCode, nil)
+			continue
+		}
+
+		r.starsID[p.externalID] = result.Entity.ID
+		counts.Imported++
+	}
+
+	return nil
+}
+
+func (r *importRun) readCredits(ctx context.Context, reader recordReader) error {
+	counts := &r.report.Credits
+
+	err := r.forEachRecord(reader, CreditsFile, counts, func(rec record) error {
+		movieExternalID := rec.fields["movie_external_id"]
+		starExternalID := rec.fields["star_external_id"]
+		if movieExternalID == "" || starExternalID == "" {
+			r.reject(CreditsFile, counts, rec.line, movieExternalID, core.ExternalIDRequiredCode, nil)
+			return nil
+		}
+
+		r.credits[movieExternalID] = append(r.credits[movieExternalID], credit{
+			line:           rec.line,
+			starExternalID: starExternalID,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Credited stars that are not in the stars file may already be stored.
+	var unknown []string
+	seen := map[string]struct{}{}
+	for _, credits := range r.credits {
+		for _, c := range credits {
+			if _, ok := r.starsID[c.starExternalID]; ok {
+				continue
+			}
+			if _, ok := seen[c.starExternalID]; ok {
+				continue
+			}
+			seen[c.starExternalID] = struct{}{}
+			unknown = append(unknown, c.starExternalID)
+		}
+	}
+
+	for start := 0; start < len(unknown); start += r.opts.ChunkSize {
+		end := min(start+r.opts.ChunkSize, len(unknown))
+
+		existing, err := r.starsStore.GetIDsByExternalIDs(ctx, unknown[start:end])
+		if err != nil {
+			return err
+		}
+		for externalID, id := range existing {
+			r.starsID[externalID] = id
+		}
+	}
+
+	return nil
+}
+
+func (r *importRun) importMovies(ctx context.Context, reader recordReader) error {
+	counts := &r.report.Movies
+	seen := map[string]struct{}{}
+
+	var chunk []pendingMovie
+	err := r.forEachRecord(reader, MoviesFile, counts, func(rec record) error {
+		externalID, ok := r.checkExternalID(rec, MoviesFile, counts, seen)
+		if !ok {
+			return nil
+		}
+
+		var rating int
+		if val := rec.fields["rating"]; val != "" {
+			num, err := strconv.Atoi(val)
+			if err != nil {
+				r.reject(MoviesFile, counts, rec.line, externalID, core.InvalidBodyCode, nil)
+				return nil
+			}
+			rating = num
+		}
+
+		model := filmoteka.CreateMovieModel{
+			Title:       rec.fields["title"],
+			Description: rec.fields["description"],
+			ReleaseDate: rec.fields["release_date"],
+			Rating:      rating,
+			StarsID:     r.takeCredits(externalID),
+		}
+
+		verrors, err := webutil.StructErrors(model)
+		if err != nil {
+			return err
+		}
+		if len(verrors) != 0 {
+			r.report.Credits.Skipped += len(model.StarsID)
+			r.reject(MoviesFile, counts, rec.line, externalID, core.ValidationCode, verrors)
+			return nil
+		}
+
+		releaseDate, err := time.Parse(time.RFC3339, model.ReleaseDate)
+		if err != nil {
+			return err
+		}
+
+		chunk = append(chunk, pendingMovie{
+			line:       rec.line,
+			externalID: externalID,
+			entity: movie.CreateEntity{
+				ExternalID:  &externalID,
+				Title:       model.Title,
+				Description: model.Description,
+				ReleaseDate: releaseDate,
+				Rating:      model.Rating,
+				StarsID:     model.StarsID,
+			},
+		})
+		if len(chunk) < r.opts.ChunkSize {
+			return nil
+		}
+
+		err = r.flushMovies(ctx, chunk)
+		chunk = nil
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.flushMovies(ctx, chunk)
+}
+
+func (r *importRun) flushMovies(ctx context.Context, chunk []pendingMovie) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	counts := &r.report.Movies
+
+	externalIDs := make([]string, len(chunk))
+	for i, p := range chunk {
+		externalIDs[i] = p.externalID
+	}
+
+	existing, err := r.moviesStore.GetIDsByExternalIDs(ctx, externalIDs)
+	if err != nil {
+		return err
+	}
+
+	var (
+		ops     []movie.BatchOperation
+		pending []pendingMovie
+	)
+	for _, p := range chunk {
+		if _, ok := existing[p.externalID]; ok {
+			counts.Skipped++
+			r.report.Credits.Skipped += len(p.entity.StarsID)
+			continue
+		}
+		if r.opts.DryRun {
+			counts.Imported++
+			r.report.Credits.Imported += len(p.entity.StarsID)
+			continue
+		}
+
+		ops = append(ops, movie.BatchOperation{Op: core.BatchCreateOp, Create: p.entity})
+		pending = append(pending, p)
+	}
+
+	if len(ops) == 0 {
+		return nil
+	}
+
+	results, err := r.moviesStore.Batch(ctx, ops, false)
+	if err != nil {
+		return err
+	}
+
+	for i, result := range results {
+		p := pending[i]
+		if result.Err != nil {
+			r.report.Credits.Skipped += len(p.entity.StarsID)
+			r.reject(MoviesFile, counts, p.line, p.externalID, core.InternalErrorCode, nil)
+			continue
+		}
+
+		counts.Imported++
+		r.report.Credits.Imported += len(p.entity.StarsID)
+	}
+
+	return nil
+}
+
+// takeCredits resolves the stars credited to a movie and forgets its credits.
+// Credits of unknown stars are rejected, repeated ones are skipped.
+func (r *importRun) takeCredits(movieExternalID string) []int {
+	credits, ok := r.credits[movieExternalID]
+	if !ok {
+		return nil
+	}
+	delete(r.credits, movieExternalID)
+
+	counts := &r.report.Credits
+
+	starsID := []int{}
+	seen := map[string]struct{}{}
+	for _, c := range credits {
+		id, ok := r.starsID[c.starExternalID]
+		if !ok {
+			r.reject(CreditsFile, counts, c.line, movieExternalID, core.StarNotFoundCode, nil)
+			continue
+		}
+		if _, ok := seen[c.starExternalID]; ok {
+			counts.Skipped++
+			continue
+		}
+		seen[c.starExternalID] = struct{}{}
+
+		starsID = append(starsID, id)
+	}
+
+	return starsID
+}
+
+// finishCredits accounts for credits of movies that were not in the movies file.
+// Credits of already stored movies are left as they are, the rest are rejected.
+func (r *importRun) finishCredits(ctx context.Context) error {
+	if len(r.credits) == 0 {
+		return nil
+	}
+
+	counts := &r.report.Credits
+
+	externalIDs := make([]string, 0, len(r.credits))
+	for externalID := range r.credits {
+		externalIDs = append(externalIDs, externalID)
+	}
+
+	existing, err := r.moviesStore.GetIDsByExternalIDs(ctx, externalIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, externalID := range externalIDs {
+		if _, ok := existing[externalID]; ok {
+			counts.Skipped += len(r.credits[externalID])
+			continue
+		}
+		for _, c := range r.credits[externalID] {
+			r.reject(CreditsFile, counts, c.line, externalID, core.MovieNotFoundCode, nil)
+		}
+	}
+
+	return nil
+}
+
+func (r *importRun) checkExternalID(rec record, file string, counts *Counts, seen map[string]struct{}) (string, bool) {
+	externalID := rec.fields["external_id"]
+	if externalID == "" {
+		r.reject(file, counts, rec.line, "", core.ExternalIDRequiredCode, nil)
+		return "", false
+	}
+
+	if _, ok := seen[externalID]; ok {
+		r.reject(file, counts, rec.line, externalID, core.DuplicateExternalIDCode, nil)
+		return "", false
+	}
+	seen[externalID] = struct{}{}
+
+	return externalID, true
+}
+
+func (r *importRun) forEachRecord(reader recordReader, file string, counts *Counts, handle func(record) error) error {
+	for {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		var malformed *malformedError
+		if errors.As(err, &malformed) {
+			counts.Read++
+			r.reject(file, counts, malformed.line, "", core.InvalidBodyCode, nil)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		counts.Read++
+		if err := handle(rec); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *importRun) reject(file string, counts *Counts, line int, externalID, msgCode string, errors []web.ValidationError) {
+	counts.Rejected++
+	r.report.Rejected = append(r.report.Rejected, RejectedRow{
+		File:       file,
+		Line:       line,
+		ExternalID: externalID,
+		MsgCode:    msgCode,
+		Errors:     errors,
+	})
+}
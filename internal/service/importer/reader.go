@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const maxLineSize = 1024 * 1024
+
+type (
+	// record is a single row of an import file keyed by column name.
+	record struct {
+		line   int
+		fields map[string]string
+	}
+
+	recordReader interface {
+		Read() (record, error)
+	}
+
+	csvReader struct {
+		reader *csv.Reader
+		header []string
+	}
+
+	jsonlReader struct {
+		scanner *bufio.Scanner
+		line    int
+	}
+
+	// malformedError marks a row that cannot be parsed. Reading may continue after it.
+	malformedError struct {
+		line int
+		err  error
+	}
+)
+
+func (e *malformedError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.line, e.err.Error())
+}
+
+func (e *malformedError) Unwrap() error {
+	return e.err
+}
+
+// openRecords opens a CSV file with a header row, or a JSON Lines file
+// when the extension is .jsonl or .ndjson.
+func openRecords(path string) (recordReader, io.Closer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson":
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		return &jsonlReader{scanner: scanner}, file, nil
+	default:
+		reader := csv.NewReader(file)
+		reader.TrimLeadingSpace = true
+		reader.FieldsPerRecord = -1
+
+		header, err := reader.Read()
+		if err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("read header of %s: %w", path, err)
+		}
+		for i := range header {
+			header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+		}
+
+		return &csvReader{reader: reader, header: header}, file, nil
+	}
+}
+
+func (r *csvReader) Read() (record, error) {
+	values, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return record{}, &malformedError{line: parseErr.Line, err: parseErr.Err}
+		}
+		return record{}, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	fields := make(map[string]string, len(r.header))
+	for i, name := range r.header {
+		if i < len(values) {
+			fields[name] = strings.TrimSpace(values[i])
+		}
+	}
+
+	return record{line: line, fields: fields}, nil
+}
+
+func (r *jsonlReader) Read() (record, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		data := bytes.TrimSpace(r.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		var values map[string]any
+		if err := decoder.Decode(&values); err != nil {
+			return record{}, &malformedError{line: r.line, err: err}
+		}
+
+		fields := make(map[string]string, len(values))
+		for name, value := range values {
+			if value != nil {
+				fields[strings.ToLower(name)] = strings.TrimSpace(fmt.Sprint(value))
+			}
+		}
+
+		return record{line: r.line, fields: fields}, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return record{}, err
+	}
+
+	return record{}, io.EOF
+}
@@ -0,0 +1,102 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenRecords(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, test := range []struct {
+		Name      string
+		File      string
+		Content   string
+		Want      []map[string]string
+		Malformed []int
+	}{
+		{
+			Name:    "CSV with header",
+			File:    "stars.csv",
+			Content: "External_ID, name,sex,birth_date\nnm1,Ryan Gosling,male,1980-11-12T00:00:00Z\nnm2,\"Stone, Emma\",female\n",
+			Want: []map[string]string{
+				{"external_id": "nm1", "name": "Ryan Gosling", "sex": "male", "birth_date": "1980-11-12T00:00:00Z"},
+				{"external_id": "nm2", "name": "Stone, Emma", "sex": "female"},
+			},
+		},
+		{
+			Name:    "CSV with broken quotes",
+			File:    "movies.csv",
+			Content: "external_id,title\ntt1,\"Dri\"ve\ntt2,Dune\n",
+			Want: []map[string]string{
+				{"external_id": "tt2", "title": "Dune"},
+			},
+			Malformed: []int{2},
+		},
+		{
+			Name:    "JSON Lines",
+			File:    "movies.jsonl",
+			Content: "{\"external_id\":\"tt1\",\"title\":\"Drive\",\"rating\":8}\n\n{not json}\n{\"external_id\":\"tt2\",\"rating\":null}\n",
+			Want: []map[string]string{
+				{"external_id": "tt1", "title": "Drive", "rating": "8"},
+				{"external_id": "tt2"},
+			},
+			Malformed: []int{3},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			path := filepath.Join(dir, test.File)
+			if err := os.WriteFile(path, []byte(test.Content), 0o600); err != nil {
+				t.Fatalf("error with writing test file: %s", err.Error())
+			}
+
+			reader, closer, err := openRecords(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			defer closer.Close()
+
+			var (
+				got       []map[string]string
+				malformed []int
+			)
+			for {
+				rec, err := reader.Read()
+				if err == io.EOF {
+					break
+				}
+				var malformedErr *malformedError
+				if errors.As(err, &malformedErr) {
+					malformed = append(malformed, malformedErr.line)
+					continue
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err.Error())
+				}
+				got = append(got, rec.fields)
+			}
+
+			if len(got) != len(test.Want) {
+				t.Fatalf("wrong records count. Expected %d but got %d", len(test.Want), len(got))
+			}
+			for i, want := range test.Want {
+				for name, value := range want {
+					if got[i][name] != value {
+						t.Errorf("wrong %s in record %d. Expected %q but got %q", name, i, value, got[i][name])
+					}
+				}
+			}
+			if len(malformed) != len(test.Malformed) {
+				t.Fatalf("wrong malformed lines. Expected %v but got %v", test.Malformed, malformed)
+			}
+			for i, line := range test.Malformed {
+				if malformed[i] != line {
+					t.Errorf("wrong malformed line. Expected %d but got %d", line, malformed[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package image
+
+import (
+	"context"
+	"time"
+
+	"vk-test-task/pkg/format"
+	"vk-test-task/pkg/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	PosterKind   = "poster"
+	StillKind    = "still"
+	HeadshotKind = "headshot"
+)
+
+type (
+	Store interface {
+		Create(context.Context, CreateEntity) (Entity, error)
+		GetByMovieIDs(context.Context, []int) (map[int][]Entity, error)
+		GetByStarIDs(context.Context, []int) (map[int][]Entity, error)
+	}
+
+	storeImpl struct {
+		client *pgxpool.Pool
+	}
+
+	// CreateEntity belongs to either a movie or a star.
+	CreateEntity struct {
+		MovieID     *int
+		StarID      *int
+		Kind        string
+		Key         string
+		ContentType string
+		Width       int
+		Height      int
+		// Thumbnails are the keys of the thumbnails by size name
+		Thumbnails map[string]string
+	}
+
+	Entity struct {
+		ID          int
+		MovieID     *int
+		StarID      *int
+		Kind        string
+		Key         string
+		ContentType string
+		Width       int
+		Height      int
+		Thumbnails  map[string]string
+		CreatedAt   time.Time
+	}
+)
+
+func New(client *pgxpool.Pool) Store {
+	return &storeImpl{client: client}
+}
+
+func (s *storeImpl) Create(ctx context.Context, entity CreateEntity) (Entity, error) {
+	var image Entity
+
+	err := s.client.QueryRow(
+		ctx,
+		`
+			INSERT INTO images (movie_id, star_id, kind, key, content_type, width, height, thumbnails, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id, movie_id, star_id, kind, key, content_type, width, height, thumbnails, created_at
+		`,
+		entity.MovieID,
+		entity.StarID,
+		entity.Kind,
+		entity.Key,
+		entity.ContentType,
+		entity.Width,
+		entity.Height,
+		entity.Thumbnails,
+		format.TimeNow(),
+	).Scan(&image.ID,
+		&image.MovieID,
+		&image.StarID,
+		&image.Kind,
+		&image.Key,
+		&image.ContentType,
+		&image.Width,
+		&image.Height,
+		&image.Thumbnails,
+		&image.CreatedAt)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "create new image",
+			"error", err.Error())
+		return Entity{}, err
+	}
+
+	return image, nil
+}
+
+func (s *storeImpl) GetByMovieIDs(ctx context.Context, moviesID []int) (map[int][]Entity, error) {
+	return s.getByOwnerIDs(ctx, "movie_id", moviesID)
+}
+
+func (s *storeImpl) GetByStarIDs(ctx context.Context, starsID []int) (map[int][]Entity, error) {
+	return s.getByOwnerIDs(ctx, "star_id", starsID)
+}
+
+// getByOwnerIDs groups the images of the movies or the stars by owner id, column is
+// movie_id or star_id.
+func (s *storeImpl) getByOwnerIDs(ctx context.Context, column string, ownersID []int) (map[int][]Entity, error) {
+	images := make(map[int][]Entity, len(ownersID))
+
+	rows, err := s.client.Query(
+		ctx,
+		`
+			SELECT id, movie_id, star_id, kind, key, content_type, width, height, thumbnails, created_at
+			FROM images
+			WHERE `+column+` = ANY($1)
+			ORDER BY id
+		`,
+		ownersID,
+	)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "get images by owner ids",
+			"owner", column,
+			"error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var image Entity
+
+		if err := rows.Scan(&image.ID,
+			&image.MovieID,
+			&image.StarID,
+			&image.Kind,
+			&image.Key,
+			&image.ContentType,
+			&image.Width,
+			&image.Height,
+			&image.Thumbnails,
+			&image.CreatedAt,
+		); err != nil {
+			logger.Log.ErrorContext(ctx, "scan image",
+				"error", err.Error())
+			return nil, err
+		}
+
+		ownerID := image.StarID
+		if image.MovieID != nil {
+			ownerID = image.MovieID
+		}
+		images[*ownerID] = append(images[*ownerID], image)
+	}
+
+	return images, rows.Err()
+}
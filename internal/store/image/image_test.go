@@ -0,0 +1,139 @@
+package image
+
+import (
+	"context"
+	"testing"
+
+	"vk-test-task/internal/tests"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestCreateAndGetByOwnerIDs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postgresContainer, databaseURL := tests.CreateFilmotekaTestPostgresContainer(ctx)
+	defer tests.TerminateFilmotekaTestContainer(ctx, postgresContainer)
+
+	postgresClient, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		t.Errorf("error with starting postgres client: %s", err.Error())
+	}
+	defer postgresClient.Close()
+
+	movieID, starID, err := addOwners(ctx, postgresClient)
+	if err != nil {
+		t.Fatalf("error with adding existing data: %s", err.Error())
+	}
+
+	store := New(postgresClient)
+
+	for _, test := range []struct {
+		Name    string
+		Data    CreateEntity
+		WantErr bool
+	}{
+		{
+			Name: "Successful creating a poster",
+			Data: CreateEntity{
+				MovieID:     &movieID,
+				Kind:        PosterKind,
+				Key:         "poster.jpg",
+				ContentType: "image/jpeg",
+				Width:       2000,
+				Height:      3000,
+				Thumbnails:  map[string]string{"small": "poster_small.jpg"},
+			},
+		},
+		{
+			Name: "Successful creating a headshot",
+			Data: CreateEntity{
+				StarID:      &starID,
+				Kind:        HeadshotKind,
+				Key:         "headshot.png",
+				ContentType: "image/png",
+				Width:       400,
+				Height:      400,
+				Thumbnails:  map[string]string{"small": "headshot_small.png"},
+			},
+		},
+		{
+			Name: "Image of both a movie and a star",
+			Data: CreateEntity{
+				MovieID:     &movieID,
+				StarID:      &starID,
+				Kind:        StillKind,
+				Key:         "still.jpg",
+				ContentType: "image/jpeg",
+			},
+			WantErr: true,
+		},
+		{
+			Name: "Duplicate key",
+			Data: CreateEntity{
+				MovieID:     &movieID,
+				Kind:        StillKind,
+				Key:         "poster.jpg",
+				ContentType: "image/jpeg",
+			},
+			WantErr: true,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			entity, err := store.Create(ctx, test.Data)
+			if err != nil {
+				if !test.WantErr {
+					t.Errorf("unexpected error: %s", err.Error())
+				}
+				return
+			}
+			if test.WantErr {
+				t.Errorf("expected error but nothing got")
+			}
+			if entity.Key != test.Data.Key {
+				t.Errorf("wrong key. Expected %q but got %q", test.Data.Key, entity.Key)
+			}
+			if entity.Thumbnails["small"] != test.Data.Thumbnails["small"] {
+				t.Errorf("wrong thumbnails. Expected %v but got %v", test.Data.Thumbnails, entity.Thumbnails)
+			}
+		})
+	}
+
+	moviesImages, err := store.GetByMovieIDs(ctx, []int{movieID, movieID + 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(moviesImages) != 1 || len(moviesImages[movieID]) != 1 || moviesImages[movieID][0].Kind != PosterKind {
+		t.Errorf("wrong images of movies. Expected the poster but got %+v", moviesImages)
+	}
+
+	starsImages, err := store.GetByStarIDs(ctx, []int{starID})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(starsImages[starID]) != 1 || starsImages[starID][0].Key != "headshot.png" {
+		t.Errorf("wrong images of stars. Expected the headshot but got %+v", starsImages)
+	}
+}
+
+func addOwners(ctx context.Context, postgresClient *pgxpool.Pool) (int, int, error) {
+	var movieID, starID int
+
+	err := postgresClient.QueryRow(ctx, `
+		INSERT INTO movies (title, description, release_date, rating)
+		VALUES ('Drive', 'A stuntman', '2011-11-03', 10)
+		RETURNING id
+	`).Scan(&movieID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	err = postgresClient.QueryRow(ctx, `
+		INSERT INTO stars (name, birth_date, sex)
+		VALUES ('Ryan Gosling', '1980-11-12', 'male')
+		RETURNING id
+	`).Scan(&starID)
+
+	return movieID, starID, err
+}
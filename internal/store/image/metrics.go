@@ -0,0 +1,35 @@
+package image
+
+import (
+	"context"
+	"time"
+
+	"vk-test-task/pkg/metrics"
+)
+
+const metricsName = "image"
+
+// instrumentedStore records the duration of every call to the wrapped store.
+type instrumentedStore struct {
+	store Store
+}
+
+// WithMetrics wraps the store to report query durations per method.
+func WithMetrics(store Store) Store {
+	return &instrumentedStore{store: store}
+}
+
+func (s *instrumentedStore) Create(ctx context.Context, entity CreateEntity) (Entity, error) {
+	defer metrics.ObserveQuery(metricsName, "Create", time.Now())
+	return s.store.Create(ctx, entity)
+}
+
+func (s *instrumentedStore) GetByMovieIDs(ctx context.Context, moviesID []int) (map[int][]Entity, error) {
+	defer metrics.ObserveQuery(metricsName, "GetByMovieIDs", time.Now())
+	return s.store.GetByMovieIDs(ctx, moviesID)
+}
+
+func (s *instrumentedStore) GetByStarIDs(ctx context.Context, starsID []int) (map[int][]Entity, error) {
+	defer metrics.ObserveQuery(metricsName, "GetByStarIDs", time.Now())
+	return s.store.GetByStarIDs(ctx, starsID)
+}
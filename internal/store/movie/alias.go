@@ -0,0 +1,118 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/pkg/format"
+	"vk-test-task/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AliasEntity is another title a movie is known under: the original, a working or a
+// regional one.
+type AliasEntity struct {
+	ID        int
+	MovieID   int
+	Alias     string
+	Kind      string
+	CreatedAt time.Time
+}
+
+func (s *storeImpl) CreateAlias(ctx context.Context, entity AliasEntity) (AliasEntity, error) {
+	var alias AliasEntity
+
+	err := s.client.QueryRow(
+		ctx,
+		`
+			INSERT INTO movie_aliases (movie_id, alias, kind, created_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (movie_id, alias) DO NOTHING
+			RETURNING id, movie_id, alias, kind, created_at
+		`,
+		entity.MovieID,
+		entity.Alias,
+		entity.Kind,
+		format.TimeNow(),
+	).Scan(&alias.ID,
+		&alias.MovieID,
+		&alias.Alias,
+		&alias.Kind,
+		&alias.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return AliasEntity{}, core.ErrAliasExists
+	}
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "create movie alias",
+			"error", err.Error())
+		return AliasEntity{}, err
+	}
+
+	return alias, nil
+}
+
+func (s *storeImpl) GetAliases(ctx context.Context, movieID int) ([]AliasEntity, error) {
+	aliases := []AliasEntity{}
+
+	rows, err := s.client.Query(
+		ctx,
+		`
+			SELECT id, movie_id, alias, kind, created_at
+			FROM movie_aliases
+			WHERE movie_id = $1
+			ORDER BY id
+		`,
+		movieID,
+	)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "get movie aliases",
+			"error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var alias AliasEntity
+
+		if err := rows.Scan(&alias.ID,
+			&alias.MovieID,
+			&alias.Alias,
+			&alias.Kind,
+			&alias.CreatedAt,
+		); err != nil {
+			logger.Log.ErrorContext(ctx, "scan movie alias",
+				"error", err.Error())
+			return nil, err
+		}
+
+		aliases = append(aliases, alias)
+	}
+
+	return aliases, rows.Err()
+}
+
+func (s *storeImpl) DeleteAlias(ctx context.Context, movieID, aliasID int) error {
+	n, err := s.client.Exec(
+		ctx,
+		`
+			DELETE FROM movie_aliases
+			WHERE id = $1 AND movie_id = $2
+		`,
+		aliasID,
+		movieID,
+	)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "delete movie alias",
+			"error", err.Error())
+		return err
+	}
+
+	if n.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
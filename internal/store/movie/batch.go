@@ -0,0 +1,263 @@
+package movie
+
+import (
+	"context"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/pkg/format"
+	"vk-test-task/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	BatchOperation struct {
+		Op     string
+		ID     int
+		Create CreateEntity
+		Update UpdateEntity
+	}
+
+	BatchResult struct {
+		Entity Entity
+		Err    error
+	}
+)
+
+// Batch applies operations in a single transaction. In atomic mode any failed
+// operation rolls back the whole batch, otherwise failed operations are retried
+// one by one so that the rest of the batch is still applied.
+func (s *storeImpl) Batch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchResult, error) {
+	tx, err := s.client.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.execBatchInTx(ctx, ops, tx)
+	if err == nil && atomic && hasFailed(results) {
+		err = core.ErrBatchAborted
+	}
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "execute movies batch",
+			"error", err.Error())
+		if txErr := tx.Rollback(ctx); txErr != nil {
+			logger.Log.ErrorContext(ctx, "rollback",
+				"error", txErr.Error())
+			return nil, txErr
+		}
+		if atomic {
+			return results, err
+		}
+		return s.execEach(ctx, ops)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "committing batch transaction",
+			"error", err.Error())
+	}
+
+	return results, err
+}
+
+func (s *storeImpl) execEach(ctx context.Context, ops []BatchOperation) ([]BatchResult, error) {
+	tx, err := s.client.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchResult, len(ops))
+	for i, op := range ops {
+		results[i] = s.execOneInTx(ctx, op, tx)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "committing batch transaction",
+			"error", err.Error())
+	}
+
+	return results, err
+}
+
+// execOneInTx runs a single operation under a savepoint, so its failure
+// does not abort the enclosing transaction.
+func (s *storeImpl) execOneInTx(ctx context.Context, op BatchOperation, tx pgx.Tx) BatchResult {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return BatchResult{Err: err}
+	}
+
+	results, err := s.execBatchInTx(ctx, []BatchOperation{op}, savepoint)
+	if err != nil {
+		if txErr := savepoint.Rollback(ctx); txErr != nil {
+			logger.Log.ErrorContext(ctx, "rollback",
+				"error", txErr.Error())
+		}
+		return BatchResult{Err: err}
+	}
+
+	if err := savepoint.Commit(ctx); err != nil {
+		return BatchResult{Err: err}
+	}
+
+	return results[0]
+}
+
+func (s *storeImpl) execBatchInTx(ctx context.Context, ops []BatchOperation, tx pgx.Tx) ([]BatchResult, error) {
+	batch := &pgx.Batch{}
+	for _, op := range ops {
+		switch op.Op {
+		case core.BatchCreateOp:
+			batch.Queue(
+				`
+					INSERT INTO movies (external_id, title, description, release_date, rating, created_at, updated_at)
+					VALUES ($1, $2, $3, $4, $5, $6, $7)
+					RETURNING id, title, description, release_date, rating, created_at, updated_at, deleted_at
+				`,
+				op.Create.ExternalID,
+				op.Create.Title,
+				op.Create.Description,
+				op.Create.ReleaseDate,
+				op.Create.Rating,
+				format.TimeNow(),
+				format.TimeNow(),
+			)
+		case core.BatchUpdateOp:
+			batch.Queue(
+				`
+					UPDATE movies
+					SET title = COALESCE($1, title),
+						description = COALESCE($2, description),
+						release_date = COALESCE($3, release_date),
+						rating = COALESCE($4, rating),
+						updated_at = $5
+					WHERE id = $6 AND deleted_at IS NULL
+					RETURNING id, title, description, release_date, rating, created_at, updated_at, deleted_at
+				`,
+				op.Update.Title,
+				op.Update.Description,
+				op.Update.ReleaseDate,
+				op.Update.Rating,
+				format.TimeNow(),
+				op.ID,
+			)
+		case core.BatchDeleteOp:
+			batch.Queue(
+				`
+					UPDATE movies
+					SET updated_at = $1, deleted_at = $2
+					WHERE id = $3 AND deleted_at IS NULL
+					RETURNING id, title, description, release_date, rating, created_at, updated_at, deleted_at
+				`,
+				format.TimeNow(),
+				format.TimeNow(),
+				op.ID,
+			)
+		}
+	}
+
+	results := make([]BatchResult, len(ops))
+
+	batchResults := tx.SendBatch(ctx, batch)
+	for i := range ops {
+		var movie Entity
+
+		err := batchResults.QueryRow().Scan(&movie.ID,
+			&movie.Title,
+			&movie.Description,
+			&movie.ReleaseDate,
+			&movie.Rating,
+			&movie.CreatedAt,
+			&movie.UpdatedAt,
+			&movie.DeletedAt)
+		switch err {
+		case nil:
+			results[i].Entity = movie
+		case pgx.ErrNoRows:
+			results[i].Err = err
+		default:
+			batchResults.Close()
+			return nil, err
+		}
+	}
+	if err := batchResults.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := s.replaceStarsForMoviesInTx(ctx, ops, results, tx); err != nil {
+		logger.Log.ErrorContext(ctx, "replace stars for movies",
+			"error", err.Error())
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// replaceStarsForMoviesInTx links stars to the created and updated movies of a batch
+// with a single COPY instead of an INSERT per pair.
+func (s *storeImpl) replaceStarsForMoviesInTx(ctx context.Context, ops []BatchOperation, results []BatchResult, tx pgx.Tx) error {
+	var (
+		updatedMoviesID []int
+		links           [][]any
+	)
+
+	for i, op := range ops {
+		if results[i].Err != nil {
+			continue
+		}
+
+		movieID := results[i].Entity.ID
+		switch op.Op {
+		case core.BatchCreateOp:
+			for _, starID := range op.Create.StarsID {
+				links = append(links, []any{movieID, starID})
+			}
+		case core.BatchUpdateOp:
+			// We believe that a movie cannot exist without actors
+			if len(op.Update.StarsID) == 0 {
+				continue
+			}
+			updatedMoviesID = append(updatedMoviesID, movieID)
+			for _, starID := range op.Update.StarsID {
+				links = append(links, []any{movieID, starID})
+			}
+		}
+	}
+
+	if len(updatedMoviesID) != 0 {
+		if _, err := tx.Exec(
+			ctx,
+			`
+				DELETE FROM movie_stars
+				WHERE movie_id = ANY($1)
+			`,
+			updatedMoviesID,
+		); err != nil {
+			return err
+		}
+	}
+
+	if len(links) == 0 {
+		return nil
+	}
+
+	_, err := tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"movie_stars"},
+		[]string{"movie_id", "star_id"},
+		pgx.CopyFromRows(links),
+	)
+
+	return err
+}
+
+func hasFailed(results []BatchResult) bool {
+	for _, result := range results {
+		if result.Err != nil {
+			return true
+		}
+	}
+
+	return false
+}
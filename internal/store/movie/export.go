@@ -0,0 +1,116 @@
+package movie
+
+import (
+	"context"
+	"fmt"
+
+	"vk-test-task/pkg/logger"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type ExportEntity struct {
+	Entity
+	ExternalID *string
+	StarsID    []int
+}
+
+// Export passes movies matching params to fn in the order of params, ignoring
+// Limit and Offset. Rows are scanned one by one as pgx reads them from the
+// connection, so the result set is never held in memory.
+func (s *storeImpl) Export(ctx context.Context, params GetAllParams, fn func(ExportEntity) error) error {
+	// the most preferred translation replaces the title and the description
+	title, description, locale := "m.title", "m.description", "'' AS locale"
+	if len(params.Locales) > 0 {
+		title, description, locale = "COALESCE(t.title, m.title)", "COALESCE(t.description, m.description)", "COALESCE(t.locale, '') AS locale"
+	}
+
+	selectQuery := s.statBuilder.
+		Select("m.id", "m.external_id", title, description, "m.release_date", "m.rating", "m.created_at", "m.updated_at", "m.deleted_at",
+			"ARRAY(SELECT ms.star_id FROM movie_stars ms WHERE ms.movie_id = m.id ORDER BY ms.star_id) AS stars_id",
+			locale).
+		From("movies m").
+		Where("m.deleted_at IS NULL")
+
+	if len(params.Locales) > 0 {
+		selectQuery = selectQuery.
+			JoinClause(
+				`LEFT JOIN LATERAL (
+					SELECT mt.locale, mt.title, mt.description
+					FROM movie_translations mt
+					WHERE mt.movie_id = m.id AND mt.locale = ANY(?)
+					ORDER BY array_position(?, mt.locale::TEXT)
+					LIMIT 1
+				) t ON TRUE`,
+				params.Locales,
+				params.Locales,
+			)
+	}
+
+	if params.SearchTerm != "" {
+		selectQuery = selectQuery.
+			Where(sq.Or{
+				sq.ILike{"m.title": "%" + params.SearchTerm + "%"},
+				sq.Expr(
+					`EXISTS (
+						SELECT 1
+						FROM movie_stars ms
+						JOIN stars s ON ms.star_id = s.id
+						WHERE ms.movie_id = m.id AND s.name ILIKE ?
+					)`,
+					"%"+params.SearchTerm+"%",
+				),
+			})
+	}
+
+	// id keeps the order of movies with equal sort values stable between exports
+	if params.SortBy != "" {
+		selectQuery = selectQuery.
+			OrderBy(fmt.Sprintf("m.%s %s", params.SortBy, params.SortOrder), "m.id")
+	} else {
+		selectQuery = selectQuery.
+			OrderBy("m.rating DESC", "m.id")
+	}
+
+	sqlQuery, args, err := selectQuery.ToSql()
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "generate select query",
+			"error", err.Error())
+		return err
+	}
+
+	rows, err := s.client.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "export movies",
+			"error", err.Error())
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movie ExportEntity
+
+		if err := rows.Scan(&movie.ID,
+			&movie.ExternalID,
+			&movie.Title,
+			&movie.Description,
+			&movie.ReleaseDate,
+			&movie.Rating,
+			&movie.CreatedAt,
+			&movie.UpdatedAt,
+			&movie.DeletedAt,
+			&movie.StarsID,
+			&movie.Locale,
+		); err != nil {
+			logger.Log.ErrorContext(ctx, "scan movie",
+				"error", err.Error())
+			return err
+		}
+
+		if err := fn(movie); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
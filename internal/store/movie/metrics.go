@@ -0,0 +1,105 @@
+package movie
+
+import (
+	"context"
+	"time"
+
+	"vk-test-task/pkg/metrics"
+)
+
+const metricsName = "movie"
+
+// instrumentedStore records the duration of every call to the wrapped store.
+type instrumentedStore struct {
+	store Store
+}
+
+// WithMetrics wraps the store to report query durations per method.
+func WithMetrics(store Store) Store {
+	return &instrumentedStore{store: store}
+}
+
+func (s *instrumentedStore) Create(ctx context.Context, entity CreateEntity) (Entity, error) {
+	defer metrics.ObserveQuery(metricsName, "Create", time.Now())
+	return s.store.Create(ctx, entity)
+}
+
+func (s *instrumentedStore) GetByID(ctx context.Context, id int) (Entity, error) {
+	defer metrics.ObserveQuery(metricsName, "GetByID", time.Now())
+	return s.store.GetByID(ctx, id)
+}
+
+func (s *instrumentedStore) GetByStarID(ctx context.Context, starID int) ([]Entity, error) {
+	defer metrics.ObserveQuery(metricsName, "GetByStarID", time.Now())
+	return s.store.GetByStarID(ctx, starID)
+}
+
+func (s *instrumentedStore) GetAll(ctx context.Context, params GetAllParams) (EntityWithTotalCount, error) {
+	defer metrics.ObserveQuery(metricsName, "GetAll", time.Now())
+	return s.store.GetAll(ctx, params)
+}
+
+func (s *instrumentedStore) Update(ctx context.Context, id int, entity UpdateEntity) (Entity, error) {
+	defer metrics.ObserveQuery(metricsName, "Update", time.Now())
+	return s.store.Update(ctx, id, entity)
+}
+
+func (s *instrumentedStore) Delete(ctx context.Context, id int) error {
+	defer metrics.ObserveQuery(metricsName, "Delete", time.Now())
+	return s.store.Delete(ctx, id)
+}
+
+func (s *instrumentedStore) Batch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchResult, error) {
+	defer metrics.ObserveQuery(metricsName, "Batch", time.Now())
+	return s.store.Batch(ctx, ops, atomic)
+}
+
+func (s *instrumentedStore) GetIDsByExternalIDs(ctx context.Context, externalIDs []string) (map[string]int, error) {
+	defer metrics.ObserveQuery(metricsName, "GetIDsByExternalIDs", time.Now())
+	return s.store.GetIDsByExternalIDs(ctx, externalIDs)
+}
+
+func (s *instrumentedStore) Export(ctx context.Context, params GetAllParams, fn func(ExportEntity) error) error {
+	defer metrics.ObserveQuery(metricsName, "Export", time.Now())
+	return s.store.Export(ctx, params, fn)
+}
+
+func (s *instrumentedStore) GetByStarIDs(ctx context.Context, starsID []int) (map[int][]Entity, error) {
+	defer metrics.ObserveQuery(metricsName, "GetByStarIDs", time.Now())
+	return s.store.GetByStarIDs(ctx, starsID)
+}
+
+func (s *instrumentedStore) Count(ctx context.Context) (int, error) {
+	defer metrics.ObserveQuery(metricsName, "Count", time.Now())
+	return s.store.Count(ctx)
+}
+
+func (s *instrumentedStore) UpsertTranslation(ctx context.Context, entity TranslationEntity) (TranslationEntity, error) {
+	defer metrics.ObserveQuery(metricsName, "UpsertTranslation", time.Now())
+	return s.store.UpsertTranslation(ctx, entity)
+}
+
+func (s *instrumentedStore) DeleteTranslation(ctx context.Context, movieID int, locale string) error {
+	defer metrics.ObserveQuery(metricsName, "DeleteTranslation", time.Now())
+	return s.store.DeleteTranslation(ctx, movieID, locale)
+}
+
+func (s *instrumentedStore) GetTranslations(ctx context.Context, moviesID []int, locales []string) (map[int]TranslationEntity, error) {
+	defer metrics.ObserveQuery(metricsName, "GetTranslations", time.Now())
+	return s.store.GetTranslations(ctx, moviesID, locales)
+}
+
+func (s *instrumentedStore) CreateAlias(ctx context.Context, entity AliasEntity) (AliasEntity, error) {
+	defer metrics.ObserveQuery(metricsName, "CreateAlias", time.Now())
+	return s.store.CreateAlias(ctx, entity)
+}
+
+func (s *instrumentedStore) GetAliases(ctx context.Context, movieID int) ([]AliasEntity, error) {
+	defer metrics.ObserveQuery(metricsName, "GetAliases", time.Now())
+	return s.store.GetAliases(ctx, movieID)
+}
+
+func (s *instrumentedStore) DeleteAlias(ctx context.Context, movieID, aliasID int) error {
+	defer metrics.ObserveQuery(metricsName, "DeleteAlias", time.Now())
+	return s.store.DeleteAlias(ctx, movieID, aliasID)
+}
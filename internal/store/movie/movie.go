@@ -21,6 +21,17 @@ type (
 		GetAll(context.Context, GetAllParams) (EntityWithTotalCount, error)
 		Update(context.Context, int, UpdateEntity) (Entity, error)
 		Delete(context.Context, int) error
+		Batch(context.Context, []BatchOperation, bool) ([]BatchResult, error)
+		GetIDsByExternalIDs(context.Context, []string) (map[string]int, error)
+		Export(context.Context, GetAllParams, func(ExportEntity) error) error
+		GetByStarIDs(context.Context, []int) (map[int][]Entity, error)
+		Count(context.Context) (int, error)
+		UpsertTranslation(context.Context, TranslationEntity) (TranslationEntity, error)
+		DeleteTranslation(context.Context, int, string) error
+		GetTranslations(context.Context, []int, []string) (map[int]TranslationEntity, error)
+		CreateAlias(context.Context, AliasEntity) (AliasEntity, error)
+		GetAliases(context.Context, int) ([]AliasEntity, error)
+		DeleteAlias(context.Context, int, int) error
 	}
 
 	storeImpl struct {
@@ -34,9 +45,13 @@ type (
 		SortOrder  string
 		Limit      int
 		Offset     int
+		// Locales are the translations Export reads titles and descriptions in, by
+		// preference. GetAll leaves localisation to the service.
+		Locales []string
 	}
 
 	CreateEntity struct {
+		ExternalID  *string
 		Title       string
 		Description string
 		ReleaseDate time.Time
@@ -61,6 +76,10 @@ type (
 		CreatedAt   time.Time
 		UpdatedAt   time.Time
 		DeletedAt   *time.Time
+		// Locale of the title and the description, set when the movie is localised
+		Locale string
+		// MatchedAlias is the alias the search term of GetAll matched, if any
+		MatchedAlias string
 	}
 
 	EntityWithTotalCount struct {
@@ -87,10 +106,11 @@ func (s *storeImpl) Create(ctx context.Context, entity CreateEntity) (Entity, er
 	err = tx.QueryRow(
 		ctx,
 		`
-			INSERT INTO movies (title, description, release_date, rating, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6)
+			INSERT INTO movies (external_id, title, description, release_date, rating, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
 			RETURNING id, title, description, release_date, rating, created_at, updated_at
 		`,
+		entity.ExternalID,
 		entity.Title,
 		entity.Description,
 		entity.ReleaseDate,
@@ -105,10 +125,10 @@ func (s *storeImpl) Create(ctx context.Context, entity CreateEntity) (Entity, er
 		&newMovie.CreatedAt,
 		&newMovie.UpdatedAt)
 	if err != nil {
-		logger.Log.Error("create new movie",
+		logger.Log.ErrorContext(ctx, "create new movie",
 			"error", err.Error())
 		if txErr := tx.Rollback(ctx); txErr != nil {
-			logger.Log.Error("rollback",
+			logger.Log.ErrorContext(ctx, "rollback",
 				"error", txErr.Error())
 			return Entity{}, txErr
 		}
@@ -116,10 +136,10 @@ func (s *storeImpl) Create(ctx context.Context, entity CreateEntity) (Entity, er
 	}
 
 	if err = s.addStarsToMovieInTx(ctx, newMovie.ID, entity.StarsID, tx); err != nil {
-		logger.Log.Error("adding stars to movie",
+		logger.Log.ErrorContext(ctx, "adding stars to movie",
 			"error", err.Error())
 		if txErr := tx.Rollback(ctx); txErr != nil {
-			logger.Log.Error("rollback",
+			logger.Log.ErrorContext(ctx, "rollback",
 				"error", txErr.Error())
 			return Entity{}, txErr
 		}
@@ -128,7 +148,7 @@ func (s *storeImpl) Create(ctx context.Context, entity CreateEntity) (Entity, er
 
 	err = tx.Commit(ctx)
 	if err != nil {
-		logger.Log.Error("committing create transaction",
+		logger.Log.ErrorContext(ctx, "committing create transaction",
 			"error", err.Error())
 	}
 
@@ -155,7 +175,7 @@ func (s *storeImpl) GetByID(ctx context.Context, id int) (Entity, error) {
 		&movie.UpdatedAt,
 		&movie.DeletedAt)
 	if err != nil {
-		logger.Log.Error("get movie by id",
+		logger.Log.ErrorContext(ctx, "get movie by id",
 			"error", err.Error())
 	}
 
@@ -177,7 +197,7 @@ func (s *storeImpl) GetByStarID(ctx context.Context, starID int) ([]Entity, erro
 		starID,
 	)
 	if err != nil {
-		logger.Log.Error("get movies by star id",
+		logger.Log.ErrorContext(ctx, "get movies by star id",
 			"error", err.Error())
 		return []Entity{}, err
 	}
@@ -196,7 +216,7 @@ func (s *storeImpl) GetByStarID(ctx context.Context, starID int) ([]Entity, erro
 			&movie.UpdatedAt,
 			&movie.DeletedAt,
 		); err != nil {
-			logger.Log.Error("scan star",
+			logger.Log.ErrorContext(ctx, "scan star",
 				"error", err.Error())
 			return []Entity{}, err
 		}
@@ -207,6 +227,55 @@ func (s *storeImpl) GetByStarID(ctx context.Context, starID int) ([]Entity, erro
 	return movies, nil
 }
 
+// GetByStarIDs returns movies of several stars with one query, keyed by star id.
+func (s *storeImpl) GetByStarIDs(ctx context.Context, starsID []int) (map[int][]Entity, error) {
+	movies := make(map[int][]Entity, len(starsID))
+
+	rows, err := s.client.Query(
+		ctx,
+		`
+			SELECT ms.star_id, m.id, m.title, m.description, m.release_date, m.rating, m.created_at, m.updated_at, m.deleted_at
+			FROM movies m
+			JOIN movie_stars ms ON m.id = ms.movie_id
+			WHERE ms.star_id = ANY($1)
+			ORDER BY m.id
+		`,
+		starsID,
+	)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "get movies by star ids",
+			"error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			starID int
+			movie  Entity
+		)
+
+		if err := rows.Scan(&starID,
+			&movie.ID,
+			&movie.Title,
+			&movie.Description,
+			&movie.ReleaseDate,
+			&movie.Rating,
+			&movie.CreatedAt,
+			&movie.UpdatedAt,
+			&movie.DeletedAt,
+		); err != nil {
+			logger.Log.ErrorContext(ctx, "scan movie",
+				"error", err.Error())
+			return nil, err
+		}
+
+		movies[starID] = append(movies[starID], movie)
+	}
+
+	return movies, rows.Err()
+}
+
 func (s *storeImpl) GetAll(ctx context.Context, params GetAllParams) (EntityWithTotalCount, error) {
 	var movies EntityWithTotalCount
 
@@ -218,13 +287,19 @@ func (s *storeImpl) GetAll(ctx context.Context, params GetAllParams) (EntityWith
 		Offset(uint64(params.Offset))
 
 	if params.SearchTerm != "" {
+		term := "%" + params.SearchTerm + "%"
 		selectQuery = selectQuery.
-			Join("movie_stars ms ON m.id = ms.movie_id").
-			Join("stars s ON ms.star_id = s.id").
+			Column(sq.Expr("(SELECT ma.alias FROM movie_aliases ma WHERE ma.movie_id = m.id AND ma.alias ILIKE ? ORDER BY ma.id LIMIT 1) AS matched_alias", term)).
 			Where(sq.Or{
-				sq.ILike{"m.title": "%" + params.SearchTerm + "%"},
-				sq.ILike{"s.name": "%" + params.SearchTerm + "%"},
+				sq.ILike{"m.title": term},
+				sq.Expr("EXISTS (SELECT 1 FROM movie_stars ms JOIN stars s ON ms.star_id = s.id WHERE ms.movie_id = m.id AND s.name ILIKE ?)", term),
+				// titles are searched in every locale, whatever the one asked for
+				sq.Expr("EXISTS (SELECT 1 FROM movie_translations mt WHERE mt.movie_id = m.id AND mt.title ILIKE ?)", term),
+				sq.Expr("EXISTS (SELECT 1 FROM movie_aliases ma WHERE ma.movie_id = m.id AND ma.alias ILIKE ?)", term),
 			})
+	} else {
+		selectQuery = selectQuery.
+			Column("NULL AS matched_alias")
 	}
 
 	if params.SortBy != "" {
@@ -237,14 +312,14 @@ func (s *storeImpl) GetAll(ctx context.Context, params GetAllParams) (EntityWith
 
 	sqlQuery, args, err := selectQuery.ToSql()
 	if err != nil {
-		logger.Log.Error("generate select query",
+		logger.Log.ErrorContext(ctx, "generate select query",
 			"error", err.Error())
 		return EntityWithTotalCount{}, err
 	}
 
 	rows, err := s.client.Query(ctx, sqlQuery, args...)
 	if err != nil {
-		logger.Log.Error("get movies",
+		logger.Log.ErrorContext(ctx, "get movies",
 			"error", err.Error())
 		return EntityWithTotalCount{}, err
 	}
@@ -253,6 +328,7 @@ func (s *storeImpl) GetAll(ctx context.Context, params GetAllParams) (EntityWith
 	for rows.Next() {
 		var movie Entity
 		var total int
+		var matchedAlias *string
 
 		if err := rows.Scan(&movie.ID,
 			&movie.Title,
@@ -263,11 +339,15 @@ func (s *storeImpl) GetAll(ctx context.Context, params GetAllParams) (EntityWith
 			&movie.UpdatedAt,
 			&movie.DeletedAt,
 			&total,
+			&matchedAlias,
 		); err != nil {
-			logger.Log.Error("scan star",
+			logger.Log.ErrorContext(ctx, "scan star",
 				"error", err.Error())
 			return EntityWithTotalCount{}, err
 		}
+		if matchedAlias != nil {
+			movie.MatchedAlias = *matchedAlias
+		}
 
 		movies.Movies = append(movies.Movies, movie)
 		movies.TotalCount = total
@@ -279,7 +359,7 @@ func (s *storeImpl) GetAll(ctx context.Context, params GetAllParams) (EntityWith
 func (s *storeImpl) Update(ctx context.Context, id int, entity UpdateEntity) (Entity, error) {
 	item, err := s.GetByID(ctx, id)
 	if err != nil {
-		logger.Log.Error("get star by id",
+		logger.Log.ErrorContext(ctx, "get star by id",
 			"error", err.Error())
 		return Entity{}, err
 	}
@@ -327,10 +407,10 @@ func (s *storeImpl) Update(ctx context.Context, id int, entity UpdateEntity) (En
 		&updatedStar.CreatedAt,
 		&updatedStar.UpdatedAt,
 	); err != nil {
-		logger.Log.Error("update star",
+		logger.Log.ErrorContext(ctx, "update star",
 			"error", err.Error())
 		if txErr := tx.Rollback(ctx); txErr != nil {
-			logger.Log.Error("rollback",
+			logger.Log.ErrorContext(ctx, "rollback",
 				"error", txErr.Error())
 			return Entity{}, txErr
 		}
@@ -340,10 +420,10 @@ func (s *storeImpl) Update(ctx context.Context, id int, entity UpdateEntity) (En
 	// We believe that a movie cannot exist without actors
 	if len(entity.StarsID) != 0 {
 		if err := s.updateStarsForMovieInTx(ctx, id, oldStarsID, entity.StarsID, tx); err != nil {
-			logger.Log.Error("update stars for movie",
+			logger.Log.ErrorContext(ctx, "update stars for movie",
 				"error", err.Error())
 			if txErr := tx.Rollback(ctx); txErr != nil {
-				logger.Log.Error("rollback",
+				logger.Log.ErrorContext(ctx, "rollback",
 					"error", txErr.Error())
 				return Entity{}, txErr
 			}
@@ -353,7 +433,7 @@ func (s *storeImpl) Update(ctx context.Context, id int, entity UpdateEntity) (En
 
 	err = tx.Commit(ctx)
 	if err != nil {
-		logger.Log.Error("committing update transaction",
+		logger.Log.ErrorContext(ctx, "committing update transaction",
 			"error", err.Error())
 	}
 
@@ -373,7 +453,7 @@ func (s *storeImpl) Delete(ctx context.Context, id int) error {
 		id,
 	)
 	if err != nil {
-		logger.Log.Error("delete movie",
+		logger.Log.ErrorContext(ctx, "delete movie",
 			"error", err.Error())
 		return err
 	}
@@ -385,6 +465,62 @@ func (s *storeImpl) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
+func (s *storeImpl) Count(ctx context.Context) (int, error) {
+	var count int
+
+	err := s.client.QueryRow(
+		ctx,
+		`
+			SELECT COUNT(*)
+			FROM movies
+			WHERE deleted_at IS NULL
+		`,
+	).Scan(&count)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "count movies",
+			"error", err.Error())
+	}
+
+	return count, err
+}
+
+func (s *storeImpl) GetIDsByExternalIDs(ctx context.Context, externalIDs []string) (map[string]int, error) {
+	ids := make(map[string]int, len(externalIDs))
+
+	rows, err := s.client.Query(
+		ctx,
+		`
+            SELECT external_id, id
+            FROM movies
+            WHERE external_id = ANY($1)
+        `,
+		externalIDs,
+	)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "get movies by external ids",
+			"error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			externalID string
+			id         int
+		)
+
+		if err := rows.Scan(&externalID, &id); err != nil {
+			logger.Log.ErrorContext(ctx, "scan movie",
+				"error", err.Error())
+			return nil, err
+		}
+
+		ids[externalID] = id
+	}
+
+	return ids, rows.Err()
+}
+
 func (s *storeImpl) addStarsToMovieInTx(ctx context.Context, movieID int, starsID []int, tx pgx.Tx) error {
 	for _, starID := range starsID {
 		_, err := tx.Exec(
@@ -397,7 +533,7 @@ func (s *storeImpl) addStarsToMovieInTx(ctx context.Context, movieID int, starsI
 			starID,
 		)
 		if err != nil {
-			logger.Log.Error("add star to movie",
+			logger.Log.ErrorContext(ctx, "add star to movie",
 				"error", err.Error())
 			return err
 		}
@@ -418,7 +554,7 @@ func (s *storeImpl) deleteStarsFromMovieInTx(ctx context.Context, movieID int, s
 			starID,
 		)
 		if err != nil {
-			logger.Log.Error("delete star from movie",
+			logger.Log.ErrorContext(ctx, "delete star from movie",
 				"error", err.Error())
 			return err
 		}
@@ -448,7 +584,7 @@ func (s *storeImpl) getStarsIDForMovie(ctx context.Context, movieID int) ([]int,
 		movieID,
 	)
 	if err != nil {
-		logger.Log.Error("get stars for movie",
+		logger.Log.ErrorContext(ctx, "get stars for movie",
 			"error", err.Error())
 		return []int{}, err
 	}
@@ -458,7 +594,7 @@ func (s *storeImpl) getStarsIDForMovie(ctx context.Context, movieID int) ([]int,
 		var starID int
 
 		if err := rows.Scan(&starID); err != nil {
-			logger.Log.Error("scan star",
+			logger.Log.ErrorContext(ctx, "scan star",
 				"error", err.Error())
 			return []int{}, err
 		}
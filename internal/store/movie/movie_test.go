@@ -2,11 +2,13 @@ package movie
 
 import (
 	"context"
+	"errors"
 	"math/rand"
 	"strings"
 	"testing"
 	"time"
 
+	"vk-test-task/internal/core"
 	"vk-test-task/internal/tests"
 
 	"github.com/jackc/pgx/v5"
@@ -816,6 +818,499 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestCount(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postgresContainer, databaseURL := tests.CreateFilmotekaTestPostgresContainer(ctx)
+	defer tests.TerminateFilmotekaTestContainer(ctx, postgresContainer)
+
+	postgresClient, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		t.Errorf("error with starting postgres client: %s", err.Error())
+	}
+	defer postgresClient.Close()
+
+	store := New(postgresClient)
+
+	var ids []int
+	for _, title := range []string{"Drive", "La La Land"} {
+		movie, err := store.Create(ctx, CreateEntity{
+			Title:       title,
+			ReleaseDate: time.Date(2011, time.November, 3, 0, 0, 0, 0, time.UTC),
+			Rating:      10,
+		})
+		if err != nil {
+			t.Errorf("error with creating test data: %s", err.Error())
+		}
+		ids = append(ids, movie.ID)
+	}
+	if err := store.Delete(ctx, ids[1]); err != nil {
+		t.Errorf("error with deleting test data: %s", err.Error())
+	}
+
+	count, err := store.Count(ctx)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if count != 1 {
+		t.Errorf("wrong count. Expected 1 but got %d", count)
+	}
+}
+
+func TestQueryDeadline(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postgresContainer, databaseURL := tests.CreateFilmotekaTestPostgresContainer(ctx)
+	defer tests.TerminateFilmotekaTestContainer(ctx, postgresContainer)
+
+	postgresClient, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		t.Errorf("error with starting postgres client: %s", err.Error())
+	}
+	defer postgresClient.Close()
+
+	// the lock keeps the query waiting until its deadline
+	tx, err := postgresClient.Begin(ctx)
+	if err != nil {
+		t.Fatalf("error with starting transaction: %s", err.Error())
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+	if _, err := tx.Exec(ctx, "LOCK TABLE movies IN ACCESS EXCLUSIVE MODE"); err != nil {
+		t.Fatalf("error with locking movies: %s", err.Error())
+	}
+
+	store := New(postgresClient)
+
+	queryCtx, queryCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer queryCancel()
+
+	start := time.Now()
+	_, err = store.GetAll(queryCtx, GetAllParams{Limit: 10})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wrong error. Expected %v but got %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("query is not aborted at the deadline, it took %s", elapsed)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postgresContainer, databaseURL := tests.CreateFilmotekaTestPostgresContainer(ctx)
+	defer tests.TerminateFilmotekaTestContainer(ctx, postgresContainer)
+
+	postgresClient, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		t.Errorf("error with starting postgres client: %s", err.Error())
+	}
+	defer postgresClient.Close()
+
+	starsID, err := addExistingStars(ctx, postgresClient)
+	if err != nil {
+		t.Errorf("error with adding existing data: %s", err.Error())
+	}
+
+	store := New(postgresClient)
+
+	movie, err := store.Create(ctx, CreateEntity{
+		Title:       "Drive",
+		Description: "I'm giving you a night call to tell you how I feel (We'll go all, all, all night long)",
+		ReleaseDate: time.Date(2011, time.November, 3, 0, 0, 0, 0, time.UTC),
+		Rating:      10,
+		StarsID:     starsID[:2],
+	})
+	if err != nil {
+		t.Errorf("error with creating test movie: %s", err.Error())
+	}
+
+	newTitle := "Drive 2"
+
+	for _, test := range []struct {
+		Name    string
+		Ops     []BatchOperation
+		Atomic  bool
+		Failed  []bool
+		WantErr bool
+		Err     string
+	}{
+		{
+			Name: "Atomic batch with non-existent movie is aborted",
+			Ops: []BatchOperation{
+				{
+					Op: core.BatchCreateOp,
+					Create: CreateEntity{
+						Title:       "La La Land",
+						Description: "City of stars",
+						ReleaseDate: time.Date(2016, time.December, 9, 0, 0, 0, 0, time.UTC),
+						Rating:      8,
+						StarsID:     starsID[:1],
+					},
+				},
+				{Op: core.BatchDeleteOp, ID: 100},
+			},
+			Atomic:  true,
+			Failed:  []bool{false, true},
+			WantErr: true,
+			Err:     core.ErrBatchAborted.Error(),
+		},
+		{
+			Name: "Non-atomic batch isolates failed operations",
+			Ops: []BatchOperation{
+				{
+					Op: core.BatchCreateOp,
+					Create: CreateEntity{
+						Title:       "Dune",
+						Description: "Spice",
+						ReleaseDate: time.Date(2021, time.September, 3, 0, 0, 0, 0, time.UTC),
+						Rating:      8,
+						StarsID:     []int{starsID[3], starsID[4]},
+					},
+				},
+				{
+					Op: core.BatchCreateOp,
+					Create: CreateEntity{
+						Title:       "Broken",
+						Description: "Star does not exist",
+						ReleaseDate: time.Date(2021, time.September, 3, 0, 0, 0, 0, time.UTC),
+						Rating:      1,
+						StarsID:     []int{100},
+					},
+				},
+				{Op: core.BatchUpdateOp, ID: movie.ID, Update: UpdateEntity{Title: &newTitle, StarsID: starsID[2:3]}},
+			},
+			Failed: []bool{false, true, false},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			results, err := store.Batch(ctx, test.Ops, test.Atomic)
+			if err != nil {
+				if !test.WantErr {
+					t.Errorf("unexpected error: %s", err.Error())
+				}
+				if err.Error() != test.Err {
+					t.Errorf("unexpected error. Expected %q but got %q", test.Err, err.Error())
+				}
+			} else if test.WantErr {
+				t.Errorf("expected error but nothing got")
+			}
+			if len(results) != len(test.Failed) {
+				t.Fatalf("wrong results count. Expected %d but got %d", len(test.Failed), len(results))
+			}
+			for i, failed := range test.Failed {
+				if failed != (results[i].Err != nil) {
+					t.Errorf("wrong result of operation %d. Expected failed %t but got error %v", i, failed, results[i].Err)
+				}
+			}
+		})
+	}
+
+	updated, err := store.GetByID(ctx, movie.ID)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if updated.Title != newTitle {
+		t.Errorf("wrong title. Expected %q but got %q", newTitle, updated.Title)
+	}
+
+	stars, err := getStarsNameByMovieID(ctx, movie.ID, postgresClient)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if len(stars) != 1 || stars[0].id != starsID[2] {
+		t.Errorf("wrong stars. Expected only %d but got %v", starsID[2], stars)
+	}
+
+	data, err := store.GetAll(ctx, GetAllParams{Limit: 10})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if data.TotalCount != 2 {
+		t.Errorf("wrong total count. Expected %d but got %d", 2, data.TotalCount)
+	}
+}
+
+func TestExport(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postgresContainer, databaseURL := tests.CreateFilmotekaTestPostgresContainer(ctx)
+	defer tests.TerminateFilmotekaTestContainer(ctx, postgresContainer)
+
+	postgresClient, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		t.Errorf("error with starting postgres client: %s", err.Error())
+	}
+	defer postgresClient.Close()
+
+	starsID, err := addExistingStars(ctx, postgresClient)
+	if err != nil {
+		t.Errorf("error with adding existing data: %s", err.Error())
+	}
+
+	store := New(postgresClient)
+
+	externalID := "tt0780504"
+	var duneID int
+	for _, data := range []CreateEntity{
+		{
+			ExternalID:  &externalID,
+			Title:       "Drive",
+			Description: "I'm giving you a night call to tell you how I feel (We'll go all, all, all night long)",
+			ReleaseDate: time.Date(2011, time.November, 3, 0, 0, 0, 0, time.UTC),
+			Rating:      10,
+			StarsID:     []int{starsID[0]},
+		},
+		{
+			Title:       "La La Land",
+			Description: "City of stars",
+			ReleaseDate: time.Date(2016, time.December, 9, 0, 0, 0, 0, time.UTC),
+			Rating:      8,
+			StarsID:     []int{starsID[0], starsID[2]},
+		},
+		{
+			Title:       "Dune",
+			Description: "Fear is the mind-killer",
+			ReleaseDate: time.Date(2021, time.September, 3, 0, 0, 0, 0, time.UTC),
+			Rating:      9,
+			StarsID:     []int{starsID[3], starsID[4]},
+		},
+	} {
+		created, err := store.Create(ctx, data)
+		if err != nil {
+			t.Errorf("error with creating movie: %s", err.Error())
+		}
+		if created.Title == "Dune" {
+			duneID = created.ID
+		}
+	}
+
+	if _, err := store.UpsertTranslation(ctx, TranslationEntity{MovieID: duneID, Locale: "ru", Title: "Дюна", Description: "Страх убивает разум"}); err != nil {
+		t.Errorf("error with adding translation: %s", err.Error())
+	}
+
+	for _, test := range []struct {
+		Name   string
+		Params GetAllParams
+		Titles []string
+	}{
+		{
+			Name:   "Export sorted by rating by default",
+			Titles: []string{"Drive", "Dune", "La La Land"},
+		},
+		{
+			Name:   "Export sorted by title",
+			Params: GetAllParams{SortBy: "title", SortOrder: "asc"},
+			Titles: []string{"Drive", "Dune", "La La Land"},
+		},
+		{
+			Name:   "Export movies of a star once",
+			Params: GetAllParams{SearchTerm: "o", SortBy: "release_date", SortOrder: "desc"},
+			Titles: []string{"Dune", "La La Land", "Drive"},
+		},
+		{
+			Name:   "Export by search term",
+			Params: GetAllParams{SearchTerm: "emma"},
+			Titles: []string{"La La Land"},
+		},
+		{
+			Name:   "Export translated",
+			Params: GetAllParams{Locales: []string{"ru"}},
+			Titles: []string{"Drive", "Дюна", "La La Land"},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			var movies []ExportEntity
+			err := store.Export(ctx, test.Params, func(movie ExportEntity) error {
+				movies = append(movies, movie)
+				return nil
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %s", err.Error())
+				return
+			}
+
+			if len(movies) != len(test.Titles) {
+				t.Errorf("wrong number of movies. Expected %d but got %d", len(test.Titles), len(movies))
+				return
+			}
+			for i, movie := range movies {
+				if movie.Title != test.Titles[i] {
+					t.Errorf("wrong title. Expected %q but got %q", test.Titles[i], movie.Title)
+				}
+				if len(movie.StarsID) == 0 {
+					t.Errorf("expected stars of %q", movie.Title)
+				}
+				if movie.Title == "Drive" && (movie.ExternalID == nil || *movie.ExternalID != externalID) {
+					t.Errorf("wrong external id of %q", movie.Title)
+				}
+				if movie.Title == "Дюна" && movie.Locale != "ru" {
+					t.Errorf("wrong locale of %q. Expected ru but got %q", movie.Title, movie.Locale)
+				}
+			}
+		})
+	}
+}
+
+func TestTranslations(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postgresContainer, databaseURL := tests.CreateFilmotekaTestPostgresContainer(ctx)
+	defer tests.TerminateFilmotekaTestContainer(ctx, postgresContainer)
+
+	postgresClient, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		t.Errorf("error with starting postgres client: %s", err.Error())
+	}
+	defer postgresClient.Close()
+
+	starsID, err := addExistingStars(ctx, postgresClient)
+	if err != nil {
+		t.Fatalf("error with adding existing data: %s", err.Error())
+	}
+
+	store := New(postgresClient)
+
+	var ids []int
+	for _, title := range []string{"Драйв", "Ла-Ла Ленд"} {
+		movie, err := store.Create(ctx, CreateEntity{
+			Title:       title,
+			Description: title,
+			ReleaseDate: time.Date(2011, time.November, 3, 0, 0, 0, 0, time.UTC),
+			Rating:      10,
+			StarsID:     starsID[:1],
+		})
+		if err != nil {
+			t.Fatalf("error with creating test data: %s", err.Error())
+		}
+		ids = append(ids, movie.ID)
+	}
+
+	for _, translation := range []TranslationEntity{
+		{MovieID: ids[0], Locale: "en", Title: "Drive (draft)", Description: "A stuntman"},
+		{MovieID: ids[0], Locale: "en", Title: "Drive", Description: "A stuntman"},
+		{MovieID: ids[0], Locale: "de", Title: "Drive", Description: "Ein Stuntman"},
+		{MovieID: ids[1], Locale: "de", Title: "La La Land", Description: "Ein Musical"},
+	} {
+		if _, err := store.UpsertTranslation(ctx, translation); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+	}
+
+	translations, err := store.GetTranslations(ctx, ids, []string{"en", "de"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if got := translations[ids[0]]; got.Locale != "en" || got.Title != "Drive" {
+		t.Errorf("wrong translation. Expected the upserted english one but got %+v", got)
+	}
+	if got := translations[ids[1]]; got.Locale != "de" {
+		t.Errorf("wrong translation. Expected the german one but got %+v", got)
+	}
+
+	movies, err := store.GetAll(ctx, GetAllParams{SearchTerm: "drive", Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(movies.Movies) != 1 || movies.Movies[0].ID != ids[0] {
+		t.Errorf("wrong search result. Expected the movie translated as Drive but got %+v", movies.Movies)
+	}
+
+	if err := store.DeleteTranslation(ctx, ids[0], "en"); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := store.DeleteTranslation(ctx, ids[0], "en"); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("wrong error. Expected %v but got %v", pgx.ErrNoRows, err)
+	}
+
+	translations, err = store.GetTranslations(ctx, ids[:1], []string{"en", "de"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if got := translations[ids[0]]; got.Locale != "de" {
+		t.Errorf("wrong translation. Expected the german one but got %+v", got)
+	}
+}
+
+func TestAliases(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postgresContainer, databaseURL := tests.CreateFilmotekaTestPostgresContainer(ctx)
+	defer tests.TerminateFilmotekaTestContainer(ctx, postgresContainer)
+
+	postgresClient, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		t.Errorf("error with starting postgres client: %s", err.Error())
+	}
+	defer postgresClient.Close()
+
+	store := New(postgresClient)
+
+	// the movie has no stars, so that it is found by its alias only
+	movie, err := store.Create(ctx, CreateEntity{
+		Title:       "Крёстный отец",
+		Description: "Семья",
+		ReleaseDate: time.Date(1972, time.March, 14, 0, 0, 0, 0, time.UTC),
+		Rating:      10,
+	})
+	if err != nil {
+		t.Fatalf("error with creating test data: %s", err.Error())
+	}
+
+	var aliasesID []int
+	for _, alias := range []AliasEntity{
+		{MovieID: movie.ID, Alias: "The Godfather", Kind: "original"},
+		{MovieID: movie.ID, Alias: "Mario Puzo's The Godfather", Kind: "working"},
+	} {
+		created, err := store.CreateAlias(ctx, alias)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		aliasesID = append(aliasesID, created.ID)
+	}
+
+	if _, err := store.CreateAlias(ctx, AliasEntity{MovieID: movie.ID, Alias: "The Godfather", Kind: "regional"}); !errors.Is(err, core.ErrAliasExists) {
+		t.Errorf("wrong error. Expected %v but got %v", core.ErrAliasExists, err)
+	}
+
+	aliases, err := store.GetAliases(ctx, movie.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(aliases) != 2 || aliases[0].Alias != "The Godfather" || aliases[1].Kind != "working" {
+		t.Errorf("wrong aliases. Expected the two created ones but got %+v", aliases)
+	}
+
+	for _, test := range []struct {
+		In   string
+		Want string
+	}{
+		{In: "godfather", Want: "The Godfather"},
+		{In: "puzo", Want: "Mario Puzo's The Godfather"},
+		{In: "отец", Want: ""},
+	} {
+		movies, err := store.GetAll(ctx, GetAllParams{SearchTerm: test.In, Limit: 10})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if len(movies.Movies) != 1 || movies.Movies[0].MatchedAlias != test.Want {
+			t.Errorf("%q: wrong search result. Expected the movie matched by %q but got %+v", test.In, test.Want, movies.Movies)
+		}
+	}
+
+	if err := store.DeleteAlias(ctx, movie.ID, aliasesID[0]); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := store.DeleteAlias(ctx, movie.ID, aliasesID[0]); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("wrong error. Expected %v but got %v", pgx.ErrNoRows, err)
+	}
+}
+
 func addExistingStars(ctx context.Context, postgresClient *pgxpool.Pool) ([]int, error) {
 	var starsID []int
 
@@ -0,0 +1,121 @@
+package movie
+
+import (
+	"context"
+	"time"
+
+	"vk-test-task/pkg/format"
+	"vk-test-task/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TranslationEntity is the title and the description of a movie in a locale other than
+// the default one.
+type TranslationEntity struct {
+	MovieID     int
+	Locale      string
+	Title       string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (s *storeImpl) UpsertTranslation(ctx context.Context, entity TranslationEntity) (TranslationEntity, error) {
+	var translation TranslationEntity
+
+	err := s.client.QueryRow(
+		ctx,
+		`
+			INSERT INTO movie_translations (movie_id, locale, title, description, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $5)
+			ON CONFLICT (movie_id, locale)
+			DO UPDATE SET title = EXCLUDED.title, description = EXCLUDED.description, updated_at = EXCLUDED.updated_at
+			RETURNING movie_id, locale, title, description, created_at, updated_at
+		`,
+		entity.MovieID,
+		entity.Locale,
+		entity.Title,
+		entity.Description,
+		format.TimeNow(),
+	).Scan(&translation.MovieID,
+		&translation.Locale,
+		&translation.Title,
+		&translation.Description,
+		&translation.CreatedAt,
+		&translation.UpdatedAt)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "upsert movie translation",
+			"error", err.Error())
+		return TranslationEntity{}, err
+	}
+
+	return translation, nil
+}
+
+func (s *storeImpl) DeleteTranslation(ctx context.Context, movieID int, locale string) error {
+	n, err := s.client.Exec(
+		ctx,
+		`
+			DELETE FROM movie_translations
+			WHERE movie_id = $1 AND locale = $2
+		`,
+		movieID,
+		locale,
+	)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "delete movie translation",
+			"error", err.Error())
+		return err
+	}
+
+	if n.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetTranslations returns the most preferred translation of each movie, keyed by movie
+// id. Locales go by preference, movies without a translation to any of them are left out.
+func (s *storeImpl) GetTranslations(ctx context.Context, moviesID []int, locales []string) (map[int]TranslationEntity, error) {
+	translations := make(map[int]TranslationEntity, len(moviesID))
+
+	rows, err := s.client.Query(
+		ctx,
+		`
+			SELECT DISTINCT ON (movie_id) movie_id, locale, title, description, created_at, updated_at
+			FROM movie_translations
+			WHERE movie_id = ANY($1) AND locale = ANY($2)
+			ORDER BY movie_id, array_position($2, locale::TEXT)
+		`,
+		moviesID,
+		locales,
+	)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "get movie translations",
+			"error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var translation TranslationEntity
+
+		if err := rows.Scan(&translation.MovieID,
+			&translation.Locale,
+			&translation.Title,
+			&translation.Description,
+			&translation.CreatedAt,
+			&translation.UpdatedAt,
+		); err != nil {
+			logger.Log.ErrorContext(ctx, "scan movie translation",
+				"error", err.Error())
+			return nil, err
+		}
+
+		translations[translation.MovieID] = translation
+	}
+
+	return translations, rows.Err()
+}
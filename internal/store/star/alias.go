@@ -0,0 +1,117 @@
+package star
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/pkg/format"
+	"vk-test-task/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AliasEntity is another name a star is known under: a stage name or a transliteration.
+type AliasEntity struct {
+	ID        int
+	StarID    int
+	Alias     string
+	Kind      string
+	CreatedAt time.Time
+}
+
+func (s *storeImpl) CreateAlias(ctx context.Context, entity AliasEntity) (AliasEntity, error) {
+	var alias AliasEntity
+
+	err := s.client.QueryRow(
+		ctx,
+		`
+			INSERT INTO star_aliases (star_id, alias, kind, created_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (star_id, alias) DO NOTHING
+			RETURNING id, star_id, alias, kind, created_at
+		`,
+		entity.StarID,
+		entity.Alias,
+		entity.Kind,
+		format.TimeNow(),
+	).Scan(&alias.ID,
+		&alias.StarID,
+		&alias.Alias,
+		&alias.Kind,
+		&alias.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return AliasEntity{}, core.ErrAliasExists
+	}
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "create star alias",
+			"error", err.Error())
+		return AliasEntity{}, err
+	}
+
+	return alias, nil
+}
+
+func (s *storeImpl) GetAliases(ctx context.Context, starID int) ([]AliasEntity, error) {
+	aliases := []AliasEntity{}
+
+	rows, err := s.client.Query(
+		ctx,
+		`
+			SELECT id, star_id, alias, kind, created_at
+			FROM star_aliases
+			WHERE star_id = $1
+			ORDER BY id
+		`,
+		starID,
+	)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "get star aliases",
+			"error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var alias AliasEntity
+
+		if err := rows.Scan(&alias.ID,
+			&alias.StarID,
+			&alias.Alias,
+			&alias.Kind,
+			&alias.CreatedAt,
+		); err != nil {
+			logger.Log.ErrorContext(ctx, "scan star alias",
+				"error", err.Error())
+			return nil, err
+		}
+
+		aliases = append(aliases, alias)
+	}
+
+	return aliases, rows.Err()
+}
+
+func (s *storeImpl) DeleteAlias(ctx context.Context, starID, aliasID int) error {
+	n, err := s.client.Exec(
+		ctx,
+		`
+			DELETE FROM star_aliases
+			WHERE id = $1 AND star_id = $2
+		`,
+		aliasID,
+		starID,
+	)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "delete star alias",
+			"error", err.Error())
+		return err
+	}
+
+	if n.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package star
+
+import (
+	"context"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/pkg/format"
+	"vk-test-task/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type (
+	BatchOperation struct {
+		Op     string
+		ID     int
+		Create CreateEntity
+		Update UpdateEntity
+	}
+
+	BatchResult struct {
+		Entity Entity
+		Err    error
+	}
+)
+
+// Batch applies operations in a single transaction. In atomic mode any failed
+// operation rolls back the whole batch, otherwise failed operations are retried
+// one by one so that the rest of the batch is still applied.
+func (s *storeImpl) Batch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchResult, error) {
+	tx, err := s.client.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := s.execBatchInTx(ctx, ops, tx)
+	if err == nil && atomic && hasFailed(results) {
+		err = core.ErrBatchAborted
+	}
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "execute stars batch",
+			"error", err.Error())
+		if txErr := tx.Rollback(ctx); txErr != nil {
+			logger.Log.ErrorContext(ctx, "rollback",
+				"error", txErr.Error())
+			return nil, txErr
+		}
+		if atomic {
+			return results, err
+		}
+		return s.execEach(ctx, ops)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "committing batch transaction",
+			"error", err.Error())
+	}
+
+	return results, err
+}
+
+func (s *storeImpl) execEach(ctx context.Context, ops []BatchOperation) ([]BatchResult, error) {
+	tx, err := s.client.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchResult, len(ops))
+	for i, op := range ops {
+		results[i] = s.execOneInTx(ctx, op, tx)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "committing batch transaction",
+			"error", err.Error())
+	}
+
+	return results, err
+}
+
+// execOneInTx runs a single operation under a savepoint, so its failure
+// does not abort the enclosing transaction.
+func (s *storeImpl) execOneInTx(ctx context.Context, op BatchOperation, tx pgx.Tx) BatchResult {
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return BatchResult{Err: err}
+	}
+
+	results, err := s.execBatchInTx(ctx, []BatchOperation{op}, savepoint)
+	if err != nil {
+		if txErr := savepoint.Rollback(ctx); txErr != nil {
+			logger.Log.ErrorContext(ctx, "rollback",
+				"error", txErr.Error())
+		}
+		return BatchResult{Err: err}
+	}
+
+	if err := savepoint.Commit(ctx); err != nil {
+		return BatchResult{Err: err}
+	}
+
+	return results[0]
+}
+
+func (s *storeImpl) execBatchInTx(ctx context.Context, ops []BatchOperation, tx pgx.Tx) ([]BatchResult, error) {
+	batch := &pgx.Batch{}
+	for _, op := range ops {
+		switch op.Op {
+		case core.BatchCreateOp:
+			batch.Queue(
+				`
+					INSERT INTO stars (external_id, name, sex, birth_date, created_at, updated_at)
+					VALUES ($1, $2, $3, $4, $5, $6)
+					RETURNING id, name, sex, birth_date, created_at, updated_at, deleted_at
+				`,
+				op.Create.ExternalID,
+				op.Create.Name,
+				op.Create.Sex,
+				op.Create.BirthDate,
+				format.TimeNow(),
+				format.TimeNow(),
+			)
+		case core.BatchUpdateOp:
+			batch.Queue(
+				`
+					UPDATE stars
+					SET name = COALESCE($1, name),
+						sex = COALESCE($2, sex),
+						birth_date = COALESCE($3, birth_date),
+						updated_at = $4
+					WHERE id = $5 AND deleted_at IS NULL
+					RETURNING id, name, sex, birth_date, created_at, updated_at, deleted_at
+				`,
+				op.Update.Name,
+				op.Update.Sex,
+				op.Update.BirthDate,
+				format.TimeNow(),
+				op.ID,
+			)
+		case core.BatchDeleteOp:
+			batch.Queue(
+				`
+					UPDATE stars
+					SET updated_at = $1, deleted_at = $2
+					WHERE id = $3 AND deleted_at IS NULL
+					RETURNING id, name, sex, birth_date, created_at, updated_at, deleted_at
+				`,
+				format.TimeNow(),
+				format.TimeNow(),
+				op.ID,
+			)
+		}
+	}
+
+	results := make([]BatchResult, len(ops))
+
+	batchResults := tx.SendBatch(ctx, batch)
+	defer batchResults.Close()
+
+	for i := range ops {
+		var star Entity
+
+		err := batchResults.QueryRow().Scan(&star.ID,
+			&star.Name,
+			&star.Sex,
+			&star.BirthDate,
+			&star.CreatedAt,
+			&star.UpdatedAt,
+			&star.DeletedAt)
+		switch err {
+		case nil:
+			results[i].Entity = star
+		case pgx.ErrNoRows:
+			results[i].Err = err
+		default:
+			return nil, err
+		}
+	}
+
+	return results, batchResults.Close()
+}
+
+func hasFailed(results []BatchResult) bool {
+	for _, result := range results {
+		if result.Err != nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,56 @@
+package star
+
+import (
+	"context"
+
+	"vk-test-task/pkg/logger"
+)
+
+type ExportEntity struct {
+	Entity
+	ExternalID *string
+}
+
+// Export passes all stars to fn ordered by id. Rows are scanned one by one
+// as pgx reads them from the connection.
+func (s *storeImpl) Export(ctx context.Context, fn func(ExportEntity) error) error {
+	rows, err := s.client.Query(
+		ctx,
+		`
+			SELECT id, external_id, name, sex, birth_date, created_at, updated_at, deleted_at
+			FROM stars
+			WHERE deleted_at IS NULL
+			ORDER BY id
+		`,
+	)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "export stars",
+			"error", err.Error())
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var star ExportEntity
+
+		if err := rows.Scan(&star.ID,
+			&star.ExternalID,
+			&star.Name,
+			&star.Sex,
+			&star.BirthDate,
+			&star.CreatedAt,
+			&star.UpdatedAt,
+			&star.DeletedAt,
+		); err != nil {
+			logger.Log.ErrorContext(ctx, "scan star",
+				"error", err.Error())
+			return err
+		}
+
+		if err := fn(star); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
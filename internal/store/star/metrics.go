@@ -0,0 +1,95 @@
+package star
+
+import (
+	"context"
+	"time"
+
+	"vk-test-task/pkg/metrics"
+)
+
+const metricsName = "star"
+
+// instrumentedStore records the duration of every call to the wrapped store.
+type instrumentedStore struct {
+	store Store
+}
+
+// WithMetrics wraps the store to report query durations per method.
+func WithMetrics(store Store) Store {
+	return &instrumentedStore{store: store}
+}
+
+func (s *instrumentedStore) Create(ctx context.Context, entity CreateEntity) (Entity, error) {
+	defer metrics.ObserveQuery(metricsName, "Create", time.Now())
+	return s.store.Create(ctx, entity)
+}
+
+func (s *instrumentedStore) GetByID(ctx context.Context, id int) (Entity, error) {
+	defer metrics.ObserveQuery(metricsName, "GetByID", time.Now())
+	return s.store.GetByID(ctx, id)
+}
+
+func (s *instrumentedStore) GetAll(ctx context.Context, params GetAllParams) (EntityWithTotalCount, error) {
+	defer metrics.ObserveQuery(metricsName, "GetAll", time.Now())
+	return s.store.GetAll(ctx, params)
+}
+
+func (s *instrumentedStore) Update(ctx context.Context, id int, entity UpdateEntity) (Entity, error) {
+	defer metrics.ObserveQuery(metricsName, "Update", time.Now())
+	return s.store.Update(ctx, id, entity)
+}
+
+func (s *instrumentedStore) Delete(ctx context.Context, id int) error {
+	defer metrics.ObserveQuery(metricsName, "Delete", time.Now())
+	return s.store.Delete(ctx, id)
+}
+
+func (s *instrumentedStore) CheckExistence(ctx context.Context, id int) (bool, error) {
+	defer metrics.ObserveQuery(metricsName, "CheckExistence", time.Now())
+	return s.store.CheckExistence(ctx, id)
+}
+
+func (s *instrumentedStore) GetExistingIDs(ctx context.Context, ids []int) (map[int]struct{}, error) {
+	defer metrics.ObserveQuery(metricsName, "GetExistingIDs", time.Now())
+	return s.store.GetExistingIDs(ctx, ids)
+}
+
+func (s *instrumentedStore) GetIDsByExternalIDs(ctx context.Context, externalIDs []string) (map[string]int, error) {
+	defer metrics.ObserveQuery(metricsName, "GetIDsByExternalIDs", time.Now())
+	return s.store.GetIDsByExternalIDs(ctx, externalIDs)
+}
+
+func (s *instrumentedStore) Batch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchResult, error) {
+	defer metrics.ObserveQuery(metricsName, "Batch", time.Now())
+	return s.store.Batch(ctx, ops, atomic)
+}
+
+func (s *instrumentedStore) Export(ctx context.Context, fn func(ExportEntity) error) error {
+	defer metrics.ObserveQuery(metricsName, "Export", time.Now())
+	return s.store.Export(ctx, fn)
+}
+
+func (s *instrumentedStore) GetByMovieIDs(ctx context.Context, moviesID []int) (map[int][]Entity, error) {
+	defer metrics.ObserveQuery(metricsName, "GetByMovieIDs", time.Now())
+	return s.store.GetByMovieIDs(ctx, moviesID)
+}
+
+func (s *instrumentedStore) Count(ctx context.Context) (int, error) {
+	defer metrics.ObserveQuery(metricsName, "Count", time.Now())
+	return s.store.Count(ctx)
+}
+
+func (s *instrumentedStore) CreateAlias(ctx context.Context, entity AliasEntity) (AliasEntity, error) {
+	defer metrics.ObserveQuery(metricsName, "CreateAlias", time.Now())
+	return s.store.CreateAlias(ctx, entity)
+}
+
+func (s *instrumentedStore) GetAliases(ctx context.Context, starID int) ([]AliasEntity, error) {
+	defer metrics.ObserveQuery(metricsName, "GetAliases", time.Now())
+	return s.store.GetAliases(ctx, starID)
+}
+
+func (s *instrumentedStore) DeleteAlias(ctx context.Context, starID, aliasID int) error {
+	defer metrics.ObserveQuery(metricsName, "DeleteAlias", time.Now())
+	return s.store.DeleteAlias(ctx, starID, aliasID)
+}
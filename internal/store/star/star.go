@@ -19,6 +19,15 @@ type (
 		Update(context.Context, int, UpdateEntity) (Entity, error)
 		Delete(context.Context, int) error
 		CheckExistence(context.Context, int) (bool, error)
+		GetExistingIDs(context.Context, []int) (map[int]struct{}, error)
+		GetIDsByExternalIDs(context.Context, []string) (map[string]int, error)
+		Batch(context.Context, []BatchOperation, bool) ([]BatchResult, error)
+		Export(context.Context, func(ExportEntity) error) error
+		GetByMovieIDs(context.Context, []int) (map[int][]Entity, error)
+		Count(context.Context) (int, error)
+		CreateAlias(context.Context, AliasEntity) (AliasEntity, error)
+		GetAliases(context.Context, int) ([]AliasEntity, error)
+		DeleteAlias(context.Context, int, int) error
 	}
 
 	storeImpl struct {
@@ -26,14 +35,16 @@ type (
 	}
 
 	GetAllParams struct {
-		Limit  int
-		Offset int
+		SearchTerm string
+		Limit      int
+		Offset     int
 	}
 
 	CreateEntity struct {
-		Name      string
-		Sex       string
-		BirthDate time.Time
+		ExternalID *string
+		Name       string
+		Sex        string
+		BirthDate  time.Time
 	}
 
 	UpdateEntity struct {
@@ -50,6 +61,8 @@ type (
 		CreatedAt time.Time
 		UpdatedAt time.Time
 		DeletedAt *time.Time
+		// MatchedAlias is the alias the search term of GetAll matched, if any
+		MatchedAlias string
 	}
 
 	EntityWithTotalCount struct {
@@ -70,10 +83,11 @@ func (s *storeImpl) Create(ctx context.Context, entity CreateEntity) (Entity, er
 	err := s.client.QueryRow(
 		ctx,
 		`
-			INSERT INTO stars (name, sex, birth_date, created_at, updated_at)
-			VALUES ($1, $2, $3, $4, $5)
+			INSERT INTO stars (external_id, name, sex, birth_date, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
 			RETURNING id, name, sex, birth_date, created_at, updated_at
 		`,
+		entity.ExternalID,
 		entity.Name,
 		entity.Sex,
 		entity.BirthDate,
@@ -86,7 +100,7 @@ func (s *storeImpl) Create(ctx context.Context, entity CreateEntity) (Entity, er
 		&newStar.CreatedAt,
 		&newStar.UpdatedAt)
 	if err != nil {
-		logger.Log.Error("create new star",
+		logger.Log.ErrorContext(ctx, "create new star",
 			"error", err.Error())
 	}
 
@@ -112,31 +126,87 @@ func (s *storeImpl) GetByID(ctx context.Context, id int) (Entity, error) {
 		&star.UpdatedAt,
 		&star.DeletedAt)
 	if err != nil {
-		logger.Log.Error("get star by id",
+		logger.Log.ErrorContext(ctx, "get star by id",
 			"error", err.Error())
 	}
 
 	return star, err
 }
 
+// GetByMovieIDs returns stars of several movies with one query, keyed by movie id.
+func (s *storeImpl) GetByMovieIDs(ctx context.Context, moviesID []int) (map[int][]Entity, error) {
+	stars := make(map[int][]Entity, len(moviesID))
+
+	rows, err := s.client.Query(
+		ctx,
+		`
+			SELECT ms.movie_id, s.id, s.name, s.sex, s.birth_date, s.created_at, s.updated_at, s.deleted_at
+			FROM stars s
+			JOIN movie_stars ms ON s.id = ms.star_id
+			WHERE ms.movie_id = ANY($1)
+			ORDER BY s.id
+		`,
+		moviesID,
+	)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "get stars by movie ids",
+			"error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			movieID int
+			star    Entity
+		)
+
+		if err := rows.Scan(&movieID,
+			&star.ID,
+			&star.Name,
+			&star.Sex,
+			&star.BirthDate,
+			&star.CreatedAt,
+			&star.UpdatedAt,
+			&star.DeletedAt,
+		); err != nil {
+			logger.Log.ErrorContext(ctx, "scan star",
+				"error", err.Error())
+			return nil, err
+		}
+
+		stars[movieID] = append(stars[movieID], star)
+	}
+
+	return stars, rows.Err()
+}
+
 func (s *storeImpl) GetAll(ctx context.Context, params GetAllParams) (EntityWithTotalCount, error) {
 	var entities EntityWithTotalCount
 
 	rows, err := s.client.Query(
 		ctx,
 		`
-			SELECT id, name, sex, birth_date, created_at, updated_at, deleted_at, COUNT(*) OVER() AS total
-			FROM stars
-			WHERE deleted_at IS NULL
-			ORDER BY id DESC
+			SELECT s.id, s.name, s.sex, s.birth_date, s.created_at, s.updated_at, s.deleted_at, COUNT(*) OVER() AS total,
+				CASE WHEN $3 <> '' THEN (
+					SELECT sa.alias FROM star_aliases sa WHERE sa.star_id = s.id AND sa.alias ILIKE '%' || $3 || '%' ORDER BY sa.id LIMIT 1
+				) END AS matched_alias
+			FROM stars s
+			WHERE s.deleted_at IS NULL AND (
+				$3 = ''
+				OR s.name ILIKE '%' || $3 || '%'
+				OR EXISTS (SELECT 1 FROM star_aliases sa WHERE sa.star_id = s.id AND sa.alias ILIKE '%' || $3 || '%')
+			)
+			ORDER BY s.id DESC
 			LIMIT $1
 			OFFSET $2
 		`,
 		params.Limit,
 		params.Offset,
+		params.SearchTerm,
 	)
 	if err != nil {
-		logger.Log.Error("get stars",
+		logger.Log.ErrorContext(ctx, "get stars",
 			"error", err.Error())
 		return EntityWithTotalCount{}, err
 	}
@@ -145,6 +215,7 @@ func (s *storeImpl) GetAll(ctx context.Context, params GetAllParams) (EntityWith
 	for rows.Next() {
 		var entity Entity
 		var total int
+		var matchedAlias *string
 
 		if err := rows.Scan(
 			&entity.ID,
@@ -155,11 +226,15 @@ func (s *storeImpl) GetAll(ctx context.Context, params GetAllParams) (EntityWith
 			&entity.UpdatedAt,
 			&entity.DeletedAt,
 			&total,
+			&matchedAlias,
 		); err != nil {
-			logger.Log.Error("scan star",
+			logger.Log.ErrorContext(ctx, "scan star",
 				"error", err.Error())
 			return EntityWithTotalCount{}, err
 		}
+		if matchedAlias != nil {
+			entity.MatchedAlias = *matchedAlias
+		}
 
 		entities.Stars = append(entities.Stars, entity)
 		entities.TotalCount = total
@@ -171,7 +246,7 @@ func (s *storeImpl) GetAll(ctx context.Context, params GetAllParams) (EntityWith
 func (s *storeImpl) Update(ctx context.Context, id int, entity UpdateEntity) (Entity, error) {
 	item, err := s.GetByID(ctx, id)
 	if err != nil {
-		logger.Log.Error("get star by id",
+		logger.Log.ErrorContext(ctx, "get star by id",
 			"error", err.Error())
 		return Entity{}, err
 	}
@@ -206,7 +281,7 @@ func (s *storeImpl) Update(ctx context.Context, id int, entity UpdateEntity) (En
 		&updatedStar.UpdatedAt,
 		&updatedStar.DeletedAt)
 	if err != nil {
-		logger.Log.Error("update star",
+		logger.Log.ErrorContext(ctx, "update star",
 			"error", err.Error())
 	}
 
@@ -226,7 +301,7 @@ func (s *storeImpl) Delete(ctx context.Context, id int) error {
 		id,
 	)
 	if err != nil {
-		logger.Log.Error("delete star",
+		logger.Log.ErrorContext(ctx, "delete star",
 			"error", err.Error())
 		return err
 	}
@@ -253,9 +328,99 @@ func (s *storeImpl) CheckExistence(ctx context.Context, id int) (bool, error) {
 		id,
 	).Scan(&exists)
 	if err != nil {
-		logger.Log.Error("check star existence",
+		logger.Log.ErrorContext(ctx, "check star existence",
 			"error", err.Error())
 	}
 
 	return exists, err
 }
+
+func (s *storeImpl) GetExistingIDs(ctx context.Context, ids []int) (map[int]struct{}, error) {
+	existing := make(map[int]struct{}, len(ids))
+
+	rows, err := s.client.Query(
+		ctx,
+		`
+            SELECT id
+            FROM stars
+            WHERE id = ANY($1)
+        `,
+		ids,
+	)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "get existing stars",
+			"error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+
+		if err := rows.Scan(&id); err != nil {
+			logger.Log.ErrorContext(ctx, "scan star",
+				"error", err.Error())
+			return nil, err
+		}
+
+		existing[id] = struct{}{}
+	}
+
+	return existing, rows.Err()
+}
+
+func (s *storeImpl) Count(ctx context.Context) (int, error) {
+	var count int
+
+	err := s.client.QueryRow(
+		ctx,
+		`
+			SELECT COUNT(*)
+			FROM stars
+			WHERE deleted_at IS NULL
+		`,
+	).Scan(&count)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "count stars",
+			"error", err.Error())
+	}
+
+	return count, err
+}
+
+func (s *storeImpl) GetIDsByExternalIDs(ctx context.Context, externalIDs []string) (map[string]int, error) {
+	ids := make(map[string]int, len(externalIDs))
+
+	rows, err := s.client.Query(
+		ctx,
+		`
+            SELECT external_id, id
+            FROM stars
+            WHERE external_id = ANY($1)
+        `,
+		externalIDs,
+	)
+	if err != nil {
+		logger.Log.ErrorContext(ctx, "get stars by external ids",
+			"error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			externalID string
+			id         int
+		)
+
+		if err := rows.Scan(&externalID, &id); err != nil {
+			logger.Log.ErrorContext(ctx, "scan star",
+				"error", err.Error())
+			return nil, err
+		}
+
+		ids[externalID] = id
+	}
+
+	return ids, rows.Err()
+}
@@ -2,9 +2,11 @@ package star
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"vk-test-task/internal/core"
 	"vk-test-task/internal/tests"
 
 	"github.com/jackc/pgx/v5"
@@ -528,3 +530,265 @@ func TestCheckExistence(t *testing.T) {
 		})
 	}
 }
+
+func TestGetExistingIDs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postgresContainer, databaseURL := tests.CreateFilmotekaTestPostgresContainer(ctx)
+	defer tests.TerminateFilmotekaTestContainer(ctx, postgresContainer)
+
+	postgresClient, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		t.Errorf("error with starting postgres client: %s", err.Error())
+	}
+	defer postgresClient.Close()
+
+	store := New(postgresClient)
+
+	star, err := store.Create(ctx, CreateEntity{
+		Name:      "Ryan Gosling",
+		Sex:       "male",
+		BirthDate: time.Date(1980, time.November, 12, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Errorf("error with creating test data: %s", err.Error())
+	}
+
+	existing, err := store.GetExistingIDs(ctx, []int{star.ID, 100})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if _, ok := existing[star.ID]; !ok {
+		t.Errorf("star %d should exist", star.ID)
+	}
+	if _, ok := existing[100]; ok {
+		t.Error("star 100 should not exist")
+	}
+}
+
+func TestCount(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postgresContainer, databaseURL := tests.CreateFilmotekaTestPostgresContainer(ctx)
+	defer tests.TerminateFilmotekaTestContainer(ctx, postgresContainer)
+
+	postgresClient, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		t.Errorf("error with starting postgres client: %s", err.Error())
+	}
+	defer postgresClient.Close()
+
+	store := New(postgresClient)
+
+	var ids []int
+	for _, name := range []string{"Ryan Gosling", "Carey Mulligan"} {
+		star, err := store.Create(ctx, CreateEntity{
+			Name:      name,
+			Sex:       "male",
+			BirthDate: time.Date(1980, time.November, 12, 0, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			t.Errorf("error with creating test data: %s", err.Error())
+		}
+		ids = append(ids, star.ID)
+	}
+	if err := store.Delete(ctx, ids[1]); err != nil {
+		t.Errorf("error with deleting test data: %s", err.Error())
+	}
+
+	count, err := store.Count(ctx)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if count != 1 {
+		t.Errorf("wrong count. Expected 1 but got %d", count)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postgresContainer, databaseURL := tests.CreateFilmotekaTestPostgresContainer(ctx)
+	defer tests.TerminateFilmotekaTestContainer(ctx, postgresContainer)
+
+	postgresClient, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		t.Errorf("error with starting postgres client: %s", err.Error())
+	}
+	defer postgresClient.Close()
+
+	store := New(postgresClient)
+
+	star, err := store.Create(ctx, CreateEntity{
+		Name:      "Ryan Gosling",
+		Sex:       "male",
+		BirthDate: time.Date(1980, time.November, 12, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Errorf("error with creating test data: %s", err.Error())
+	}
+
+	newName := "Raisa Goslingova"
+
+	for _, test := range []struct {
+		Name    string
+		Ops     []BatchOperation
+		Atomic  bool
+		Failed  []bool
+		WantErr bool
+		Err     string
+	}{
+		{
+			Name: "Atomic batch with non-existent star is aborted",
+			Ops: []BatchOperation{
+				{
+					Op: core.BatchCreateOp,
+					Create: CreateEntity{
+						Name:      "Zendaya",
+						Sex:       "female",
+						BirthDate: time.Date(1996, time.September, 1, 0, 0, 0, 0, time.UTC),
+					},
+				},
+				{Op: core.BatchDeleteOp, ID: 100},
+			},
+			Atomic:  true,
+			Failed:  []bool{false, true},
+			WantErr: true,
+			Err:     core.ErrBatchAborted.Error(),
+		},
+		{
+			Name: "Non-atomic batch applies valid operations",
+			Ops: []BatchOperation{
+				{
+					Op: core.BatchCreateOp,
+					Create: CreateEntity{
+						Name:      "Emma Stone",
+						Sex:       "female",
+						BirthDate: time.Date(1988, time.November, 6, 0, 0, 0, 0, time.UTC),
+					},
+				},
+				{Op: core.BatchUpdateOp, ID: star.ID, Update: UpdateEntity{Name: &newName}},
+				{Op: core.BatchDeleteOp, ID: 100},
+			},
+			Failed: []bool{false, false, true},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			results, err := store.Batch(ctx, test.Ops, test.Atomic)
+			if err != nil {
+				if !test.WantErr {
+					t.Errorf("unexpected error: %s", err.Error())
+				}
+				if err.Error() != test.Err {
+					t.Errorf("unexpected error. Expected %q but got %q", test.Err, err.Error())
+				}
+			} else if test.WantErr {
+				t.Errorf("expected error but nothing got")
+			}
+			if len(results) != len(test.Failed) {
+				t.Fatalf("wrong results count. Expected %d but got %d", len(test.Failed), len(results))
+			}
+			for i, failed := range test.Failed {
+				if failed != (results[i].Err != nil) {
+					t.Errorf("wrong result of operation %d. Expected failed %t but got error %v", i, failed, results[i].Err)
+				}
+			}
+		})
+	}
+
+	updated, err := store.GetByID(ctx, star.ID)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if updated.Name != newName {
+		t.Errorf("wrong name. Expected %q but got %q", newName, updated.Name)
+	}
+
+	data, err := store.GetAll(ctx, GetAllParams{Limit: 10})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if data.TotalCount != 2 {
+		t.Errorf("wrong total count. Expected %d but got %d", 2, data.TotalCount)
+	}
+}
+
+func TestAliases(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	postgresContainer, databaseURL := tests.CreateFilmotekaTestPostgresContainer(ctx)
+	defer tests.TerminateFilmotekaTestContainer(ctx, postgresContainer)
+
+	postgresClient, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		t.Errorf("error with starting postgres client: %s", err.Error())
+	}
+	defer postgresClient.Close()
+
+	store := New(postgresClient)
+
+	var ids []int
+	for _, name := range []string{"Райан Гослинг", "Кэри Маллиган"} {
+		star, err := store.Create(ctx, CreateEntity{
+			Name:      name,
+			Sex:       "male",
+			BirthDate: time.Date(1980, time.November, 12, 0, 0, 0, 0, time.UTC),
+		})
+		if err != nil {
+			t.Fatalf("error with creating test data: %s", err.Error())
+		}
+		ids = append(ids, star.ID)
+	}
+
+	alias, err := store.CreateAlias(ctx, AliasEntity{StarID: ids[0], Alias: "Ryan Gosling", Kind: "transliteration"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if _, err := store.CreateAlias(ctx, AliasEntity{StarID: ids[0], Alias: "Ryan Gosling", Kind: "stage"}); !errors.Is(err, core.ErrAliasExists) {
+		t.Errorf("wrong error. Expected %v but got %v", core.ErrAliasExists, err)
+	}
+
+	for _, test := range []struct {
+		In        string
+		WantID    int
+		WantAlias string
+	}{
+		{In: "gosling", WantID: ids[0], WantAlias: "Ryan Gosling"},
+		{In: "маллиган", WantID: ids[1]},
+	} {
+		stars, err := store.GetAll(ctx, GetAllParams{SearchTerm: test.In, Limit: 10})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if len(stars.Stars) != 1 || stars.Stars[0].ID != test.WantID || stars.Stars[0].MatchedAlias != test.WantAlias {
+			t.Errorf("%q: wrong search result. Expected star %d matched by %q but got %+v", test.In, test.WantID, test.WantAlias, stars.Stars)
+		}
+	}
+
+	stars, err := store.GetAll(ctx, GetAllParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if stars.TotalCount != 2 {
+		t.Errorf("wrong total count. Expected 2 but got %d", stars.TotalCount)
+	}
+
+	aliases, err := store.GetAliases(ctx, ids[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(aliases) != 1 || aliases[0].ID != alias.ID {
+		t.Errorf("wrong aliases. Expected the created one but got %+v", aliases)
+	}
+
+	if err := store.DeleteAlias(ctx, ids[1], alias.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("wrong error. Expected %v but got %v", pgx.ErrNoRows, err)
+	}
+	if err := store.DeleteAlias(ctx, ids[0], alias.ID); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+}
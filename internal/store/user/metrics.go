@@ -0,0 +1,35 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"vk-test-task/pkg/metrics"
+)
+
+const metricsName = "user"
+
+// instrumentedStore records the duration of every call to the wrapped store.
+type instrumentedStore struct {
+	store Store
+}
+
+// WithMetrics wraps the store to report query durations per method.
+func WithMetrics(store Store) Store {
+	return &instrumentedStore{store: store}
+}
+
+func (s *instrumentedStore) Create(ctx context.Context, entity CreateEntity) (Entity, error) {
+	defer metrics.ObserveQuery(metricsName, "Create", time.Now())
+	return s.store.Create(ctx, entity)
+}
+
+func (s *instrumentedStore) GetPassHashAndRoleByUsername(ctx context.Context, username string) (string, string, error) {
+	defer metrics.ObserveQuery(metricsName, "GetPassHashAndRoleByUsername", time.Now())
+	return s.store.GetPassHashAndRoleByUsername(ctx, username)
+}
+
+func (s *instrumentedStore) CheckExistence(ctx context.Context, username string) (bool, error) {
+	defer metrics.ObserveQuery(metricsName, "CheckExistence", time.Now())
+	return s.store.CheckExistence(ctx, username)
+}
@@ -64,7 +64,7 @@ func (s *storeImpl) Create(ctx context.Context, entity CreateEntity) (Entity, er
 		&newUser.CreatedAt,
 		&newUser.UpdatedAt)
 	if err != nil {
-		logger.Log.Error("create new user",
+		logger.Log.ErrorContext(ctx, "create new user",
 			"error", err.Error())
 	}
 
@@ -85,7 +85,7 @@ func (s *storeImpl) GetPassHashAndRoleByUsername(ctx context.Context, username s
 		username,
 	).Scan(&passHash, &role)
 	if err != nil {
-		logger.Log.Error("get pass hash and role by username",
+		logger.Log.ErrorContext(ctx, "get pass hash and role by username",
 			"error", err.Error())
 	}
 
@@ -107,7 +107,7 @@ func (s *storeImpl) CheckExistence(ctx context.Context, username string) (bool,
 		username,
 	).Scan(&exists)
 	if err != nil {
-		logger.Log.Error("check user existence",
+		logger.Log.ErrorContext(ctx, "check user existence",
 			"error", err.Error())
 	}
 
@@ -3,9 +3,10 @@ package tests
 import (
 	"context"
 	"fmt"
-	"os"
+	"io/fs"
 	"time"
 
+	"vk-test-task/migrations"
 	"vk-test-task/pkg/logger"
 
 	"github.com/jackc/pgx/v5"
@@ -60,7 +61,7 @@ func CreateFilmotekaTestPostgresContainer(ctx context.Context) (testcontainers.C
 		panic("run migrations: " + err.Error())
 	}
 
-	if err := logger.SetupLogger("local"); err != nil {
+	if err := logger.Setup(logger.Options{Level: "debug"}); err != nil {
 		panic("setting up logger: " + err.Error())
 	}
 
@@ -85,7 +86,12 @@ func runMigrations(ctx context.Context, connString string) error {
 		return err
 	}
 
-	err = migrator.LoadMigrations(os.DirFS("../../../migrations/sql"))
+	sql, err := fs.Sub(migrations.SQL, "sql")
+	if err != nil {
+		return err
+	}
+
+	err = migrator.LoadMigrations(sql)
 	if err != nil {
 		return err
 	}
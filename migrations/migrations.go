@@ -0,0 +1,59 @@
+// Package migrations embeds the tern migrations of the database schema.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// versionTable is the table where tern keeps the applied version.
+const versionTable = "schema_version"
+
+//go:embed sql/*.sql
+var SQL embed.FS
+
+// Version returns the schema version the code expects, i.e. the sequence
+// number of the last migration.
+func Version() (int, error) {
+	files, err := fs.Glob(SQL, "sql/*.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+	for _, file := range files {
+		name := strings.TrimPrefix(file, "sql/")
+		seq, err := strconv.Atoi(strings.SplitN(name, "_", 2)[0])
+		if err != nil {
+			return 0, fmt.Errorf("migration %s: %w", name, err)
+		}
+		version = max(version, seq)
+	}
+
+	return version, nil
+}
+
+// Check fails when the database is not migrated to Version.
+func Check(ctx context.Context, db *pgxpool.Pool) error {
+	want, err := Version()
+	if err != nil {
+		return err
+	}
+
+	var got int
+	if err := db.QueryRow(ctx, "SELECT version FROM "+versionTable).Scan(&got); err != nil {
+		return fmt.Errorf("get schema version: %w", err)
+	}
+
+	if got != want {
+		return fmt.Errorf("schema version is %d, expected %d", got, want)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"io/fs"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	entries, err := fs.ReadDir(SQL, "sql")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(entries) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+
+	// ReadDir sorts by name and the sequence numbers are zero-padded, so the
+	// last file is the latest migration.
+	last := entries[len(entries)-1].Name()
+	expected, err := strconv.Atoi(strings.SplitN(last, "_", 2)[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	version, err := Version()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if version != expected {
+		t.Errorf("wrong version. Expected %d but got %d", expected, version)
+	}
+}
@@ -0,0 +1,12 @@
+ALTER TABLE stars ADD COLUMN external_id VARCHAR(64);
+ALTER TABLE movies ADD COLUMN external_id VARCHAR(64);
+
+CREATE UNIQUE INDEX idx_stars_external_id ON stars (external_id);
+CREATE UNIQUE INDEX idx_movies_external_id ON movies (external_id);
+
+---- create above / drop below ----
+
+DROP INDEX idx_movies_external_id;
+DROP INDEX idx_stars_external_id;
+ALTER TABLE movies DROP COLUMN external_id;
+ALTER TABLE stars DROP COLUMN external_id;
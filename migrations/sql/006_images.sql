@@ -0,0 +1,22 @@
+CREATE TABLE images (
+    id SERIAL PRIMARY KEY,
+    movie_id INT REFERENCES movies (id) ON DELETE CASCADE,
+    star_id INT REFERENCES stars (id) ON DELETE CASCADE,
+    kind VARCHAR(16) NOT NULL,
+    key VARCHAR(128) NOT NULL UNIQUE,
+    content_type VARCHAR(64) NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    thumbnails JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK ((movie_id IS NULL) <> (star_id IS NULL))
+);
+
+CREATE INDEX idx_images_movie_id ON images (movie_id);
+CREATE INDEX idx_images_star_id ON images (star_id);
+
+---- create above / drop below ----
+
+DROP INDEX idx_images_star_id;
+DROP INDEX idx_images_movie_id;
+DROP TABLE images;
\ No newline at end of file
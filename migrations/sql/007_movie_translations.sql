@@ -0,0 +1,13 @@
+CREATE TABLE movie_translations (
+    movie_id INT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    locale VARCHAR(8) NOT NULL,
+    title VARCHAR(150) NOT NULL,
+    description VARCHAR(1000) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (movie_id, locale)
+);
+
+---- create above / drop below ----
+
+DROP TABLE movie_translations;
\ No newline at end of file
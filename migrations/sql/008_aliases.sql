@@ -0,0 +1,22 @@
+CREATE TABLE movie_aliases (
+    id SERIAL PRIMARY KEY,
+    movie_id INT NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    alias VARCHAR(150) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (movie_id, alias)
+);
+
+CREATE TABLE star_aliases (
+    id SERIAL PRIMARY KEY,
+    star_id INT NOT NULL REFERENCES stars (id) ON DELETE CASCADE,
+    alias VARCHAR(100) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (star_id, alias)
+);
+
+---- create above / drop below ----
+
+DROP TABLE star_aliases;
+DROP TABLE movie_aliases;
@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+func (c *Client) SignUp(ctx context.Context, req SignUpRequest) (User, error) {
+	resp, err := send[User](ctx, c, http.MethodPost, apiPrefix+"/auth/signup", nil, req, false)
+	return resp.Data, err
+}
+
+// Login gets a token and uses it for the following requests.
+func (c *Client) Login(ctx context.Context, username, password string) (Token, error) {
+	body := map[string]string{"username": username, "password": password}
+
+	resp, err := send[Token](ctx, c, http.MethodPost, apiPrefix+"/auth/login", nil, body, false)
+	if err != nil {
+		return Token{}, err
+	}
+	c.setToken(resp.Data.AccessToken)
+
+	return resp.Data, nil
+}
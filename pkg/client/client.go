@@ -0,0 +1,236 @@
+// Package client is a Go client of the filmoteka REST API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"vk-test-task/internal/core"
+)
+
+const (
+	apiPrefix       = "/api/v1"
+	filmotekaPrefix = apiPrefix + "/filmoteka"
+
+	defaultRetries   = 3
+	defaultRetryWait = 200 * time.Millisecond
+)
+
+type (
+	Client struct {
+		baseURL    string
+		httpClient *http.Client
+		retries    int
+		retryWait  time.Duration
+
+		mu          sync.Mutex
+		token       string
+		credentials *credentials
+	}
+
+	Option func(*Client)
+
+	credentials struct {
+		username string
+		password string
+	}
+
+	// envelope is web.Response with typed data.
+	envelope[T any] struct {
+		Status  string          `json:"status"`
+		MsgCode string          `json:"msg_code"`
+		Data    T               `json:"data"`
+		Meta    json.RawMessage `json:"_meta"`
+	}
+)
+
+// New returns a client of the API served at baseURL, e.g. http://localhost:8080.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		retries:    defaultRetries,
+		retryWait:  defaultRetryWait,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken sets the access token sent with requests.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithCredentials makes the client log in before the first request and again
+// when the token is rejected, e.g. after it expired.
+func WithCredentials(username, password string) Option {
+	return func(c *Client) {
+		c.credentials = &credentials{username: username, password: password}
+	}
+}
+
+// WithRetries sets how many times idempotent requests (GET and DELETE) are
+// retried on network errors and 502, 503 and 504 responses. The wait doubles
+// after every attempt.
+func WithRetries(retries int, wait time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.retryWait = wait
+	}
+}
+
+// Token returns the current access token.
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.token
+}
+
+func (c *Client) setToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.token = token
+}
+
+// call sends an authorized request, logging in first when the client has credentials
+// but no token yet, and once more when the token is rejected.
+func call[T any](ctx context.Context, c *Client, method, path string, query url.Values, body any) (envelope[T], error) {
+	if c.Token() == "" && c.credentials != nil {
+		if _, err := c.Login(ctx, c.credentials.username, c.credentials.password); err != nil {
+			return envelope[T]{}, err
+		}
+	}
+
+	resp, err := send[T](ctx, c, method, path, query, body, true)
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.MsgCode == core.UnauthorizedCode && c.credentials != nil {
+		if _, err := c.Login(ctx, c.credentials.username, c.credentials.password); err != nil {
+			return envelope[T]{}, err
+		}
+		return send[T](ctx, c, method, path, query, body, true)
+	}
+
+	return resp, err
+}
+
+func send[T any](ctx context.Context, c *Client, method, path string, query url.Values, body any, authorized bool) (envelope[T], error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return envelope[T]{}, fmt.Errorf("marshal request: %w", err)
+		}
+	}
+
+	target := c.baseURL + path
+	if len(query) != 0 {
+		target += "?" + query.Encode()
+	}
+
+	attempts := 1
+	if method == http.MethodGet || method == http.MethodDelete {
+		attempts += c.retries
+	}
+
+	var (
+		data []byte
+		code int
+		err  error
+	)
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt != 0 {
+			select {
+			case <-ctx.Done():
+				return envelope[T]{}, ctx.Err()
+			case <-time.After(c.retryWait << (attempt - 1)):
+			}
+		}
+
+		data, code, err = c.do(ctx, method, target, payload, authorized)
+		if !retryable(code, err) || ctx.Err() != nil {
+			break
+		}
+	}
+	if err != nil {
+		return envelope[T]{}, err
+	}
+
+	if code >= http.StatusBadRequest {
+		return envelope[T]{}, newError(code, data)
+	}
+
+	var resp envelope[T]
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return envelope[T]{}, fmt.Errorf("decode response: %w", err)
+	}
+
+	return resp, nil
+}
+
+func (c *Client) do(ctx context.Context, method, target string, payload []byte, authorized bool) ([]byte, int, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token := c.Token(); authorized && token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	return data, resp.StatusCode, nil
+}
+
+func retryable(code int, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch code {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,331 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"vk-test-task/api/rest/handlers"
+	"vk-test-task/internal/core"
+	"vk-test-task/internal/service/auth"
+	"vk-test-task/internal/service/filmoteka"
+	"vk-test-task/internal/store/image"
+	"vk-test-task/internal/store/movie"
+	"vk-test-task/internal/store/star"
+	"vk-test-task/internal/store/user"
+	"vk-test-task/pkg/client"
+	"vk-test-task/pkg/jwt"
+	"vk-test-task/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/exp/slog"
+)
+
+type (
+	memoryUsers struct {
+		mu    sync.Mutex
+		users map[string]user.CreateEntity
+	}
+
+	// memoryFilmoteka keeps movies in memory, stars are always found with no movies.
+	// Neither movies nor stars have images.
+	memoryFilmoteka struct {
+		filmoteka.Service
+		mu     sync.Mutex
+		movies []movie.Entity
+	}
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = slog.New(slog.NewTextHandler(io.Discard, nil))
+	os.Exit(m.Run())
+}
+
+func (s *memoryUsers) Create(_ context.Context, entity user.CreateEntity) (user.Entity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[entity.Username] = entity
+	return user.Entity{ID: len(s.users), Username: entity.Username, Role: entity.Role}, nil
+}
+
+func (s *memoryUsers) GetPassHashAndRoleByUsername(_ context.Context, username string) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entity, ok := s.users[username]
+	if !ok {
+		return "", "", pgx.ErrNoRows
+	}
+	return entity.PassHash, entity.Role, nil
+}
+
+func (s *memoryUsers) CheckExistence(_ context.Context, username string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.users[username]
+	return ok, nil
+}
+
+func (s *memoryFilmoteka) GetMovies(_ context.Context, _ filmoteka.GetMoviesModel) ([]movie.Entity, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.movies, len(s.movies), nil
+}
+
+func (s *memoryFilmoteka) GetMovieByID(_ context.Context, id int) (movie.Entity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range s.movies {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return movie.Entity{}, pgx.ErrNoRows
+}
+
+func (s *memoryFilmoteka) CreateMovie(_ context.Context, model filmoteka.CreateMovieModel) (movie.Entity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	releaseDate, _ := time.Parse(time.RFC3339, model.ReleaseDate)
+	entity := movie.Entity{
+		ID:          len(s.movies) + 1,
+		Title:       model.Title,
+		Description: model.Description,
+		ReleaseDate: releaseDate,
+		Rating:      model.Rating,
+	}
+	s.movies = append(s.movies, entity)
+
+	return entity, nil
+}
+
+func (s *memoryFilmoteka) DeleteMovie(_ context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, m := range s.movies {
+		if m.ID == id {
+			s.movies = append(s.movies[:i], s.movies[i+1:]...)
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+func (s *memoryFilmoteka) GetStarByID(_ context.Context, id int) (star.Entity, []movie.Entity, error) {
+	return star.Entity{ID: id, Name: "Keanu Reeves", Sex: "male"}, nil, nil
+}
+
+func (s *memoryFilmoteka) GetImagesByMovieIDs(_ context.Context, _ []int) (map[int][]image.Entity, error) {
+	return map[int][]image.Entity{}, nil
+}
+
+func (s *memoryFilmoteka) GetImagesByStarIDs(_ context.Context, _ []int) (map[int][]image.Entity, error) {
+	return map[int][]image.Entity{}, nil
+}
+
+// newServer runs the real REST resolver over in-memory services
+// with an admin and a user account.
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	authService, err := auth.New(
+		&memoryUsers{users: map[string]user.CreateEntity{}},
+		&jwt.Config{Secret: "test-secret", AccessTokenExpiration: 15 * time.Minute},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	resolver, err := handlers.NewResolver("", handlers.Options{}, &memoryFilmoteka{}, authService)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	server := httptest.NewServer(resolver.Handler())
+	t.Cleanup(server.Close)
+
+	c := client.New(server.URL)
+	for _, signUp := range []client.SignUpRequest{
+		{Username: "admin", Password: "secret", Role: core.AdminRole},
+		{Username: "user", Password: "secret", Role: core.UserRole},
+	} {
+		if _, err := c.SignUp(context.Background(), signUp); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+	}
+
+	return server
+}
+
+func TestClient(t *testing.T) {
+	server := newServer(t)
+	ctx := context.Background()
+	c := client.New(server.URL, client.WithCredentials("admin", "secret"))
+
+	created, err := c.CreateMovie(ctx, client.CreateMovieRequest{
+		Title:       "The Matrix",
+		Description: "A hacker learns the truth about reality",
+		ReleaseDate: time.Date(1999, 3, 31, 0, 0, 0, 0, time.UTC),
+		Rating:      9,
+		StarsID:     []int{1},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if c.Token() == "" {
+		t.Error("expected the client to log in")
+	}
+
+	got, err := c.GetMovie(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if got.Title != "The Matrix" || got.ReleaseDate.Year() != 1999 {
+		t.Errorf("unexpected movie %+v", got)
+	}
+
+	list, err := c.ListMovies(ctx, client.ListMoviesParams{Sort: "rating,desc", Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(list.Movies) != 1 || list.Pagination.TotalCount != 1 || list.Pagination.PerPage != 10 {
+		t.Errorf("unexpected list %+v", list)
+	}
+
+	starWithMovies, err := c.GetStar(ctx, 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if starWithMovies.Star.ID != 42 {
+		t.Errorf("unexpected star %+v", starWithMovies)
+	}
+
+	if err := c.DeleteMovie(ctx, created.ID); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if _, err := c.GetMovie(ctx, created.ID); !errors.Is(err, client.ErrMovieNotFound) {
+		t.Errorf("expected movie not found, got %v", err)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	server := newServer(t)
+	ctx := context.Background()
+
+	if _, err := client.New(server.URL).Login(ctx, "admin", "wrong"); !errors.Is(err, client.ErrWrongCredentials) {
+		t.Errorf("expected wrong credentials, got %v", err)
+	}
+
+	admin := client.New(server.URL, client.WithCredentials("admin", "secret"))
+	_, err := admin.CreateMovie(ctx, client.CreateMovieRequest{Description: "No title", Rating: 5, StarsID: []int{1}})
+
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.MsgCode != core.ValidationCode || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if len(apiErr.Errors) == 0 || apiErr.Errors[0].Field != "Title" {
+		t.Errorf("unexpected validation errors %+v", apiErr.Errors)
+	}
+
+	userClient := client.New(server.URL, client.WithCredentials("user", "secret"))
+	if err := userClient.DeleteMovie(ctx, 1); !errors.Is(err, client.ErrForbidden) {
+		t.Errorf("expected forbidden, got %v", err)
+	}
+}
+
+func TestClientRefreshesToken(t *testing.T) {
+	server := newServer(t)
+	c := client.New(server.URL, client.WithToken("expired"), client.WithCredentials("user", "secret"))
+
+	if _, err := c.ListMovies(context.Background(), client.ListMoviesParams{}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if c.Token() == "expired" {
+		t.Error("expected the token to be refreshed")
+	}
+
+	// Without credentials the rejected token is returned as an error
+	c = client.New(server.URL, client.WithToken("expired"))
+	if _, err := c.ListMovies(context.Background(), client.ListMoviesParams{}); !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("expected unauthorized, got %v", err)
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	api := newServer(t)
+
+	var (
+		mu       sync.Mutex
+		requests = map[string]int{}
+	)
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		requests[req.Method]++
+		n := requests[req.Method]
+		mu.Unlock()
+
+		if n <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		proxy, _ := http.NewRequestWithContext(req.Context(), req.Method, api.URL+req.URL.String(), req.Body)
+		proxy.Header = req.Header
+		resp, err := http.DefaultClient.Do(proxy)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+
+		w.WriteHeader(resp.StatusCode)
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := resp.Body.Read(buf)
+			w.Write(buf[:n]) //nolint
+			if err != nil {
+				return
+			}
+		}
+	}))
+	defer flaky.Close()
+
+	c := client.New(flaky.URL, client.WithToken(login(t, api.URL)), client.WithRetries(3, time.Millisecond))
+
+	if _, err := c.ListMovies(context.Background(), client.ListMoviesParams{}); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if requests[http.MethodGet] != 3 {
+		t.Errorf("expected 3 GET requests, got %d", requests[http.MethodGet])
+	}
+
+	// Creating is not idempotent and is not retried
+	_, err := c.CreateMovie(context.Background(), client.CreateMovieRequest{Title: "Retry"})
+	if client.MsgCode(err) != http.StatusText(http.StatusServiceUnavailable) {
+		t.Errorf("expected service unavailable, got %v", err)
+	}
+	if requests[http.MethodPost] != 1 {
+		t.Errorf("expected 1 POST request, got %d", requests[http.MethodPost])
+	}
+}
+
+func login(t *testing.T, url string) string {
+	t.Helper()
+
+	token, err := client.New(url).Login(context.Background(), "user", "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	return token.AccessToken
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/pkg/web"
+)
+
+// Error is an error response of the API. Errors are compared by msg code,
+// so errors.Is(err, client.ErrMovieNotFound) matches any movie_not_found response.
+type Error struct {
+	StatusCode int
+	MsgCode    string
+	// Errors are the failed validation rules of a validation response.
+	Errors []web.ValidationError
+}
+
+var (
+	ErrUnauthorized     = &Error{MsgCode: core.UnauthorizedCode}
+	ErrForbidden        = &Error{MsgCode: core.ForbiddenErrorCode}
+	ErrWrongCredentials = &Error{MsgCode: core.WrongCredentialsCode}
+	ErrUsernameIsTaken  = &Error{MsgCode: core.UsernameIsTaken}
+	ErrValidation       = &Error{MsgCode: core.ValidationCode}
+	ErrInvalidID        = &Error{MsgCode: core.InvalidIDCode}
+	ErrBodyTooLarge     = &Error{MsgCode: core.BodyTooLargeCode}
+	ErrTooManyRequests  = &Error{MsgCode: core.TooManyRequestsCode}
+	ErrAccountLocked    = &Error{MsgCode: core.AccountLockedCode}
+	ErrMovieNotFound    = &Error{MsgCode: core.MovieNotFoundCode}
+	ErrStarNotFound     = &Error{MsgCode: core.StarNotFoundCode}
+	ErrInternal         = &Error{MsgCode: core.InternalErrorCode}
+)
+
+func (e *Error) Error() string {
+	if len(e.Errors) != 0 {
+		return fmt.Sprintf("filmoteka: %d %s: %v", e.StatusCode, e.MsgCode, e.Errors)
+	}
+
+	return fmt.Sprintf("filmoteka: %d %s", e.StatusCode, e.MsgCode)
+}
+
+func (e *Error) Is(target error) bool {
+	var t *Error
+	return errors.As(target, &t) && t.MsgCode == e.MsgCode
+}
+
+// MsgCode returns the msg code of an API error or an empty string.
+func MsgCode(err error) string {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.MsgCode
+	}
+
+	return ""
+}
+
+func newError(code int, body []byte) error {
+	var resp envelope[json.RawMessage]
+	if err := json.Unmarshal(body, &resp); err != nil || resp.MsgCode == "" {
+		return &Error{StatusCode: code, MsgCode: http.StatusText(code)}
+	}
+
+	apiErr := &Error{StatusCode: code, MsgCode: resp.MsgCode}
+	if resp.MsgCode == core.ValidationCode {
+		var data struct {
+			Errors []web.ValidationError `json:"errors"`
+		}
+		if json.Unmarshal(resp.Data, &data) == nil {
+			apiErr.Errors = data.Errors
+		}
+	}
+
+	return apiErr
+}
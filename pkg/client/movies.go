@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func (c *Client) ListMovies(ctx context.Context, params ListMoviesParams) (MovieList, error) {
+	query := url.Values{}
+	if params.Q != "" {
+		query.Set("q", params.Q)
+	}
+	if params.Sort != "" {
+		query.Set("sort", params.Sort)
+	}
+	setPagination(query, params.Page, params.Limit)
+
+	resp, err := call[[]Movie](ctx, c, http.MethodGet, filmotekaPrefix+"/movies", query, nil)
+	if err != nil {
+		return MovieList{}, err
+	}
+
+	list := MovieList{Movies: resp.Data}
+	if err := decodeMeta(resp.Meta, &list.Pagination); err != nil {
+		return MovieList{}, err
+	}
+
+	return list, nil
+}
+
+func (c *Client) GetMovie(ctx context.Context, id int) (Movie, error) {
+	resp, err := call[Movie](ctx, c, http.MethodGet, moviePath(id), nil, nil)
+	return resp.Data, err
+}
+
+func (c *Client) CreateMovie(ctx context.Context, req CreateMovieRequest) (Movie, error) {
+	resp, err := call[Movie](ctx, c, http.MethodPost, filmotekaPrefix+"/movies", nil, req)
+	return resp.Data, err
+}
+
+func (c *Client) UpdateMovie(ctx context.Context, id int, req UpdateMovieRequest) (Movie, error) {
+	resp, err := call[Movie](ctx, c, http.MethodPatch, moviePath(id), nil, req)
+	return resp.Data, err
+}
+
+func (c *Client) DeleteMovie(ctx context.Context, id int) error {
+	_, err := call[json.RawMessage](ctx, c, http.MethodDelete, moviePath(id), nil, nil)
+	return err
+}
+
+func moviePath(id int) string {
+	return fmt.Sprintf("%s/movie/%d", filmotekaPrefix, id)
+}
+
+func setPagination(query url.Values, page, limit int) {
+	if page != 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	if limit != 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+}
+
+func decodeMeta(meta json.RawMessage, pagination *Pagination) error {
+	if len(meta) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(meta, pagination); err != nil {
+		return fmt.Errorf("decode pagination: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+func (c *Client) ListStars(ctx context.Context, params ListStarsParams) (StarList, error) {
+	query := url.Values{}
+	if params.Q != "" {
+		query.Set("q", params.Q)
+	}
+	setPagination(query, params.Page, params.Limit)
+
+	resp, err := call[[]Star](ctx, c, http.MethodGet, filmotekaPrefix+"/stars", query, nil)
+	if err != nil {
+		return StarList{}, err
+	}
+
+	list := StarList{Stars: resp.Data}
+	if err := decodeMeta(resp.Meta, &list.Pagination); err != nil {
+		return StarList{}, err
+	}
+
+	return list, nil
+}
+
+// GetStar returns a star with the movies it starred in.
+func (c *Client) GetStar(ctx context.Context, id int) (StarWithMovies, error) {
+	resp, err := call[StarWithMovies](ctx, c, http.MethodGet, starPath(id), nil, nil)
+	return resp.Data, err
+}
+
+func (c *Client) CreateStar(ctx context.Context, req CreateStarRequest) (Star, error) {
+	resp, err := call[StarWithMovies](ctx, c, http.MethodPost, filmotekaPrefix+"/stars", nil, req)
+	return resp.Data.Star, err
+}
+
+func (c *Client) UpdateStar(ctx context.Context, id int, req UpdateStarRequest) (StarWithMovies, error) {
+	resp, err := call[StarWithMovies](ctx, c, http.MethodPatch, starPath(id), nil, req)
+	return resp.Data, err
+}
+
+func (c *Client) DeleteStar(ctx context.Context, id int) error {
+	_, err := call[json.RawMessage](ctx, c, http.MethodDelete, starPath(id), nil, nil)
+	return err
+}
+
+func starPath(id int) string {
+	return fmt.Sprintf("%s/star/%d", filmotekaPrefix, id)
+}
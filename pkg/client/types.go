@@ -0,0 +1,115 @@
+package client
+
+import "time"
+
+type (
+	User struct {
+		ID        int       `json:"id"`
+		Username  string    `json:"username"`
+		Role      string    `json:"role"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+
+	Token struct {
+		AccessToken string `json:"access_token"`
+	}
+
+	Movie struct {
+		ID          int        `json:"id"`
+		Title       string     `json:"title"`
+		Description string     `json:"description"`
+		ReleaseDate time.Time  `json:"release_date"`
+		Rating      int        `json:"rating"`
+		CreatedAt   time.Time  `json:"created_at"`
+		UpdatedAt   time.Time  `json:"updated_at"`
+		DeletedAt   *time.Time `json:"deleted_at"`
+		// MatchedAlias is set in lists searched by Q when an alias matched
+		MatchedAlias string `json:"matched_alias,omitempty"`
+	}
+
+	Star struct {
+		ID        int        `json:"id"`
+		Name      string     `json:"name"`
+		Sex       string     `json:"sex"`
+		BirthDate time.Time  `json:"birth_date"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt time.Time  `json:"updated_at"`
+		DeletedAt *time.Time `json:"deleted_at"`
+		// MatchedAlias is set in lists searched by Q when an alias matched
+		MatchedAlias string `json:"matched_alias,omitempty"`
+	}
+
+	StarWithMovies struct {
+		Star   Star    `json:"star"`
+		Movies []Movie `json:"movies"`
+	}
+
+	Pagination struct {
+		TotalCount  int `json:"total_count"`
+		PageCount   int `json:"page_count"`
+		CurrentPage int `json:"current_page"`
+		PerPage     int `json:"per_page"`
+	}
+
+	MovieList struct {
+		Movies     []Movie    `json:"movies"`
+		Pagination Pagination `json:"pagination"`
+	}
+
+	StarList struct {
+		Stars      []Star     `json:"stars"`
+		Pagination Pagination `json:"pagination"`
+	}
+
+	SignUpRequest struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Role     string `json:"role"`
+	}
+
+	ListMoviesParams struct {
+		// Q searches by movie title or star name.
+		Q string
+		// Sort is a field and an order, e.g. "rating,desc".
+		Sort  string
+		Page  int
+		Limit int
+	}
+
+	ListStarsParams struct {
+		Q     string
+		Page  int
+		Limit int
+	}
+
+	CreateMovieRequest struct {
+		Title       string    `json:"title"`
+		Description string    `json:"description"`
+		ReleaseDate time.Time `json:"release_date"`
+		Rating      int       `json:"rating"`
+		StarsID     []int     `json:"stars_id"`
+	}
+
+	// UpdateMovieRequest changes the set fields only.
+	UpdateMovieRequest struct {
+		Title       *string    `json:"title,omitempty"`
+		Description *string    `json:"description,omitempty"`
+		ReleaseDate *time.Time `json:"release_date,omitempty"`
+		Rating      *int       `json:"rating,omitempty"`
+		StarsID     []int      `json:"stars_id,omitempty"`
+	}
+
+	CreateStarRequest struct {
+		Name      string    `json:"name"`
+		Sex       string    `json:"sex"`
+		BirthDate time.Time `json:"birth_date"`
+	}
+
+	// UpdateStarRequest changes the set fields only.
+	UpdateStarRequest struct {
+		Name      *string    `json:"name,omitempty"`
+		Sex       *string    `json:"sex,omitempty"`
+		BirthDate *time.Time `json:"birth_date,omitempty"`
+	}
+)
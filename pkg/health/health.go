@@ -0,0 +1,132 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// checkTimeout limits every readiness check.
+const checkTimeout = 2 * time.Second
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+	statusDraining    = "draining"
+)
+
+// Check returns an error when the dependency is not usable.
+type Check func(context.Context) error
+
+type (
+	// Checker serves the liveness and readiness of the service.
+	Checker struct {
+		mu       sync.Mutex
+		checks   map[string]Check
+		draining atomic.Bool
+	}
+
+	Report struct {
+		Status string                 `json:"status"`
+		Checks map[string]CheckReport `json:"checks,omitempty"`
+	}
+
+	CheckReport struct {
+		Status string `json:"status"`
+		Error  string `json:"error,omitempty"`
+	}
+)
+
+func New() *Checker {
+	return &Checker{checks: map[string]Check{}}
+}
+
+// Add registers a readiness check under the name shown in the report.
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks[name] = check
+}
+
+// Drain makes the service not ready, so that no new traffic is routed to it while
+// it is shutting down. The checks still run to report the dependencies.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// LiveHandler answers 200 while the process is able to serve HTTP.
+func (c *Checker) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeReport(w, http.StatusOK, Report{Status: statusOK})
+	})
+}
+
+// ReadyHandler runs the checks concurrently and answers 200 when all of them pass,
+// otherwise 503 with the failed checks.
+func (c *Checker) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Ready(r.Context())
+
+		code := http.StatusOK
+		if report.Status != statusOK {
+			code = http.StatusServiceUnavailable
+		}
+		writeReport(w, code, report)
+	})
+}
+
+// Ready runs the checks and reports the readiness of the service.
+func (c *Checker) Ready(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	c.mu.Lock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.Unlock()
+
+	report := Report{Status: statusOK, Checks: make(map[string]CheckReport, len(checks))}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+
+			result := CheckReport{Status: statusOK}
+			if err := check(ctx); err != nil {
+				result = CheckReport{Status: statusUnavailable, Error: err.Error()}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != statusOK {
+				report.Status = statusUnavailable
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	if c.draining.Load() {
+		report.Status = statusDraining
+	}
+
+	return report
+}
+
+func writeReport(w http.ResponseWriter, code int, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(report) //nolint
+}
@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestReadyHandler(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		Checks map[string]Check
+		Drain  bool
+		Code   int
+		Report Report
+	}{
+		{
+			Name: "Ready",
+			Checks: map[string]Check{
+				"postgres": func(context.Context) error { return nil },
+				"jwt":      func(context.Context) error { return nil },
+			},
+			Code: http.StatusOK,
+			Report: Report{Status: statusOK, Checks: map[string]CheckReport{
+				"postgres": {Status: statusOK},
+				"jwt":      {Status: statusOK},
+			}},
+		},
+		{
+			Name: "Failed check",
+			Checks: map[string]Check{
+				"postgres":   func(context.Context) error { return nil },
+				"migrations": func(context.Context) error { return errors.New("schema version is 4, expected 5") },
+			},
+			Code: http.StatusServiceUnavailable,
+			Report: Report{Status: statusUnavailable, Checks: map[string]CheckReport{
+				"postgres":   {Status: statusOK},
+				"migrations": {Status: statusUnavailable, Error: "schema version is 4, expected 5"},
+			}},
+		},
+		{
+			Name: "Draining",
+			Checks: map[string]Check{
+				"postgres": func(context.Context) error { return nil },
+			},
+			Drain: true,
+			Code:  http.StatusServiceUnavailable,
+			Report: Report{Status: statusDraining, Checks: map[string]CheckReport{
+				"postgres": {Status: statusOK},
+			}},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			checker := New()
+			for name, check := range test.Checks {
+				checker.Add(name, check)
+			}
+			if test.Drain {
+				checker.Drain()
+			}
+
+			rec := httptest.NewRecorder()
+			checker.ReadyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != test.Code {
+				t.Errorf("wrong status code. Expected %d but got %d", test.Code, rec.Code)
+			}
+
+			var report Report
+			if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+			if !reflect.DeepEqual(report, test.Report) {
+				t.Errorf("wrong report. Expected %+v but got %+v", test.Report, report)
+			}
+		})
+	}
+}
+
+func TestLiveHandler(t *testing.T) {
+	checker := New()
+	checker.Add("postgres", func(context.Context) error { return errors.New("connection refused") })
+	checker.Drain()
+
+	rec := httptest.NewRecorder()
+	checker.LiveHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("wrong status code. Expected %d but got %d", http.StatusOK, rec.Code)
+	}
+}
@@ -1,22 +1,23 @@
 package jwt
 
 import (
-	"fmt"
+	"errors"
 	"time"
-
-	"github.com/caarlos0/env/v8"
 )
 
 type Config struct {
-	Secret                string        `env:"JWT_SECRET"`
-	AccessTokenExpiration time.Duration `env:"JWT_ACCESS_TOKEN_EXPIRATION" envDefault:"15m"`
+	Secret                string        `yaml:"secret" toml:"secret" env:"JWT_SECRET"`
+	AccessTokenExpiration time.Duration `yaml:"access_token_expiration" toml:"access_token_expiration" env:"JWT_ACCESS_TOKEN_EXPIRATION"`
 }
 
-func ParseConfig() (*Config, error) {
-	var c Config
-	if err := env.Parse(&c); err != nil {
-		return nil, fmt.Errorf("parse config: %w", err)
+// Validate checks that tokens can be signed with the config.
+func (c *Config) Validate() error {
+	if c.Secret == "" {
+		return errors.New("JWT_SECRET is not set")
+	}
+	if c.AccessTokenExpiration <= 0 {
+		return errors.New("JWT_ACCESS_TOKEN_EXPIRATION must be positive")
 	}
 
-	return &c, nil
+	return nil
 }
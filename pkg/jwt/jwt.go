@@ -46,7 +46,7 @@ func (s *Service) ValidateToken(tokenString string) (*UserData, error) {
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 		role := fmt.Sprint(claims["role"])
-		username := fmt.Sprint(claims["username"])
+		username := fmt.Sprint(claims["user"])
 		user := &UserData{
 			Username: username,
 			Role:     role,
@@ -0,0 +1,114 @@
+package locale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"golang.org/x/text/language"
+)
+
+var ErrNoLocales = errors.New("no locales")
+
+// Locales are the locales of a request by preference. The last one is the default
+// locale, the one movies are written in: it is always there and nothing follows it.
+type Locales []string
+
+// Default returns the locale of the movies themselves.
+func (l Locales) Default() string {
+	if len(l) == 0 {
+		return ""
+	}
+
+	return l[len(l)-1]
+}
+
+// Translated returns the locales preferred to the default one, the translations to them
+// are looked for.
+func (l Locales) Translated() []string {
+	if len(l) == 0 {
+		return nil
+	}
+
+	return l[:len(l)-1]
+}
+
+// Negotiator picks the supported locales asked for by Accept-Language.
+type Negotiator struct {
+	supported []string
+}
+
+// NewNegotiator takes the supported locales as two- or three-letter language codes,
+// the first one is the default.
+func NewNegotiator(supported []string) (*Negotiator, error) {
+	if len(supported) == 0 {
+		return nil, ErrNoLocales
+	}
+
+	locales := make([]string, 0, len(supported))
+	for _, locale := range supported {
+		base, err := language.ParseBase(locale)
+		if err != nil || base.String() != locale {
+			return nil, fmt.Errorf("invalid locale %q", locale)
+		}
+		if slices.Contains(locales, locale) {
+			return nil, fmt.Errorf("duplicate locale %q", locale)
+		}
+		locales = append(locales, locale)
+	}
+
+	return &Negotiator{supported: locales}, nil
+}
+
+// Default returns the locale of the movies themselves.
+func (n *Negotiator) Default() string {
+	return n.supported[0]
+}
+
+// Supports reports whether the locale is one of the supported ones.
+func (n *Negotiator) Supports(locale string) bool {
+	return slices.Contains(n.supported, locale)
+}
+
+// Negotiate returns the supported locales of the Accept-Language header by preference,
+// regional variants count as their language: en-US asks for en. The default locale
+// ends the list, a malformed header asks for it only.
+func (n *Negotiator) Negotiate(acceptLanguage string) Locales {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		tags = nil
+	}
+
+	locales := make(Locales, 0, len(n.supported))
+	for _, tag := range tags {
+		// the wildcard and unknown languages are guessed, not asked for
+		base, confidence := tag.Base()
+		if confidence != language.Exact {
+			continue
+		}
+
+		locale := base.String()
+		if locale == n.Default() {
+			break
+		}
+		if n.Supports(locale) && !slices.Contains(locales, locale) {
+			locales = append(locales, locale)
+		}
+	}
+
+	return append(locales, n.Default())
+}
+
+type localesKey struct{}
+
+// ContextWith returns a copy of ctx carrying the locales of the request.
+func ContextWith(ctx context.Context, locales Locales) context.Context {
+	return context.WithValue(ctx, localesKey{}, locales)
+}
+
+// FromContext returns the locales of the request, nil if they were not negotiated.
+func FromContext(ctx context.Context) Locales {
+	locales, _ := ctx.Value(localesKey{}).(Locales)
+	return locales
+}
@@ -0,0 +1,68 @@
+package locale
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestNewNegotiator(t *testing.T) {
+	for _, test := range []struct {
+		In      []string
+		WantErr bool
+	}{
+		{In: []string{"ru", "en"}},
+		{In: []string{"en"}},
+		{In: nil, WantErr: true},
+		{In: []string{"ru", "ru"}, WantErr: true},
+		{In: []string{"en-US"}, WantErr: true},
+		{In: []string{"russian"}, WantErr: true},
+	} {
+		_, err := NewNegotiator(test.In)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%q: unexpected error: %v", test.In, err)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	negotiator, err := NewNegotiator([]string{"ru", "en", "de"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	for _, test := range []struct {
+		In   string
+		Want Locales
+	}{
+		{In: "", Want: Locales{"ru"}},
+		{In: "en", Want: Locales{"en", "ru"}},
+		{In: "en-US,en;q=0.9,de;q=0.5", Want: Locales{"en", "de", "ru"}},
+		{In: "de;q=0.5,en;q=0.8", Want: Locales{"en", "de", "ru"}},
+		{In: "ru,en", Want: Locales{"ru"}},
+		{In: "fr,en;q=0.5", Want: Locales{"en", "ru"}},
+		{In: "*", Want: Locales{"ru"}},
+		{In: "en;q=0", Want: Locales{"ru"}},
+		{In: "en;q=high", Want: Locales{"ru"}},
+	} {
+		got := negotiator.Negotiate(test.In)
+		if !slices.Equal(got, test.Want) {
+			t.Errorf("%q: wrong locales. Expected %v but got %v", test.In, test.Want, got)
+		}
+		if got.Default() != "ru" {
+			t.Errorf("%q: wrong default locale. Expected ru but got %s", test.In, got.Default())
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	if locales := FromContext(context.Background()); locales != nil {
+		t.Errorf("wrong locales of a context without them. Expected nil but got %v", locales)
+	}
+
+	ctx := ContextWith(context.Background(), Locales{"en", "ru"})
+	locales := FromContext(ctx)
+	if !slices.Equal(locales.Translated(), []string{"en"}) || locales.Default() != "ru" {
+		t.Errorf("wrong locales. Expected [en ru] but got %v", locales)
+	}
+}
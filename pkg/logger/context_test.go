@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+)
+
+func TestContextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(contextHandler{slog.NewTextHandler(&buf, nil)}).With("component", "store")
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	ctx = ContextWith(ctx, "request_id", "abc")
+	ctx = ContextWith(ctx, "user", "admin")
+
+	log.ErrorContext(ctx, "get movies")
+	log.Error("no context")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("wrong number of records. Expected 2 but got %d", len(lines))
+	}
+	want := "component=store request_id=abc user=admin trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7"
+	if !strings.HasSuffix(lines[0], want) {
+		t.Errorf("record has no context attributes: %s", lines[0])
+	}
+	if strings.Contains(lines[1], "request_id") || strings.Contains(lines[1], "trace_id") {
+		t.Errorf("record without context has its attributes: %s", lines[1])
+	}
+}
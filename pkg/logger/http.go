@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type levelBody struct {
+	Level string `json:"level"`
+	Error string `json:"error,omitempty"`
+}
+
+// LevelHandler reports the level of Log on GET and changes it on PUT with a body like
+// {"level": "debug"}, e.g. to debug a running service. The level set by the config
+// comes back on restart or config reload.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body levelBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeLevel(w, http.StatusBadRequest, levelBody{Level: Level(), Error: "invalid body: " + err.Error()})
+				return
+			}
+			if err := SetLevel(body.Level); err != nil {
+				writeLevel(w, http.StatusBadRequest, levelBody{Level: Level(), Error: err.Error()})
+				return
+			}
+			Log.Info("log level changed", "level", Level())
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			writeLevel(w, http.StatusMethodNotAllowed, levelBody{Level: Level(), Error: "method not allowed"})
+			return
+		}
+
+		writeLevel(w, http.StatusOK, levelBody{Level: Level()})
+	})
+}
+
+func writeLevel(w http.ResponseWriter, code int, body levelBody) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body) //nolint
+}
@@ -2,30 +2,134 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"sync"
 
 	"golang.org/x/exp/slog"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 const (
-	envLocal = "local"
-	envDev   = "dev"
+	FormatText = "text"
+	FormatJSON = "json"
 )
 
 var Log *slog.Logger
 
-func SetupLogger(env string) error {
-	switch env {
-	case envLocal:
-		Log = slog.New(
-			slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
-		)
-	case envDev:
-		Log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
-		)
+var (
+	// level is shared by the handlers of Log, so SetLevel applies without rebuilding it
+	level = new(slog.LevelVar)
+
+	// file is the rotated log file of the last Setup, closed by the next one
+	fileMu sync.Mutex
+	file   *lumberjack.Logger
+)
+
+var levels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+	// names of the environments the level was set by before
+	"local": slog.LevelDebug,
+	"dev":   slog.LevelInfo,
+}
+
+// Options set up Log. The zero Options write text records of info and above to stdout.
+type Options struct {
+	// Level is debug, info, warn or error
+	Level string `yaml:"level" toml:"level" env:"LOG_LEVEL"`
+	// Format is text or json
+	Format string `yaml:"format" toml:"format" env:"LOG_FORMAT"`
+
+	// File turns on writing to the file instead of stdout. The file is rotated once it
+	// grows to MaxSizeMB, MaxBackups rotated files are kept for MaxAgeDays.
+	File       string `yaml:"file" toml:"file" env:"LOG_FILE"`
+	MaxSizeMB  int    `yaml:"max_size_mb" toml:"max_size_mb" env:"LOG_MAX_SIZE_MB"`
+	MaxBackups int    `yaml:"max_backups" toml:"max_backups" env:"LOG_MAX_BACKUPS"`
+	MaxAgeDays int    `yaml:"max_age_days" toml:"max_age_days" env:"LOG_MAX_AGE_DAYS"`
+
+	// SampleFirst debug records with the same message are written each second, then
+	// every SampleThereafter-th of them. Zero SampleFirst writes every record.
+	SampleFirst      int `yaml:"sample_first" toml:"sample_first" env:"LOG_SAMPLE_FIRST"`
+	SampleThereafter int `yaml:"sample_thereafter" toml:"sample_thereafter" env:"LOG_SAMPLE_THEREAFTER"`
+}
+
+// Setup builds Log from opts. Passwords, tokens and the passwords of URLs are redacted
+// from the records, see Redact.
+func Setup(opts Options) error {
+	lvl, err := ParseLevel(opts.Level)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	var rotated *lumberjack.Logger
+	if opts.File != "" {
+		rotated = &lumberjack.Logger{
+			Filename:   opts.File,
+			MaxSize:    opts.MaxSizeMB,
+			MaxBackups: opts.MaxBackups,
+			MaxAge:     opts.MaxAgeDays,
+		}
+		w = rotated
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: level, ReplaceAttr: Redact}
+	var handler slog.Handler
+	switch opts.Format {
+	case FormatText, "":
+		handler = slog.NewTextHandler(w, handlerOpts)
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, handlerOpts)
 	default:
-		return fmt.Errorf("invalid logger level: %s", env)
+		return fmt.Errorf("invalid log format: %s", opts.Format)
+	}
+	if opts.SampleFirst > 0 {
+		handler = SamplingHandler(handler, opts.SampleFirst, opts.SampleThereafter)
 	}
+
+	level.Set(lvl)
+	Log = slog.New(ContextHandler(handler))
+
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	if file != nil {
+		file.Close() //nolint
+	}
+	file = rotated
+
 	return nil
 }
+
+// ParseLevel parses debug, info, warn or error. An empty level is info.
+func ParseLevel(s string) (slog.Level, error) {
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+
+	lvl, ok := levels[strings.ToLower(s)]
+	if !ok {
+		return 0, fmt.Errorf("invalid log level: %s", s)
+	}
+
+	return lvl, nil
+}
+
+// SetLevel changes the level of Log while it is in use.
+func SetLevel(s string) error {
+	lvl, err := ParseLevel(s)
+	if err != nil {
+		return err
+	}
+	level.Set(lvl)
+
+	return nil
+}
+
+// Level returns the level of Log, e.g. "info".
+func Level() string {
+	return strings.ToLower(level.Level().String())
+}
@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/exp/slog"
+)
+
+const redacted = "[redacted]"
+
+// sensitiveKeys are the attribute keys whose values are never written. Keys are compared
+// in lower case, with a suffix, so that e.g. db_password and access_token match.
+var sensitiveKeys = []string{"password", "pass", "secret", "token", "authorization", "cookie"}
+
+// Redact is the ReplaceAttr of the handlers of Log. It hides the values of sensitive
+// attributes and the password of URLs, like the database URL.
+func Redact(_ []string, attr slog.Attr) slog.Attr {
+	if isSensitive(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+
+	if attr.Value.Kind() == slog.KindString {
+		if s := attr.Value.String(); strings.Contains(s, "://") {
+			if u, err := url.Parse(s); err == nil && u.User != nil {
+				return slog.String(attr.Key, u.Redacted())
+			}
+		}
+	}
+
+	return attr
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if key == sensitive || strings.HasSuffix(key, "_"+sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+// samplingHandler drops debug records with the same message past the first ones in a
+// second, so that a hot path logging at debug does not flood the output. Records of
+// info and above are always written.
+type samplingHandler struct {
+	slog.Handler
+	first      int
+	thereafter int
+	counts     *sampleCounts
+}
+
+type sampleCounts struct {
+	mu     sync.Mutex
+	second int64
+	counts map[string]int
+}
+
+// SamplingHandler wraps h to write the first debug records with a message in each second,
+// then every thereafter-th of them, none with a zero thereafter.
+func SamplingHandler(h slog.Handler, first, thereafter int) slog.Handler {
+	return samplingHandler{
+		Handler:    h,
+		first:      first,
+		thereafter: thereafter,
+		counts:     &sampleCounts{counts: make(map[string]int)},
+	}
+}
+
+func (h samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level > slog.LevelDebug || h.counts.sampled(r.Message, r.Time, h.first, h.thereafter) {
+		return h.Handler.Handle(ctx, r)
+	}
+
+	return nil
+}
+
+// sampled counts the record and reports whether it is written.
+func (c *sampleCounts) sampled(message string, t time.Time, first, thereafter int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if second := t.Unix(); second != c.second {
+		c.second = second
+		c.counts = make(map[string]int)
+	}
+
+	c.counts[message]++
+	n := c.counts[message]
+	if n <= first {
+		return true
+	}
+
+	return thereafter > 0 && (n-first)%thereafter == 0
+}
+
+func (h samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return samplingHandler{h.Handler.WithAttrs(attrs), h.first, h.thereafter, h.counts}
+}
+
+func (h samplingHandler) WithGroup(name string) slog.Handler {
+	return samplingHandler{h.Handler.WithGroup(name), h.first, h.thereafter, h.counts}
+}
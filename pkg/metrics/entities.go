@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"vk-test-task/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// countTimeout limits the queries made on a scrape.
+const countTimeout = 5 * time.Second
+
+// CountFunc returns the number of stored entities of a kind.
+type CountFunc func(context.Context) (int, error)
+
+// entitiesCollector queries the number of entities on every scrape.
+type entitiesCollector struct {
+	counts map[string]CountFunc
+	desc   *prometheus.Desc
+}
+
+// NewEntitiesCollector returns a collector of the number of entities by kind, e.g. "movies".
+// A kind that can't be counted is left out of the scrape.
+func NewEntitiesCollector(counts map[string]CountFunc) prometheus.Collector {
+	return &entitiesCollector{
+		counts: counts,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "entities"),
+			"Number of stored entities that are not deleted.",
+			[]string{"kind"}, nil,
+		),
+	}
+}
+
+func (c *entitiesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *entitiesCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), countTimeout)
+	defer cancel()
+
+	kinds := make([]string, 0, len(c.counts))
+	for kind := range c.counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		count, err := c.counts[kind](ctx)
+		if err != nil {
+			logger.Log.Error("error count entities", "kind", kind, "error", err.Error())
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), kind)
+	}
+}
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "filmoteka"
+
+// Login results.
+const (
+	LoginSuccess = "success"
+	LoginFailure = "failure"
+	// LoginLocked is an attempt to log in to an account locked out after failures
+	LoginLocked = "locked"
+)
+
+// Registry holds the metrics of the process, it is served by Handler.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of handled HTTP requests.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of handled HTTP requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	storeQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "store",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of store methods.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"store", "method"})
+
+	logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "logins_total",
+		Help:      "Number of login attempts by result.",
+	}, []string{"result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpRequestDuration,
+		storeQueryDuration,
+		logins,
+	)
+}
+
+// Handler serves the metrics of Registry along with collectors in the Prometheus text
+// format. The collectors, e.g. of a connection pool, are registered on a registry of
+// their own, so that every server built in the process may bring its own.
+func Handler(collectors ...prometheus.Collector) (http.Handler, error) {
+	registry := prometheus.NewRegistry()
+	for _, collector := range collectors {
+		if err := registry.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return promhttp.HandlerFor(prometheus.Gatherers{Registry, registry}, promhttp.HandlerOpts{Registry: registry}), nil
+}
+
+// ObserveRequest counts the request to the route template and records its duration.
+func ObserveRequest(method, route string, status int, start time.Time) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpRequestDuration.WithLabelValues(method, route, code).Observe(time.Since(start).Seconds())
+}
+
+// ObserveQuery records the duration of the store method, it is meant to be deferred.
+func ObserveQuery(store, method string, start time.Time) {
+	storeQueryDuration.WithLabelValues(store, method).Observe(time.Since(start).Seconds())
+}
+
+// Login counts a login attempt with LoginSuccess, LoginFailure or LoginLocked result.
+func Login(result string) {
+	logins.WithLabelValues(result).Inc()
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"vk-test-task/pkg/logger"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"golang.org/x/exp/slog"
+)
+
+func TestMain(m *testing.M) {
+	logger.Log = slog.New(slog.NewTextHandler(io.Discard, nil))
+	os.Exit(m.Run())
+}
+
+func TestEntitiesCollector(t *testing.T) {
+	collector := NewEntitiesCollector(map[string]CountFunc{
+		"movies": func(context.Context) (int, error) { return 3, nil },
+		"stars":  func(context.Context) (int, error) { return 0, errors.New("connection refused") },
+	})
+
+	want := `
+# HELP filmoteka_entities Number of stored entities that are not deleted.
+# TYPE filmoteka_entities gauge
+filmoteka_entities{kind="movies"} 3
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(want)); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+}
+
+func TestLogin(t *testing.T) {
+	Login(LoginSuccess)
+	Login(LoginFailure)
+	Login(LoginFailure)
+
+	if got := testutil.ToFloat64(logins.WithLabelValues(LoginSuccess)); got != 1 {
+		t.Errorf("got %v successful logins, want 1", got)
+	}
+	if got := testutil.ToFloat64(logins.WithLabelValues(LoginFailure)); got != 2 {
+		t.Errorf("got %v failed logins, want 2", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	entities := func() prometheus.Collector {
+		return NewEntitiesCollector(map[string]CountFunc{
+			"movies": func(context.Context) (int, error) { return 3, nil },
+		})
+	}
+
+	// e.g. two containers built in one process
+	for i := 0; i < 2; i++ {
+		handler, err := Handler(entities())
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		for _, want := range []string{`filmoteka_entities{kind="movies"} 3`, "go_goroutines"} {
+			if !strings.Contains(rec.Body.String(), want) {
+				t.Errorf("%s is not served", want)
+			}
+		}
+	}
+
+	if _, err := Handler(entities(), entities()); err == nil {
+		t.Errorf("expected error but nothing got")
+	}
+}
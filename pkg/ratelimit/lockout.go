@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type account struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// Lockout locks an account out after repeated failed logins. Every failure past the
+// threshold doubles the lock, up to maxDuration; failures are forgotten after a
+// successful login or maxDuration without failures.
+type Lockout struct {
+	threshold   int
+	duration    time.Duration
+	maxDuration time.Duration
+	now         func() time.Time
+
+	mu        sync.Mutex
+	accounts  map[string]*account
+	lastSweep time.Time
+}
+
+// NewLockout returns a lockout after threshold failures, nil for a threshold that
+// locks nothing.
+func NewLockout(threshold int, duration, maxDuration time.Duration) *Lockout {
+	if threshold <= 0 || duration <= 0 {
+		return nil
+	}
+	if maxDuration < duration {
+		maxDuration = duration
+	}
+
+	return &Lockout{
+		threshold:   threshold,
+		duration:    duration,
+		maxDuration: maxDuration,
+		now:         time.Now,
+		accounts:    make(map[string]*account),
+	}
+}
+
+// Locked returns the time left until the account can log in again.
+func (l *Lockout) Locked(username string) (time.Duration, bool) {
+	if l == nil {
+		return 0, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, ok := l.accounts[username]
+	if !ok {
+		return 0, false
+	}
+	left := a.lockedUntil.Sub(l.now())
+
+	return left, left > 0
+}
+
+// Fail records a failed login of the account.
+func (l *Lockout) Fail(username string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	a, ok := l.accounts[username]
+	if !ok || l.expired(a, now) {
+		a = &account{}
+		l.accounts[username] = a
+	}
+	a.failures++
+	a.lastFailure = now
+
+	if over := a.failures - l.threshold; over >= 0 {
+		lock := l.duration
+		for i := 0; i < over && lock < l.maxDuration; i++ {
+			lock *= 2
+		}
+		a.lockedUntil = now.Add(min(lock, l.maxDuration))
+	}
+}
+
+// Succeed forgets the failed logins of the account.
+func (l *Lockout) Succeed(username string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.accounts, username)
+}
+
+func (l *Lockout) expired(a *account, now time.Time) bool {
+	return now.After(a.lockedUntil) && now.Sub(a.lastFailure) >= l.maxDuration
+}
+
+// sweep forgets the expired accounts, once in maxDuration.
+func (l *Lockout) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.maxDuration {
+		return
+	}
+	l.lastSweep = now
+
+	for username, a := range l.accounts {
+		if l.expired(a, now) {
+			delete(l.accounts, username)
+		}
+	}
+}
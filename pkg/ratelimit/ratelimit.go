@@ -0,0 +1,141 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var units = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+}
+
+// Rate is the number of requests allowed per period. A client may spend all of them at
+// once, then they come back evenly over the period.
+type Rate struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseRate parses a rate like "20/s", "10/m" or "100/h". An empty string or "0" is
+// a rate that limits nothing.
+func ParseRate(s string) (Rate, error) {
+	if s == "" || s == "0" {
+		return Rate{}, nil
+	}
+
+	requests, unit, ok := strings.Cut(s, "/")
+	n, err := strconv.Atoi(requests)
+	per, known := units[unit]
+	if !ok || err != nil || n < 0 || !known {
+		return Rate{}, fmt.Errorf("invalid rate %q, expected requests/s, /m or /h", s)
+	}
+
+	return Rate{Requests: n, Per: per}, nil
+}
+
+// Enabled reports whether the rate limits anything.
+func (r Rate) Enabled() bool {
+	return r.Requests > 0 && r.Per > 0
+}
+
+// Result is the state of the bucket of a key after a request.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, zero for allowed requests
+	RetryAfter time.Duration
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Limiter keeps a token bucket per key, e.g. per client IP.
+type Limiter struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	rate      Rate
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewLimiter returns a limiter of rate. A limiter of a rate that limits nothing allows
+// every request until SetRate turns it on.
+func NewLimiter(rate Rate) *Limiter {
+	return &Limiter{rate: rate, now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+// SetRate changes the rate of the limiter, e.g. on a config reload. The buckets are
+// dropped, so every key starts with the burst of the new rate.
+func (l *Limiter) SetRate(rate Rate) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rate = rate
+	l.buckets = make(map[string]*bucket)
+}
+
+// Allow takes a token from the bucket of key. With a rate that limits nothing the
+// request is allowed and the result has a zero Limit.
+func (l *Limiter) Allow(key string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.rate.Enabled() {
+		return Result{Allowed: true}
+	}
+	burst := float64(l.rate.Requests)
+	perSecond := burst / l.rate.Per.Seconds()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.updated).Seconds()*perSecond)
+	b.updated = now
+
+	result := Result{Limit: l.rate.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / perSecond)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((burst - b.tokens) / perSecond)
+
+	return result
+}
+
+// sweep forgets the buckets that are full again, once in a period.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.rate.Per {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= l.rate.Per {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,162 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func TestParseRate(t *testing.T) {
+	for _, test := range []struct {
+		In      string
+		Want    Rate
+		WantErr bool
+	}{
+		{In: "20/s", Want: Rate{Requests: 20, Per: time.Second}},
+		{In: "10/m", Want: Rate{Requests: 10, Per: time.Minute}},
+		{In: "100/h", Want: Rate{Requests: 100, Per: time.Hour}},
+		{In: "", Want: Rate{}},
+		{In: "0", Want: Rate{}},
+		{In: "20", WantErr: true},
+		{In: "20/d", WantErr: true},
+		{In: "-1/s", WantErr: true},
+		{In: "many/s", WantErr: true},
+	} {
+		got, err := ParseRate(test.In)
+		if (err != nil) != test.WantErr {
+			t.Errorf("%q: unexpected error: %v", test.In, err)
+			continue
+		}
+		if got != test.Want {
+			t.Errorf("%q: wrong rate. Expected %+v but got %+v", test.In, test.Want, got)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	c := &clock{now: time.Unix(0, 0)}
+	limiter := NewLimiter(Rate{Requests: 2, Per: time.Second})
+	limiter.now = c.Now
+
+	for i, want := range []Result{
+		{Allowed: true, Limit: 2, Remaining: 1, Reset: 500 * time.Millisecond},
+		{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Second},
+		{Allowed: false, Limit: 2, Remaining: 0, Reset: time.Second, RetryAfter: 500 * time.Millisecond},
+	} {
+		if got := limiter.Allow("a"); got != want {
+			t.Fatalf("request %d: expected %+v but got %+v", i, want, got)
+		}
+	}
+
+	if got := limiter.Allow("b"); !got.Allowed {
+		t.Fatalf("keys share a bucket")
+	}
+
+	c.now = c.now.Add(500 * time.Millisecond)
+	if got := limiter.Allow("a"); !got.Allowed {
+		t.Fatalf("token is not refilled: %+v", got)
+	}
+	if got := limiter.Allow("a"); got.Allowed {
+		t.Fatalf("more tokens than refilled: %+v", got)
+	}
+
+	c.now = c.now.Add(time.Hour)
+	limiter.Allow("a")
+	if _, ok := limiter.buckets["b"]; ok {
+		t.Errorf("full bucket is not swept")
+	}
+}
+
+func TestLimiterSetRate(t *testing.T) {
+	c := &clock{now: time.Unix(0, 0)}
+	limiter := NewLimiter(Rate{})
+	limiter.now = c.Now
+
+	for i := 0; i < 10; i++ {
+		if got := limiter.Allow("a"); got != (Result{Allowed: true}) {
+			t.Fatalf("request %d is limited by a zero rate: %+v", i, got)
+		}
+	}
+
+	limiter.SetRate(Rate{Requests: 1, Per: time.Minute})
+	if got := limiter.Allow("a"); !got.Allowed || got.Limit != 1 {
+		t.Fatalf("wrong result of the first request. Expected an allowed one of limit 1 but got %+v", got)
+	}
+	if got := limiter.Allow("a"); got.Allowed {
+		t.Fatalf("request over the new rate is allowed: %+v", got)
+	}
+
+	limiter.SetRate(Rate{Requests: 2, Per: time.Minute})
+	if got := limiter.Allow("a"); !got.Allowed || got.Remaining != 1 {
+		t.Fatalf("bucket is not refilled to the new rate: %+v", got)
+	}
+
+	limiter.SetRate(Rate{})
+	if got := limiter.Allow("a"); !got.Allowed || got.Limit != 0 {
+		t.Fatalf("request is limited after the rate is turned off: %+v", got)
+	}
+}
+
+func TestLockout(t *testing.T) {
+	c := &clock{now: time.Unix(0, 0)}
+	lockout := NewLockout(3, time.Minute, 5*time.Minute)
+	lockout.now = c.Now
+
+	for i := 0; i < 2; i++ {
+		lockout.Fail("admin")
+	}
+	if _, locked := lockout.Locked("admin"); locked {
+		t.Fatalf("account is locked before the threshold")
+	}
+
+	for _, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute} {
+		lockout.Fail("admin")
+		left, locked := lockout.Locked("admin")
+		if !locked || left != want {
+			t.Fatalf("wrong lock. Expected %s but got %s (locked %t)", want, left, locked)
+		}
+	}
+	if _, locked := lockout.Locked("user"); locked {
+		t.Fatalf("other account is locked")
+	}
+
+	c.now = c.now.Add(5 * time.Minute)
+	if _, locked := lockout.Locked("admin"); locked {
+		t.Fatalf("lock is not lifted")
+	}
+
+	lockout.Fail("admin")
+	if _, locked := lockout.Locked("admin"); !locked {
+		t.Fatalf("failures are forgotten before maxDuration")
+	}
+
+	lockout.Succeed("admin")
+	if _, locked := lockout.Locked("admin"); locked {
+		t.Fatalf("failures are not forgotten after a successful login")
+	}
+
+	for i := 0; i < 2; i++ {
+		lockout.Fail("admin")
+	}
+	c.now = c.now.Add(10 * time.Minute)
+	lockout.Fail("admin")
+	if _, locked := lockout.Locked("admin"); locked {
+		t.Errorf("old failures are not forgotten")
+	}
+}
+
+func TestLockoutDisabled(t *testing.T) {
+	lockout := NewLockout(0, time.Minute, time.Hour)
+
+	lockout.Fail("admin")
+	if _, locked := lockout.Locked("admin"); locked {
+		t.Errorf("disabled lockout locks accounts")
+	}
+}
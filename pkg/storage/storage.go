@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// Storage keeps objects, like uploaded images, by key. Keys are flat names like
+// "3f2a9c.png", without directories.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get returns ErrNotFound for a missing object. The caller closes the body.
+	Get(ctx context.Context, key string) (Object, error)
+	// Delete removes the object, a missing one is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+// CheckKey fails keys that are empty, hidden or would reach out of the storage.
+func CheckKey(key string) error {
+	if key == "" || strings.HasPrefix(key, ".") || strings.ContainsAny(key, `/\`) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is a local stand-in for an S3 bucket: PUT, GET and DELETE of objects by path.
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string]fakeObject
+}
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+f.bucket+"/")
+	if !ok || key == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := readPayload(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = fakeObject{data: data, contentType: r.Header.Get("Content-Type")}
+		w.Header().Set("ETag", etag(data))
+	case http.MethodGet, http.MethodHead:
+		object, ok := f.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, "<Error><Code>NoSuchKey</Code><Key>%s</Key></Error>", key)
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.data)))
+		w.Header().Set("ETag", etag(object.data))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		if r.Method == http.MethodGet {
+			w.Write(object.data) //nolint
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// readPayload reads the body of a PUT, decoding the signed chunks sent over plain HTTP.
+func readPayload(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+
+	var data []byte
+	reader := bufio.NewReader(r.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return data, nil
+		}
+
+		chunk := make([]byte, size+2) // with the trailing \r\n
+		if _, err := io.ReadFull(reader, chunk); err != nil {
+			return nil, err
+		}
+		data = append(data, chunk[:size]...)
+	}
+}
+
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func TestStorage(t *testing.T) {
+	local, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	server := httptest.NewServer(&fakeS3{bucket: "images", objects: make(map[string]fakeObject)})
+	defer server.Close()
+
+	s3, err := NewS3(S3Options{
+		Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+		Bucket:    "images",
+		AccessKey: "access",
+		SecretKey: "secret",
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	for name, storage := range map[string]Storage{"local": local, "s3": s3} {
+		t.Run(name, func(t *testing.T) {
+			testStorage(t, storage)
+		})
+	}
+}
+
+func testStorage(t *testing.T, storage Storage) {
+	ctx := context.Background()
+	data := []byte("\x89PNG\r\n\x1a\nnot really a picture")
+
+	if err := storage.Put(ctx, "poster.png", bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	object, err := storage.Get(ctx, "poster.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	got, err := io.ReadAll(object.Body)
+	object.Body.Close() //nolint
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("wrong data. Expected %q but got %q", data, got)
+	}
+	if object.ContentType != "image/png" {
+		t.Errorf("wrong content type. Expected image/png but got %s", object.ContentType)
+	}
+	if object.Size != int64(len(data)) {
+		t.Errorf("wrong size. Expected %d but got %d", len(data), object.Size)
+	}
+
+	if err := storage.Delete(ctx, "poster.png"); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if _, err := storage.Get(ctx, "poster.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("wrong error of a deleted object. Expected %v but got %v", ErrNotFound, err)
+	}
+	if err := storage.Delete(ctx, "poster.png"); err != nil {
+		t.Errorf("unexpected error deleting a missing object: %s", err.Error())
+	}
+
+	for _, key := range []string{"", "../config.yaml", "dir/poster.png", ".hidden"} {
+		if _, err := storage.Get(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("wrong error of key %q. Expected %v but got %v", key, ErrInvalidKey, err)
+		}
+	}
+}
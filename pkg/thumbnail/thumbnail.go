@@ -0,0 +1,135 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	JPEG = "image/jpeg"
+	PNG  = "image/png"
+	GIF  = "image/gif"
+)
+
+var ErrUnsupported = errors.New("unsupported image type")
+
+// Size is a box of Width by Width pixels the image is scaled down to fit.
+type Size struct {
+	Name  string
+	Width int
+}
+
+// Sizes are the thumbnails made of every uploaded image.
+var Sizes = []Size{
+	{Name: "small", Width: 160},
+	{Name: "medium", Width: 480},
+	{Name: "large", Width: 1024},
+}
+
+// Image is an uploaded image checked to be JPEG, PNG or GIF.
+type Image struct {
+	image.Image
+	ContentType string
+}
+
+// Decode sniffs the content type of data, whatever the client claimed, and decodes
+// the image. Types other than JPEG, PNG and GIF are ErrUnsupported, and so are images
+// of more than maxPixels pixels, checked by their header before any pixel is decoded.
+func Decode(data []byte, maxPixels int) (Image, error) {
+	contentType := http.DetectContentType(data)
+
+	var (
+		decodeConfig func(io.Reader) (image.Config, error)
+		decode       func(io.Reader) (image.Image, error)
+	)
+	switch contentType {
+	case JPEG:
+		decodeConfig, decode = jpeg.DecodeConfig, jpeg.Decode
+	case PNG:
+		decodeConfig, decode = png.DecodeConfig, png.Decode
+	case GIF:
+		// the first frame of an animation
+		decodeConfig, decode = gif.DecodeConfig, gif.Decode
+	default:
+		return Image{}, ErrUnsupported
+	}
+
+	cfg, err := decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, err
+	}
+	if pixels := int64(cfg.Width) * int64(cfg.Height); pixels > int64(maxPixels) {
+		return Image{}, fmt.Errorf("%w: %dx%d is more than %d pixels", ErrUnsupported, cfg.Width, cfg.Height, maxPixels)
+	}
+
+	img, err := decode(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, err
+	}
+
+	return Image{Image: img, ContentType: contentType}, nil
+}
+
+// Make scales img down to fit size, keeping the aspect ratio. Smaller images are
+// not scaled up.
+func Make(img image.Image, size Size) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size.Width && height <= size.Width {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*size.Width/width)
+		width = size.Width
+	} else {
+		width = max(1, width*size.Width/height)
+		height = size.Width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+
+	return dst
+}
+
+// Encode writes a thumbnail of an image of contentType: JPEG stays JPEG, the others
+// become PNG to keep transparency. It returns the content type written.
+func Encode(img image.Image, contentType string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if contentType == JPEG {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), JPEG, nil
+	}
+
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), PNG, nil
+}
+
+// Extension returns the file extension of contentType.
+func Extension(contentType string) string {
+	switch contentType {
+	case JPEG:
+		return ".jpg"
+	case PNG:
+		return ".png"
+	case GIF:
+		return ".gif"
+	default:
+		return ""
+	}
+}
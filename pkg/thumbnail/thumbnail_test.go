@@ -0,0 +1,136 @@
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func picture(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	return img
+}
+
+// pngHeader is a PNG of width by height pixels cut off after its header, decoding its
+// pixels would allocate them all before finding out there is no data.
+func pngHeader(width, height uint32) []byte {
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], width)
+	binary.BigEndian.PutUint32(ihdr[4:], height)
+	// 8 bit RGBA
+	ihdr[8], ihdr[9] = 8, 6
+
+	chunk := append([]byte("IHDR"), ihdr...)
+	data := []byte("\x89PNG\r\n\x1a\n")
+	data = binary.BigEndian.AppendUint32(data, uint32(len(ihdr)))
+	data = append(data, chunk...)
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(chunk))
+}
+
+func TestDecode(t *testing.T) {
+	img := picture(40, 30)
+
+	var jpegData, pngData, gifData bytes.Buffer
+	if err := jpeg.Encode(&jpegData, img, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := png.Encode(&pngData, img); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := gif.Encode(&gifData, img, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	for _, test := range []struct {
+		Name        string
+		Data        []byte
+		MaxPixels   int
+		ContentType string
+		WantErr     bool
+		Err         error
+	}{
+		{Name: "JPEG", Data: jpegData.Bytes(), MaxPixels: 1200, ContentType: JPEG},
+		{Name: "PNG", Data: pngData.Bytes(), MaxPixels: 1200, ContentType: PNG},
+		{Name: "GIF", Data: gifData.Bytes(), MaxPixels: 1200, ContentType: GIF},
+		{Name: "SVG", Data: []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"), MaxPixels: 1200, WantErr: true, Err: ErrUnsupported},
+		{Name: "Broken PNG", Data: pngData.Bytes()[:64], MaxPixels: 1200, WantErr: true},
+		{Name: "Too many pixels", Data: pngData.Bytes(), MaxPixels: 1199, WantErr: true, Err: ErrUnsupported},
+		{Name: "Oversized PNG header", Data: pngHeader(40000, 40000), MaxPixels: 25_000_000, WantErr: true, Err: ErrUnsupported},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			decoded, err := Decode(test.Data, test.MaxPixels)
+			if err != nil {
+				if !test.WantErr {
+					t.Errorf("unexpected error: %s", err.Error())
+				}
+				if test.Err != nil && !errors.Is(err, test.Err) {
+					t.Errorf("wrong error. Expected %v but got %v", test.Err, err)
+				}
+				return
+			}
+			if test.WantErr {
+				t.Errorf("expected error but nothing got")
+			}
+			if decoded.ContentType != test.ContentType {
+				t.Errorf("wrong content type. Expected %s but got %s", test.ContentType, decoded.ContentType)
+			}
+			if decoded.Bounds().Dx() != 40 || decoded.Bounds().Dy() != 30 {
+				t.Errorf("wrong size. Expected 40x30 but got %v", decoded.Bounds())
+			}
+		})
+	}
+}
+
+func TestMake(t *testing.T) {
+	for _, test := range []struct {
+		Width, Height int
+		Size          Size
+		WantWidth     int
+		WantHeight    int
+	}{
+		{Width: 2000, Height: 1000, Size: Size{Width: 160}, WantWidth: 160, WantHeight: 80},
+		{Width: 600, Height: 900, Size: Size{Width: 480}, WantWidth: 320, WantHeight: 480},
+		{Width: 100, Height: 50, Size: Size{Width: 160}, WantWidth: 100, WantHeight: 50},
+	} {
+		got := Make(picture(test.Width, test.Height), test.Size).Bounds()
+		if got.Dx() != test.WantWidth || got.Dy() != test.WantHeight {
+			t.Errorf("wrong size of %dx%d in %d. Expected %dx%d but got %dx%d",
+				test.Width, test.Height, test.Size.Width, test.WantWidth, test.WantHeight, got.Dx(), got.Dy())
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	for _, test := range []struct {
+		ContentType string
+		Want        string
+	}{
+		{ContentType: JPEG, Want: JPEG},
+		{ContentType: PNG, Want: PNG},
+		{ContentType: GIF, Want: PNG},
+	} {
+		data, contentType, err := Encode(picture(10, 10), test.ContentType)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if contentType != test.Want {
+			t.Errorf("wrong content type of %s thumbnail. Expected %s but got %s", test.ContentType, test.Want, contentType)
+		}
+		if decoded, err := Decode(data, 100); err != nil || decoded.ContentType != test.Want {
+			t.Errorf("thumbnail of %s is not %s: %v", test.ContentType, test.Want, err)
+		}
+	}
+}
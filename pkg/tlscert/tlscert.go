@@ -0,0 +1,48 @@
+package tlscert
+
+import (
+	"crypto/tls"
+	"sync/atomic"
+)
+
+// Reloader serves a certificate that can be replaced while the server is running,
+// e.g. after it was renewed on disk.
+type Reloader struct {
+	certFile string
+	keyFile  string
+	cert     atomic.Pointer[tls.Certificate]
+}
+
+// New loads the key pair from the PEM files.
+func New(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload reads the key pair again. The previous certificate is kept when the files are broken.
+func (r *Reloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.cert.Store(&cert)
+
+	return nil
+}
+
+// GetCertificate is meant for tls.Config, new connections get the last loaded certificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// Config returns the server TLS config using the reloaded certificate.
+func (r *Reloader) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+	}
+}
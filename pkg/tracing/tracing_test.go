@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+func TestQueryTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	ctx, parent := Start(context.Background(), "parent")
+
+	tracer := QueryTracer{}
+	const sql = "\n\t\t\tSELECT id FROM movies WHERE id = $1"
+	queryCtx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: sql, Args: []any{1}})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{Err: pgx.ErrNoRows})
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("wrong number of spans. Expected 2 but got %d", len(spans))
+	}
+
+	query := spans[0]
+	if query.Name() != "db SELECT" {
+		t.Errorf("wrong span name. Expected %q but got %q", "db SELECT", query.Name())
+	}
+	if query.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("query span is not a child of the parent span")
+	}
+	if query.Status().Code != codes.Error || query.Status().Description != pgx.ErrNoRows.Error() {
+		t.Errorf("wrong span status %v", query.Status())
+	}
+
+	var statement string
+	for _, attr := range query.Attributes() {
+		if attr.Key == semconv.DBStatementKey {
+			statement = attr.Value.AsString()
+		}
+	}
+	if statement != sql {
+		t.Errorf("wrong statement. Expected %q but got %q", sql, statement)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{Exporter: ExporterNone})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	_, err = Setup(context.Background(), Options{Exporter: "jaeger"})
+	if err == nil || err.Error() != "invalid tracing exporter: jaeger" {
+		t.Fatalf("expected error for an unknown exporter, got %v", err)
+	}
+}
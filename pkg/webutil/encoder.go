@@ -0,0 +1,406 @@
+package webutil
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"vk-test-task/internal/core"
+	"vk-test-task/pkg/logger"
+	"vk-test-task/pkg/web"
+)
+
+const (
+	JSONMediaType = "application/json"
+	XMLMediaType  = "application/xml"
+	CSVMediaType  = "text/csv"
+)
+
+type (
+	// Encoder writes a response envelope in one representation,
+	// including the Content-Type header and the status code.
+	Encoder interface {
+		Encode(w http.ResponseWriter, code int, resp web.Response) error
+	}
+
+	JSONEncoder struct{}
+
+	// XMLEncoder writes the envelope as elements named after its JSON fields,
+	// list items become <item> elements.
+	XMLEncoder struct{}
+
+	// CSVEncoder writes the data of the envelope as a table with a header row,
+	// one row per list item. Pagination is sent in X-Total-Count, X-Page-Count,
+	// X-Current-Page and X-Per-Page headers.
+	CSVEncoder struct{}
+
+	registeredEncoder struct {
+		mediaType string
+		encoder   Encoder
+	}
+
+	acceptedType struct {
+		mediaType string
+		quality   float64
+	}
+)
+
+// encoders are tried in registration order, the first one is used
+// when the client accepts anything.
+var encoders = []registeredEncoder{
+	{JSONMediaType, JSONEncoder{}},
+	{XMLMediaType, XMLEncoder{}},
+	{"text/xml", XMLEncoder{}},
+	{CSVMediaType, CSVEncoder{}},
+}
+
+// RegisterEncoder adds a representation for responses sent with SendResponse.
+// It is not safe to call concurrently with requests, register encoders on start.
+func RegisterEncoder(mediaType string, encoder Encoder) {
+	for i, registered := range encoders {
+		if registered.mediaType == mediaType {
+			encoders[i].encoder = encoder
+			return
+		}
+	}
+
+	encoders = append(encoders, registeredEncoder{mediaType: mediaType, encoder: encoder})
+}
+
+// NegotiateEncoder picks the encoder for an Accept header value. Types accepted with
+// q=0 are excluded, e.g. application/json;q=0 keeps JSON out of */*.
+func NegotiateEncoder(accept string) (Encoder, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return encoders[0].encoder, true
+	}
+
+	types := parseAccept(accept)
+	for _, accepted := range types {
+		if accepted.quality <= 0 {
+			continue
+		}
+		for _, registered := range encoders {
+			if mediaTypeMatches(accepted.mediaType, registered.mediaType) && !excluded(types, registered.mediaType) {
+				return registered.encoder, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// AcceptChecker negotiates the response representation and answers 406 when
+// none of the accepted types is supported. Responses vary by Accept either way.
+func AcceptChecker(w http.ResponseWriter, r *http.Request) (Encoder, bool) {
+	w.Header().Add("Vary", "Accept")
+
+	encoder, ok := NegotiateEncoder(r.Header.Get("Accept"))
+	if !ok {
+		SendJSONResponse(w, http.StatusNotAcceptable, web.ErrorResponse(core.NotAcceptableCode, nil, nil))
+		return nil, false
+	}
+
+	return encoder, true
+}
+
+func SendResponse(w http.ResponseWriter, encoder Encoder, code int, resp web.Response) {
+	if err := encoder.Encode(w, code, resp); err != nil {
+		logger.Log.Error("encode response", "error", err.Error())
+	}
+}
+
+func (JSONEncoder) Encode(w http.ResponseWriter, code int, resp web.Response) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", JSONMediaType)
+	w.WriteHeader(code)
+	_, err = w.Write(data)
+
+	return err
+}
+
+func (XMLEncoder) Encode(w http.ResponseWriter, code int, resp web.Response) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	encoder := xml.NewEncoder(&buf)
+	if err := writeXMLValue(encoder, decoder, "response"); err != nil {
+		return err
+	}
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", XMLMediaType+"; charset=utf-8")
+	w.WriteHeader(code)
+	_, err = w.Write(buf.Bytes())
+
+	return err
+}
+
+func (CSVEncoder) Encode(w http.ResponseWriter, code int, resp web.Response) error {
+	header, rows, err := csvTable(resp.Data)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if len(header) != 0 {
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+
+	if pagination, ok := resp.Meta.(web.PaginationBody); ok {
+		w.Header().Set("X-Total-Count", strconv.Itoa(pagination.TotalCount))
+		w.Header().Set("X-Page-Count", strconv.Itoa(pagination.PageCount))
+		w.Header().Set("X-Current-Page", strconv.Itoa(pagination.CurrentPage))
+		w.Header().Set("X-Per-Page", strconv.Itoa(pagination.PerPage))
+	}
+	w.Header().Set("Content-Type", CSVMediaType+"; charset=utf-8")
+	w.WriteHeader(code)
+	_, err = w.Write(buf.Bytes())
+
+	return err
+}
+
+// writeXMLValue converts the next JSON value of decoder to an element,
+// keeping the order of object fields.
+func writeXMLValue(encoder *xml.Encoder, decoder *json.Decoder, name string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		for decoder.More() {
+			child := "item"
+			if value == '{' {
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				child = key.(string)
+			}
+			if err := writeXMLValue(encoder, decoder, child); err != nil {
+				return err
+			}
+		}
+		// closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		if err := encoder.EncodeToken(xml.CharData(fmt.Sprint(value))); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
+
+// csvTable flattens a list of objects (or a single object) to rows. Nested
+// values are written as JSON, null as an empty cell.
+func csvTable(data any) ([]string, [][]string, error) {
+	if data == nil {
+		return nil, nil, nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := csvHeader(reflect.TypeOf(data))
+
+	var items []json.RawMessage
+	switch {
+	case string(raw) == "null":
+		return header, nil, nil
+	case raw[0] == '[':
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, nil, err
+		}
+	default:
+		items = []json.RawMessage{raw}
+	}
+
+	var rows [][]string
+	for _, item := range items {
+		keys, values, err := orderedFields(item)
+		if err != nil {
+			return nil, nil, err
+		}
+		if header == nil {
+			header = keys
+		}
+
+		row := make([]string, len(header))
+		for i, key := range header {
+			row[i] = csvCell(values[key])
+		}
+		rows = append(rows, row)
+	}
+
+	return header, rows, nil
+}
+
+// csvHeader returns json field names of a struct or a slice of structs,
+// so that an empty list still gets a header.
+func csvHeader(t reflect.Type) []string {
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var header []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		header = append(header, name)
+	}
+
+	return header
+}
+
+func orderedFields(item json.RawMessage) ([]string, map[string]json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(item))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if token != json.Delim('{') {
+		return []string{"value"}, map[string]json.RawMessage{"value": item}, nil
+	}
+
+	var keys []string
+	values := map[string]json.RawMessage{}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+
+		keys = append(keys, key.(string))
+		values[key.(string)] = value
+	}
+
+	return keys, values, nil
+}
+
+func csvCell(value json.RawMessage) string {
+	if len(value) == 0 || string(value) == "null" {
+		return ""
+	}
+
+	var str string
+	if value[0] == '"' && json.Unmarshal(value, &str) == nil {
+		return str
+	}
+
+	return string(value)
+}
+
+func parseAccept(accept string) []acceptedType {
+	var types []acceptedType
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		types = append(types, acceptedType{mediaType: mediaType, quality: quality})
+	}
+
+	sort.SliceStable(types, func(i, j int) bool {
+		return types[i].quality > types[j].quality
+	})
+
+	return types
+}
+
+// excluded reports whether the most specific of the accepted types matching mediaType
+// has q=0.
+func excluded(types []acceptedType, mediaType string) bool {
+	specificity, quality := -1, 0.0
+	for _, accepted := range types {
+		if !mediaTypeMatches(accepted.mediaType, mediaType) {
+			continue
+		}
+
+		s := 0
+		switch {
+		case accepted.mediaType == mediaType:
+			s = 2
+		case accepted.mediaType != "*/*":
+			s = 1
+		}
+		if s > specificity {
+			specificity, quality = s, accepted.quality
+		}
+	}
+
+	return specificity >= 0 && quality <= 0
+}
+
+func mediaTypeMatches(accepted, mediaType string) bool {
+	if accepted == "*/*" || accepted == mediaType {
+		return true
+	}
+
+	prefix, ok := strings.CutSuffix(accepted, "/*")
+	return ok && strings.HasPrefix(mediaType, prefix+"/")
+}
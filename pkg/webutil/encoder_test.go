@@ -0,0 +1,120 @@
+package webutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"vk-test-task/pkg/web"
+)
+
+type testMovie struct {
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	ReleaseDate time.Time  `json:"release_date"`
+	StarsID     []int      `json:"stars_id"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+}
+
+func TestNegotiateEncoder(t *testing.T) {
+	for _, test := range []struct {
+		Name    string
+		Accept  string
+		Encoder Encoder
+	}{
+		{Name: "No Accept header", Accept: "", Encoder: JSONEncoder{}},
+		{Name: "Any type", Accept: "*/*", Encoder: JSONEncoder{}},
+		{Name: "CSV", Accept: "text/csv", Encoder: CSVEncoder{}},
+		{Name: "XML with charset", Accept: "application/xml; charset=utf-8", Encoder: XMLEncoder{}},
+		{Name: "Preferred by quality", Accept: "application/json;q=0.5, text/csv", Encoder: CSVEncoder{}},
+		{Name: "Type wildcard", Accept: "text/*", Encoder: XMLEncoder{}},
+		{Name: "Unsupported type", Accept: "application/pdf"},
+		{Name: "Refused type", Accept: "text/csv;q=0"},
+		{Name: "Excluded type", Accept: "application/json;q=0, */*", Encoder: XMLEncoder{}},
+		{Name: "Excluded from wildcard", Accept: "text/*, text/xml;q=0", Encoder: CSVEncoder{}},
+		{Name: "Included over excluded wildcard", Accept: "text/*;q=0, text/csv", Encoder: CSVEncoder{}},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			encoder, ok := NegotiateEncoder(test.Accept)
+			if test.Encoder == nil {
+				if ok {
+					t.Errorf("expected no encoder but got %T", encoder)
+				}
+				return
+			}
+			if encoder != test.Encoder {
+				t.Errorf("wrong encoder. Expected %T but got %T", test.Encoder, encoder)
+			}
+		})
+	}
+}
+
+func TestAcceptCheckerVary(t *testing.T) {
+	for _, accept := range []string{"text/csv", "application/pdf"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		AcceptChecker(rec, req)
+
+		if got := rec.Header().Get("Vary"); got != "Accept" {
+			t.Errorf("%s: wrong Vary. Expected Accept but got %q", accept, got)
+		}
+	}
+}
+
+func TestEncoders(t *testing.T) {
+	movies := []testMovie{
+		{ID: 1, Title: "Drive, 2011", ReleaseDate: time.Date(2011, time.November, 3, 0, 0, 0, 0, time.UTC), StarsID: []int{1, 2}},
+		{ID: 2, Title: "Dune & Co", ReleaseDate: time.Date(2021, time.September, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	pagination := web.PaginationBody{TotalCount: 2, PageCount: 1, CurrentPage: 1, PerPage: 20}
+
+	for _, test := range []struct {
+		Name        string
+		Encoder     Encoder
+		Data        any
+		ContentType string
+		Body        string
+	}{
+		{
+			Name:        "CSV list",
+			Encoder:     CSVEncoder{},
+			Data:        movies,
+			ContentType: "text/csv; charset=utf-8",
+			Body: "id,title,release_date,stars_id,deleted_at\n" +
+				"1,\"Drive, 2011\",2011-11-03T00:00:00Z,\"[1,2]\",\n" +
+				"2,Dune & Co,2021-09-03T00:00:00Z,,\n",
+		},
+		{
+			Name:        "CSV empty list",
+			Encoder:     CSVEncoder{},
+			Data:        []testMovie(nil),
+			ContentType: "text/csv; charset=utf-8",
+			Body:        "id,title,release_date,stars_id,deleted_at\n",
+		},
+		{
+			Name:        "XML list",
+			Encoder:     XMLEncoder{},
+			Data:        movies[1:],
+			ContentType: "application/xml; charset=utf-8",
+			Body: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<response><status>OK</status><msg_code>movies_received</msg_code>` +
+				`<data><item><id>2</id><title>Dune &amp; Co</title><release_date>2021-09-03T00:00:00Z</release_date><stars_id></stars_id><deleted_at></deleted_at></item></data>` +
+				`<_meta><total_count>2</total_count><page_count>1</page_count><current_page>1</current_page><per_page>20</per_page></_meta></response>`,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			SendResponse(recorder, test.Encoder, 200, web.OKResponse("movies_received", test.Data, pagination))
+
+			if got := recorder.Header().Get("Content-Type"); got != test.ContentType {
+				t.Errorf("wrong content type. Expected %q but got %q", test.ContentType, got)
+			}
+			if got := recorder.Body.String(); got != test.Body {
+				t.Errorf("wrong body. Expected\n%s\nbut got\n%s", test.Body, got)
+			}
+		})
+	}
+}
@@ -27,8 +27,11 @@ const (
 )
 
 func (cv *CustomValidator) ValidateSortParams(fl validator.FieldLevel) bool {
-	value := fl.Field().String()
+	return IsSortParam(fl.Field().String())
+}
 
+// IsSortParam reports whether value is an accepted sort query parameter.
+func IsSortParam(value string) bool {
 	switch value {
 	case TitleAsc, TitleDesc, RatingAsc, RatingDesc, ReleaseDateAsc, ReleaseDateDesc, NoParam:
 		return true
@@ -70,3 +73,27 @@ func Validate(w http.ResponseWriter, r *http.Request, entity interface{}) bool {
 
 	return true
 }
+
+// StructErrors validates entity with the same rules as BodyCheck and QueryValidator
+// and returns the failed rules instead of sending a response.
+func StructErrors(entity interface{}) ([]web.ValidationError, error) {
+	structValidator := validator.New()
+	err := structValidator.RegisterValidation("date", (&CustomValidator{structValidator}).ValidateDate)
+	if err != nil {
+		return nil, err
+	}
+	err = structValidator.RegisterValidation("sort_params", (&CustomValidator{structValidator}).ValidateSortParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := structValidator.Struct(entity); err != nil {
+		var verrors validator.ValidationErrors
+		if !errors.As(err, &verrors) {
+			return nil, err
+		}
+		return web.ValidationErrors(verrors), nil
+	}
+
+	return nil, nil
+}
@@ -1,9 +1,14 @@
 package webutil
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -23,6 +28,46 @@ func SendJSONResponse(w http.ResponseWriter, code int, payload interface{}) {
 	w.Write(response) //nolint
 }
 
+// SendServiceError answers an error returned by a service. Queries aborted because the client
+// went away are answered with core.StatusClientClosedRequest, the ones that ran out of the
+// query deadline with 503, any other error with 500.
+func SendServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		logger.Log.InfoContext(r.Context(), "request canceled", "error", err.Error())
+		SendJSONResponse(w, core.StatusClientClosedRequest, web.ErrorResponse(core.RequestCanceledCode, nil, nil))
+	case errors.Is(err, context.DeadlineExceeded):
+		logger.Log.WarnContext(r.Context(), "query timeout", "error", err.Error())
+		SendJSONResponse(w, http.StatusServiceUnavailable, web.ErrorResponse(core.QueryTimeoutCode, nil, nil))
+	default:
+		SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
+	}
+}
+
+// SendBodyError answers a request whose body could not be read or decoded.
+func SendBodyError(w http.ResponseWriter, err error) {
+	if BodyTooLarge(err) {
+		SendJSONResponse(w, http.StatusRequestEntityTooLarge, web.ErrorResponse(core.BodyTooLargeCode, nil, nil))
+		return
+	}
+
+	SendJSONResponse(w, http.StatusBadRequest, web.ErrorResponse(core.InvalidBodyCode, nil, nil))
+}
+
+// BodyTooLarge reports whether err comes from reading past the limit of the request body.
+func BodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// UnmarshalStrict decodes JSON like BodyCheck does, fields unknown to v are an error.
+func UnmarshalStrict(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(v)
+}
+
 func BodyCheck(w http.ResponseWriter, r *http.Request, entity interface{}) bool {
 	if r.Body == nil {
 		SendJSONResponse(w, http.StatusBadRequest, web.ErrorResponse(core.BodyRequiredCode, nil, nil))
@@ -30,14 +75,15 @@ func BodyCheck(w http.ResponseWriter, r *http.Request, entity interface{}) bool
 	}
 
 	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(&entity); err != nil {
-		SendJSONResponse(w, http.StatusBadRequest, web.ErrorResponse(core.InvalidBodyCode, nil, nil))
+		SendBodyError(w, err)
 		return false
 	}
 
 	err := validate.RegisterValidation("date", (&CustomValidator{validate}).ValidateDate)
 	if err != nil {
-		logger.Log.Error("init validator", "error", err.Error())
+		logger.Log.ErrorContext(r.Context(), "init validator", "error", err.Error())
 		SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
 		return false
 	}
@@ -46,7 +92,7 @@ func BodyCheck(w http.ResponseWriter, r *http.Request, entity interface{}) bool
 		var verrors validator.ValidationErrors
 		ok := errors.As(err, &verrors)
 		if !ok {
-			logger.Log.Debug("error validation", "error", err.Error())
+			logger.Log.DebugContext(r.Context(), "error validation", "error", err.Error())
 			SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
 			return false
 		}
@@ -59,7 +105,26 @@ func BodyCheck(w http.ResponseWriter, r *http.Request, entity interface{}) bool
 
 func ParseID(w http.ResponseWriter, r *http.Request) int {
 	parts := strings.Split(r.URL.Path, "/")
-	idStr := parts[len(parts)-1]
+	return parseID(w, parts[len(parts)-1])
+}
+
+// ParseParentID parses the id of paths to the items of an entity, like /movie/{id}/images.
+func ParseParentID(w http.ResponseWriter, r *http.Request) int {
+	return ParseIDAt(w, r, 1)
+}
+
+// ParseIDAt parses the id n segments before the last one of the path: ParseID parses it
+// at 0 and /movie/{id}/translations/{locale} has it at 2.
+func ParseIDAt(w http.ResponseWriter, r *http.Request, n int) int {
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) < n+1 {
+		return parseID(w, "")
+	}
+
+	return parseID(w, parts[len(parts)-1-n])
+}
+
+func parseID(w http.ResponseWriter, idStr string) int {
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		SendJSONResponse(w, http.StatusBadRequest, web.ErrorResponse(core.InvalidIDCode, nil, nil))
@@ -69,22 +134,72 @@ func ParseID(w http.ResponseWriter, r *http.Request) int {
 	return id
 }
 
-func QueryParser(r *http.Request) map[string]string {
-	queries := r.URL.Query()
+// QueryCheck decodes the query into entity and validates it, like BodyCheck does with the body.
+func QueryCheck(w http.ResponseWriter, r *http.Request, entity interface{}) bool {
+	if err := QueryDecode(r, entity); err != nil {
+		logger.Log.ErrorContext(r.Context(), "error decode query", "error", err.Error())
+		SendJSONResponse(w, http.StatusBadRequest, web.ErrorResponse(core.InvalidQueryParamsCode, nil, nil))
+		return false
+	}
+
+	return QueryValidator(w, r, entity)
+}
 
-	m := make(map[string]string, len(queries))
-	for key, value := range queries {
-		m[key] = value[0]
+// QueryDecode sets the fields of entity tagged with `query` to the values of the query,
+// fields of absent parameters keep their values. Embedded structs are decoded as well.
+func QueryDecode(r *http.Request, entity interface{}) error {
+	value := reflect.ValueOf(entity)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode query into %T: pointer to struct expected", entity)
 	}
 
-	return m
+	return decodeQuery(r.URL.Query(), value.Elem())
+}
+
+func decodeQuery(queries url.Values, value reflect.Value) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := decodeQuery(queries, value.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := field.Tag.Get("query")
+		if name == "" || !queries.Has(name) {
+			continue
+		}
+		query := queries.Get(name)
+
+		switch fieldValue := value.Field(i); fieldValue.Kind() {
+		case reflect.String:
+			fieldValue.SetString(query)
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			num, err := strconv.ParseInt(query, 10, 64)
+			if err != nil {
+				return fmt.Errorf("query %s: %w", name, err)
+			}
+			fieldValue.SetInt(num)
+		case reflect.Bool:
+			val, err := strconv.ParseBool(query)
+			if err != nil {
+				return fmt.Errorf("query %s: %w", name, err)
+			}
+			fieldValue.SetBool(val)
+		default:
+			return fmt.Errorf("query %s: unsupported type %s", name, fieldValue.Type())
+		}
+	}
+
+	return nil
 }
 
 func QueryValidator(w http.ResponseWriter, r *http.Request, entity interface{}) bool {
 	validate = validator.New(validator.WithRequiredStructEnabled())
 	err := validate.RegisterValidation("sort_params", (&CustomValidator{validate}).ValidateSortParams)
 	if err != nil {
-		logger.Log.Error("init validator", "error", err.Error())
+		logger.Log.ErrorContext(r.Context(), "init validator", "error", err.Error())
 		SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
 		return false
 	}
@@ -93,7 +208,7 @@ func QueryValidator(w http.ResponseWriter, r *http.Request, entity interface{})
 		var verrors validator.ValidationErrors
 		ok := errors.As(err, &verrors)
 		if !ok {
-			logger.Log.Debug("error validation", "error", err.Error())
+			logger.Log.DebugContext(r.Context(), "error validation", "error", err.Error())
 			SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
 			return false
 		}
@@ -114,7 +229,7 @@ func AuthHeaderChecker(w http.ResponseWriter, r *http.Request) string {
 	validate = validator.New(validator.WithRequiredStructEnabled())
 	err := validate.RegisterValidation("jwt_auth_header", (&CustomValidator{validate}).ValidateAuthHeader)
 	if err != nil {
-		logger.Log.Error("init validator", "error", err.Error())
+		logger.Log.ErrorContext(r.Context(), "init validator", "error", err.Error())
 		SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
 		return ""
 	}
@@ -123,7 +238,7 @@ func AuthHeaderChecker(w http.ResponseWriter, r *http.Request) string {
 		var verrors validator.ValidationErrors
 		ok := errors.As(err, &verrors)
 		if !ok {
-			logger.Log.Debug("error validation", "error", err.Error())
+			logger.Log.DebugContext(r.Context(), "error validation", "error", err.Error())
 			SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
 			return ""
 		}
@@ -135,13 +250,8 @@ func AuthHeaderChecker(w http.ResponseWriter, r *http.Request) string {
 }
 
 func roleCheckerFromCtx(w http.ResponseWriter, r *http.Request) (string, bool) {
-	userRole := r.Context().Value("user_role")
-
-	var role string
-	switch r := userRole.(type) {
-	case string:
-		role = r
-	default:
+	role, ok := RoleFromContext(r.Context())
+	if !ok {
 		SendJSONResponse(w, http.StatusInternalServerError, web.ErrorResponse(core.InternalErrorCode, nil, nil))
 		return "", false
 	}
@@ -149,6 +259,18 @@ func roleCheckerFromCtx(w http.ResponseWriter, r *http.Request) (string, bool) {
 	return role, true
 }
 
+// RoleFromContext returns the role put into the request context by the JWT middleware.
+func RoleFromContext(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value("user_role").(string)
+	return role, ok
+}
+
+// UsernameFromContext returns the username put into the request context by the JWT middleware.
+func UsernameFromContext(ctx context.Context) (string, bool) {
+	username, ok := ctx.Value("username").(string)
+	return username, ok
+}
+
 func AllowedRoleChecker(w http.ResponseWriter, req *http.Request, allowedRoles ...string) bool {
 	role, ok := roleCheckerFromCtx(w, req)
 	if !ok {
@@ -0,0 +1,39 @@
+package webutil
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"vk-test-task/pkg/web"
+)
+
+func TestQueryDecode(t *testing.T) {
+	type query struct {
+		web.PaginationQuery
+		SearchTerm string `query:"q"`
+		Atomic     bool   `query:"atomic"`
+		Format     string `query:"format"`
+		Ignored    string
+	}
+
+	req := httptest.NewRequest("GET", "/?page=2&limit=50&q=drive&atomic=true&Ignored=x", nil)
+	got := query{Format: "csv"}
+	if err := QueryDecode(req, &got); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	want := query{
+		PaginationQuery: web.PaginationQuery{Page: 2, Limit: 50},
+		SearchTerm:      "drive",
+		Atomic:          true,
+		Format:          "csv",
+	}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	req = httptest.NewRequest("GET", "/?page=first", nil)
+	if err := QueryDecode(req, &got); err == nil {
+		t.Errorf("expected error for page that is not a number")
+	}
+}